@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/josejulio/ghautodelete/internal/changelog"
+	"github.com/josejulio/ghautodelete/internal/dates"
+	"github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/output"
+	"github.com/josejulio/ghautodelete/internal/parser"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+	"github.com/spf13/cobra"
+)
+
+const historyDescription = `Show the setting changes recorded in the audit log.
+
+Every setting ghautodelete changes is appended to a local JSON Lines audit log
+with the time, the login the token authenticated as, the repository, the
+setting, its values before and after, the ghautodelete version and the run ID.
+history lists those records, optionally for one repository (--repo) and a
+date range (--since and --until, as YYYY-MM-DD or RFC 3339 timestamps;
+--until includes the whole day). With --output json the records are written
+as JSON Lines.`
+
+const historyExamples = `  # Everything changed on a repository
+  ghautodelete history --repo octocat/hello-world
+
+  # Changes made during March 2024, as JSON Lines
+  ghautodelete history --since 2024-03-01 --until 2024-03-31 --output json`
+
+// newHistoryCmd creates the history command. It shares the root persistent
+// flags through opts for --audit-log and --output.
+func newHistoryCmd(env *environment, opts *interfaces.CLIOptions) *cobra.Command {
+	var repo, since, until string
+
+	cmd := &cobra.Command{
+		Use:     "history [flags]",
+		Short:   "Show the setting changes recorded in the audit log",
+		Long:    historyDescription,
+		Example: historyExamples,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return errors.NewValidationError("history takes no arguments; select a repository with --repo")
+			}
+			filter, err := historyFilter(env, opts.Hostname, repo, since, until)
+			if err != nil {
+				return err
+			}
+			return runHistory(env, *opts, filter)
+		},
+	}
+	cmd.Flags().StringVar(&repo, "repo", "", "Only show changes to this repository")
+	cmd.Flags().StringVar(&since, "since", "", "Only show changes on or after this date")
+	cmd.Flags().StringVar(&until, "until", "", "Only show changes on or before this date")
+
+	return cmd
+}
+
+// historyFilter builds the record filter from the history flags. A --repo URL
+// is accepted on hostname, or on the host it names when none is given.
+func historyFilter(env *environment, hostname, repo, since, until string) (changelog.Filter, error) {
+	var filter changelog.Filter
+	var err error
+
+	if repo != "" {
+		hostname, err := resolveHostname(env, hostname, []string{repo})
+		if err != nil {
+			return filter, err
+		}
+		owner, name, err := parser.NewRepoParserForHostname(hostname).Parse(repo)
+		if err != nil {
+			return filter, err
+		}
+		filter.Repository = owner + "/" + name
+	}
+	if since != "" {
+		if filter.Since, err = dates.Parse(since, false); err != nil {
+			return filter, err
+		}
+	}
+	if until != "" {
+		if filter.Until, err = dates.Parse(until, true); err != nil {
+			return filter, err
+		}
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && filter.Until.Before(filter.Since) {
+		return filter, errors.NewValidationError("--until must not be before --since")
+	}
+	return filter, nil
+}
+
+// runHistory writes the audit log records selected by filter.
+// A missing audit log means no changes have been recorded yet.
+func runHistory(env *environment, opts interfaces.CLIOptions, filter changelog.Filter) error {
+	path := auditLogPath(env, opts)
+	if path == "" {
+		return errors.NewValidationError("No audit log location is available; pass --audit-log")
+	}
+
+	content, err := env.readFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.NewValidationError(fmt.Sprintf("Cannot read audit log %s: %v", path, err))
+	}
+	records, skipped, err := changelog.Parse(content, filter)
+	if err != nil {
+		return err
+	}
+
+	writer := output.NewOutputWriter(false, env.out, env.errOut)
+	switch opts.Output {
+	case "", output.FormatText:
+	case output.FormatJSON:
+		// Keep stdout machine-readable: text messages go to stderr instead
+		writer = output.NewOutputWriter(false, env.errOut, env.errOut)
+	default:
+		return errors.NewValidationError(fmt.Sprintf("Invalid output format %q. Expected %s or %s",
+			opts.Output, output.FormatText, output.FormatJSON))
+	}
+
+	if len(skipped) > 0 {
+		lines := make([]string, len(skipped))
+		for i, lineNumber := range skipped {
+			lines[i] = strconv.Itoa(lineNumber)
+		}
+		writer.Info(fmt.Sprintf("Warning: skipped %d unreadable lines in %s: %s",
+			len(skipped), path, strings.Join(lines, ", ")))
+	}
+
+	if opts.Output == output.FormatJSON {
+		encoder := json.NewEncoder(env.out)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(records) == 0 {
+		writer.Info(fmt.Sprintf("No setting changes recorded in %s", path))
+		return nil
+	}
+
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tREPOSITORY\tSETTING\tCHANGE\tACTOR\tRUN")
+	for _, record := range records {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s -> %s\t%s\t%s\n", record.Timestamp.Format(time.RFC3339),
+			record.Repository, record.Setting, record.Before, record.After, record.Actor, record.RunID)
+	}
+	_ = tw.Flush()
+
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		writer.Info(line)
+	}
+	writer.Info(fmt.Sprintf("%d changes", len(records)))
+	return nil
+}
@@ -0,0 +1,431 @@
+// Package main provides the ghautodelete command-line entrypoint.
+//
+// main is kept minimal: it builds the cobra root command, executes it against the
+// process arguments and maps any returned error onto the documented exit codes
+// using errors.GetExitCode.
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/github"
+	"github.com/josejulio/ghautodelete/internal/output"
+	"github.com/josejulio/ghautodelete/internal/parser"
+	"github.com/josejulio/ghautodelete/internal/token"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+	"github.com/spf13/cobra"
+)
+
+// version is the application version, injected at build time via
+// -ldflags "-X main.version=1.0.0". It defaults to "dev" for local builds.
+var version = "dev"
+
+// defaultTimeout bounds each individual HTTP request.
+const defaultTimeout = 30 * time.Second
+
+// validAffiliations are the values accepted by --affiliation, as defined by GET /user/repos.
+var validAffiliations = map[string]bool{
+	"owner":               true,
+	"collaborator":        true,
+	"organization_member": true,
+}
+
+const longDescription = `Enable auto-delete branches on GitHub repositories.
+
+ghautodelete turns on the "Automatically delete head branches" setting, so
+feature branches are removed as soon as their pull requests are merged.
+
+The repository can be given as owner/repo, as an HTTPS URL or as an SSH URL.
+Several repositories can be passed at once, or listed in a file with
+--from-file (one per line, "#" starts a comment), or every repository of an
+organization can be selected with --org, every repository of the
+authenticated user with --user, every repository matching a GitHub search
+query with --search (at most 1,000 results), or every repository of every
+organization of an enterprise with --enterprise; each is processed and a
+per-repository result table is printed. Listed repositories can be narrowed down with --topic,
+--visibility, --language, --include/--exclude (name globs or /regex/) and
+--pushed-since; --verbose shows why each one was selected or skipped.
+Use the disable command to turn the setting back off, audit
+to fail with exit code 7 when any repository does not have it enabled, and
+plan/apply to manage merge settings declaratively from a ghautodelete.yaml file.
+Every change is recorded in a local audit log; history lists the records.
+snapshot captures the current settings into a file and restore puts them back.
+With --output json, one JSON record per repository is written to stdout and
+all other messages go to stderr.
+Use --concurrency to process several repositories at once.
+Enable and disable runs over several repositories record each outcome in a
+checkpoint file as it completes; after a failed or interrupted run, --resume
+skips the repositories that succeeded and retries the rest.
+The GitHub token is read from the --token flag or, failing that, from the first
+token source that has one: --token-file, --token-command, the GH_TOKEN and
+GITHUB_TOKEN environment variables and the gh CLI configuration
+(~/.config/gh/hosts.yml). Reorder or restrict the sources with --token-sources,
+which can also add git credential helpers (git-credential); --verbose shows
+which source supplied the token.
+
+For GitHub Enterprise Server, pass --hostname (or set GH_HOST), or give the
+repositories as URLs on that host; the host is then detected from the URLs.
+The API is reached at https://<hostname>/api/v3 and the token is read from
+GH_ENTERPRISE_TOKEN, GITHUB_ENTERPRISE_TOKEN or that host's gh configuration.
+
+To act as a GitHub App instead of a user, pass --app-id, --app-installation-id
+and --app-private-key-file. Installation tokens are requested as needed and
+refreshed before they expire.`
+
+const disableDescription = `Turn auto-delete branches back off on GitHub repositories.
+
+disable undoes the change made by ghautodelete: it clears the "Automatically
+delete head branches" setting. Repositories are selected exactly as for the
+root command, and --check and --dry-run behave the same way. Repositories that
+already have the setting off are left unchanged.`
+
+const disableExamples = `  # Turn auto-delete branches off again
+  ghautodelete disable octocat/hello-world
+
+  # Preview rolling back every repository of an organization
+  ghautodelete disable --org acme --dry-run`
+
+const auditDescription = `Verify that auto-delete branches is enabled on GitHub repositories.
+
+audit checks repositories without modifying them and lists those that do not
+have the "Automatically delete head branches" setting on as non-compliant.
+Repositories are selected exactly as for the root command. The exit code is 7
+if any repository is non-compliant, so audit can gate a CI pipeline.`
+
+const auditExamples = `  # Fail the pipeline if any repository of an organization has drifted
+  ghautodelete audit --org acme
+
+  # Audit a list of repositories
+  ghautodelete audit --from-file repos.txt`
+
+const examples = `  # Enable auto-delete using owner/repo format
+  ghautodelete octocat/hello-world
+
+  # Using an HTTPS URL
+  ghautodelete https://github.com/octocat/hello-world
+
+  # Using an SSH URL
+  ghautodelete git@github.com:octocat/hello-world.git
+
+  # Check the current status without making changes
+  ghautodelete --check octocat/hello-world
+
+  # Use an explicit token
+  ghautodelete --token ghp_xxxx octocat/hello-world
+
+  # Enable on several repositories at once
+  ghautodelete acme/api acme/web --from-file repos.txt
+
+  # Preview the change for every non-archived, non-fork repository of an organization
+  ghautodelete --org acme --skip-forks --dry-run
+
+  # Only Go services pushed to since 2024, except experiments
+  ghautodelete --org acme --topic service --language go --pushed-since 2024-01-01 --exclude 'exp-*'
+
+  # Enable on every backend repository found by a search
+  ghautodelete --search "org:acme topic:backend archived:false"
+
+  # Enable across every organization of an enterprise, resuming after an interruption
+  ghautodelete --enterprise acme-corp --concurrency 8
+  ghautodelete --enterprise acme-corp --concurrency 8 --resume
+
+  # Pick up a run that died part-way, retrying only what did not succeed
+  ghautodelete --org acme --checkpoint acme.jsonl
+  ghautodelete --org acme --checkpoint acme.jsonl --resume
+
+  # Check every repository you own or collaborate on
+  ghautodelete --user --affiliation owner,collaborator --check
+
+  # Emit one JSON record per repository for CI
+  ghautodelete --org acme --check --output json
+
+  # Enable on a GitHub Enterprise Server repository
+  ghautodelete https://git.corp.example/platform/api
+
+  # Authenticate as a GitHub App installation
+  ghautodelete --org acme --app-id 12345 --app-installation-id 67890 --app-private-key-file app.pem
+
+  # Roll the change back
+  ghautodelete disable octocat/hello-world`
+
+func main() {
+	env := &environment{
+		out:        os.Stdout,
+		errOut:     os.Stderr,
+		getenv:     os.Getenv,
+		homeDir:    os.UserHomeDir,
+		cacheDir:   os.UserCacheDir,
+		configDir:  os.UserConfigDir,
+		readFile:   os.ReadFile,
+		writeFile:  os.WriteFile,
+		runCommand: token.ExecCommand,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+
+	if err := execute(env, os.Args[1:]); err != nil {
+		os.Exit(errors.GetExitCode(err))
+	}
+}
+
+// execute builds the root command, runs it with the given arguments and reports
+// any error on the error stream. The returned error is suitable for GetExitCode.
+func execute(env *environment, args []string) error {
+	cmd := newRootCmd(env)
+	cmd.SetArgs(args)
+
+	// Ctrl-C cancels outstanding work; a second Ctrl-C exits immediately
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := cmd.ExecuteContext(ctx)
+	if err != nil {
+		output.NewOutputWriter(false, env.out, env.errOut).Error(err.Error())
+	}
+	return err
+}
+
+// newRootCmd creates the ghautodelete cobra command.
+// Flags are mapped directly onto an interfaces.CLIOptions value.
+func newRootCmd(env *environment) *cobra.Command {
+	var opts interfaces.CLIOptions
+	var fromFile string
+
+	cmd := &cobra.Command{
+		Use:           "ghautodelete [flags] <repository>...",
+		Short:         "Enable auto-delete branches on GitHub repositories",
+		Long:          longDescription,
+		Example:       examples,
+		Version:       version,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		Args:          cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCommand(cmd.Context(), env, opts, args, fromFile)
+		},
+	}
+
+	disableCmd := &cobra.Command{
+		Use:     "disable [flags] <repository>...",
+		Short:   "Turn auto-delete branches back off",
+		Long:    disableDescription,
+		Example: disableExamples,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Disable = true
+			return runCommand(cmd.Context(), env, opts, args, fromFile)
+		},
+	}
+	auditCmd := &cobra.Command{
+		Use:     "audit [flags] <repository>...",
+		Short:   "Report repositories that do not have auto-delete branches enabled",
+		Long:    auditDescription,
+		Example: auditExamples,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Audit = true
+			return runCommand(cmd.Context(), env, opts, args, fromFile)
+		},
+	}
+	cmd.AddCommand(disableCmd, auditCmd)
+	cmd.AddCommand(newManifestCmds(env, &opts, &fromFile)...)
+	cmd.AddCommand(newHistoryCmd(env, &opts))
+	cmd.AddCommand(newSnapshotCmds(env, &opts, &fromFile)...)
+	cmd.CompletionOptions.DisableDefaultCmd = true
+
+	cmd.SetOut(env.out)
+	cmd.SetErr(env.errOut)
+	cmd.SetVersionTemplate("ghautodelete version {{.Version}}\n")
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return errors.NewValidationError(err.Error())
+	})
+
+	// Flags are persistent so the disable subcommand accepts them too.
+	flags := cmd.PersistentFlags()
+	flags.StringVarP(&opts.Token, "token", "t", "", "GitHub personal access token")
+	flags.StringVar(&opts.TokenFile, "token-file", "", "Read the GitHub token from this file")
+	flags.StringVar(&opts.TokenCommand, "token-command", "", "Run this command (without a shell) and use its output as the GitHub token")
+	flags.StringVar(&opts.TokenSources, "token-sources", "", "Comma-separated order of token sources: file, command, gh-token, github-token, gh-hosts, git-credential (default all but git-credential)")
+	flags.Int64Var(&opts.AppID, "app-id", 0, "Authenticate as this GitHub App (with --app-installation-id and --app-private-key-file)")
+	flags.Int64Var(&opts.AppInstallationID, "app-installation-id", 0, "GitHub App installation ID to authenticate as")
+	flags.StringVar(&opts.AppPrivateKeyFile, "app-private-key-file", "", "PEM file with the GitHub App private key")
+	flags.BoolVarP(&opts.CheckOnly, "check", "c", false, "Only check current status, don't modify")
+	flags.BoolVarP(&opts.DryRun, "dry-run", "d", false, "Show what would be done without making changes")
+	flags.BoolVarP(&opts.Verbose, "verbose", "v", false, "Enable verbose output")
+	flags.StringVarP(&opts.Output, "output", "o", output.FormatText, "Output format: text or json (one JSON object per repository on stdout)")
+	flags.StringVar(&opts.Hostname, "hostname", "", "GitHub host, e.g. a GitHub Enterprise Server host (default github.com, or GH_HOST)")
+	flags.StringVar(&fromFile, "from-file", "", "Read repositories from a file, one per line")
+	flags.StringVar(&opts.Organization, "org", "", "Process every repository of an organization")
+	flags.BoolVar(&opts.User, "user", false, "Process every repository of the authenticated user")
+	flags.StringVar(&opts.Enterprise, "enterprise", "", "Process every repository of every organization of an enterprise (by slug)")
+	flags.StringVar(&opts.Search, "search", "", "Process every repository matching a GitHub search query, e.g. \"org:acme topic:backend archived:false\"")
+	flags.StringVar(&opts.Affiliation, "affiliation", "owner", "With --user, comma-separated affiliations: owner, collaborator, organization_member")
+	flags.BoolVar(&opts.SkipArchived, "skip-archived", true, "Skip archived repositories when listing an organization, user, search or enterprise")
+	flags.BoolVar(&opts.SkipForks, "skip-forks", false, "Skip forked repositories when listing an organization, user, search or enterprise")
+	flags.BoolVar(&opts.SkipTemplates, "skip-templates", false, "Skip template repositories when listing an organization, user, search or enterprise")
+	flags.StringSliceVar(&opts.Topics, "topic", nil, "Only process repositories with this topic when listing an organization, user, search or enterprise (repeatable; all must match)")
+	flags.StringVar(&opts.Visibility, "visibility", "", "Only process repositories with this visibility when listing an organization, user, search or enterprise: public, private or internal")
+	flags.StringVar(&opts.Language, "language", "", "Only process repositories with this primary language when listing an organization, user, search or enterprise")
+	flags.StringArrayVar(&opts.Include, "include", nil, "Only process repositories whose name matches this glob or /regex/ when listing an organization, user, search or enterprise (repeatable)")
+	flags.StringArrayVar(&opts.Exclude, "exclude", nil, "Skip repositories whose name matches this glob or /regex/ when listing an organization, user, search or enterprise (repeatable)")
+	flags.StringVar(&opts.PushedSince, "pushed-since", "", "Only process repositories pushed to on or after this date (YYYY-MM-DD) when listing an organization, user, search or enterprise")
+	flags.IntVar(&opts.Concurrency, "concurrency", 1, "Number of repositories to process at once")
+	flags.BoolVar(&opts.WaitOnRateLimit, "wait-on-rate-limit", false, "Wait for the API rate limit to reset instead of failing when it is exhausted")
+	flags.StringVar(&opts.API, "api", github.BackendREST, "API for repository reads: rest, or graphql to read up to 100 repositories per request")
+	flags.StringVar(&opts.AuditLog, "audit-log", "", "JSON Lines file recording every setting change (default <user config dir>/ghautodelete/audit-log.jsonl)")
+	flags.BoolVar(&opts.NoAuditLog, "no-audit-log", false, "Do not record setting changes in the audit log")
+	flags.StringVar(&opts.Checkpoint, "checkpoint", "", "File recording the progress of multi-repository runs (default: one per selection under <user cache dir>/ghautodelete/checkpoints for enable and disable runs)")
+	flags.BoolVar(&opts.NoCheckpoint, "no-checkpoint", false, "Do not record the progress of multi-repository runs")
+	flags.BoolVar(&opts.Resume, "resume", false, "Continue the run recorded in the checkpoint: skip repositories that succeeded and retry the rest")
+	flags.BoolVar(&opts.NoCache, "no-cache", false, "Do not cache API responses on disk for conditional requests")
+	flags.StringVar(&opts.CacheDir, "cache-dir", "", "Directory of the API response cache (default <user cache dir>/ghautodelete/http)")
+
+	return cmd
+}
+
+// runCommand validates the repository selection and runs the application.
+// Shared by the root command and the disable subcommand.
+func runCommand(ctx context.Context, env *environment, opts interfaces.CLIOptions, args []string, fromFile string) error {
+	identifiers, batch, err := resolveSelection(env, &opts, args, fromFile)
+	if err != nil {
+		return err
+	}
+
+	return runApp(ctx, env, opts, identifiers, batch)
+}
+
+// resolveSelection validates the repository selection and resolves opts.Hostname.
+// Returns the repository identifiers (none with --org, --user, --search or
+// --enterprise) and whether they run in batch mode even if there is only one.
+func resolveSelection(env *environment, opts *interfaces.CLIOptions, args []string, fromFile string) ([]string, bool, error) {
+	if opts.Organization != "" && opts.User {
+		return nil, false, errors.NewValidationError("--org and --user cannot be combined")
+	}
+
+	opts.Search = strings.TrimSpace(opts.Search)
+	if opts.Search != "" && (opts.Organization != "" || opts.User) {
+		return nil, false, errors.NewValidationError("--search cannot be combined with --org or --user")
+	}
+
+	if opts.Enterprise != "" && (opts.Organization != "" || opts.User || opts.Search != "") {
+		return nil, false, errors.NewValidationError("--enterprise cannot be combined with --org, --user or --search")
+	}
+
+	if opts.User && opts.AppID != 0 {
+		return nil, false, errors.NewValidationError("--user cannot be combined with GitHub App authentication, which has no user")
+	}
+
+	if opts.Organization != "" || opts.User || opts.Search != "" || opts.Enterprise != "" {
+		if len(args) > 0 || fromFile != "" {
+			return nil, false, errors.NewValidationError(
+				"--org, --user, --search and --enterprise cannot be combined with repository arguments or --from-file")
+		}
+		if err := validateAffiliation(opts.Affiliation); err != nil {
+			return nil, false, err
+		}
+		hostname, err := resolveHostname(env, opts.Hostname, nil)
+		if err != nil {
+			return nil, false, err
+		}
+		opts.Hostname = hostname
+		return nil, true, nil
+	}
+
+	if hasListingFilters(*opts) {
+		return nil, false, errors.NewValidationError(
+			"--topic, --visibility, --language, --include, --exclude and --pushed-since require --org, --user, --search or --enterprise")
+	}
+
+	identifiers, err := repositoryIdentifiers(env, args, fromFile)
+	if err != nil {
+		return nil, false, err
+	}
+
+	opts.Hostname, err = resolveHostname(env, opts.Hostname, identifiers)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return identifiers, fromFile != "", nil
+}
+
+// repositoryIdentifiers combines positional repositories with those listed in fromFile.
+// An empty result is reported as a validation error so it maps to exit code 2.
+func repositoryIdentifiers(env *environment, args []string, fromFile string) ([]string, error) {
+	identifiers := append([]string{}, args...)
+
+	if fromFile != "" {
+		content, err := env.readFile(fromFile)
+		if err != nil {
+			return nil, errors.NewValidationError(fmt.Sprintf("Cannot read repository list %s: %v", fromFile, err))
+		}
+		identifiers = append(identifiers, parser.ParseRepositoryList(content)...)
+	}
+
+	if len(identifiers) == 0 {
+		return nil, errors.NewValidationError("Repository identifier is required. Usage: ghautodelete [flags] <repository>...")
+	}
+
+	return identifiers, nil
+}
+
+// hasListingFilters reports whether any filter that only applies to listed
+// repositories is set.
+func hasListingFilters(opts interfaces.CLIOptions) bool {
+	return len(opts.Topics) > 0 || opts.Visibility != "" || opts.Language != "" ||
+		len(opts.Include) > 0 || len(opts.Exclude) > 0 || opts.PushedSince != ""
+}
+
+// validateAffiliation checks that every comma-separated value is a known affiliation.
+func validateAffiliation(affiliation string) error {
+	for _, value := range strings.Split(affiliation, ",") {
+		if !validAffiliations[value] {
+			return errors.NewValidationError(fmt.Sprintf(
+				"Invalid affiliation %q. Expected a comma-separated list of owner, collaborator, organization_member", value))
+		}
+	}
+	return nil
+}
+
+// resolveHostname determines the GitHub host for a run.
+//
+// An explicit --hostname wins, then the GH_HOST environment variable, then the host
+// of any repository URL among identifiers, and finally github.com. Repository URLs
+// on a different host are rejected, since one run talks to a single host.
+func resolveHostname(env *environment, hostname string, identifiers []string) (string, error) {
+	if hostname == "" {
+		hostname = env.getenv("GH_HOST")
+	}
+	hostname = strings.ToLower(strings.TrimSpace(hostname))
+	if strings.Contains(hostname, "/") {
+		return "", errors.NewValidationError(fmt.Sprintf(
+			"Invalid hostname %q. Expected a host name such as git.corp.example", hostname))
+	}
+
+	for _, identifier := range identifiers {
+		detected := parser.DetectHostname(identifier)
+		if detected == "" {
+			continue
+		}
+		if hostname == "" {
+			hostname = detected
+		} else if detected != hostname {
+			return "", errors.NewValidationError(fmt.Sprintf(
+				"Repository %s is not on %s. All repositories must be on the same host", identifier, hostname))
+		}
+	}
+
+	if hostname == "" {
+		return github.DefaultHostname, nil
+	}
+	return hostname, nil
+}
@@ -0,0 +1,1254 @@
+// Package main provides tests for the ghautodelete cobra entrypoint.
+//
+// These tests drive the root command end to end against a fake GitHub API and
+// verify help/version output, flag mapping and the documented exit-code table.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+)
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+// newTestEnvironment creates an environment backed by buffers and the given server.
+// The token is supplied through a fake GITHUB_TOKEN environment variable.
+func newTestEnvironment(server *httptest.Server, envToken string) (*environment, *bytes.Buffer, *bytes.Buffer) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	env := &environment{
+		out:    stdout,
+		errOut: stderr,
+		getenv: func(key string) string {
+			if key == "GITHUB_TOKEN" {
+				return envToken
+			}
+			return ""
+		},
+		homeDir:  func() (string, error) { return "", errors.New("no home directory") },
+		readFile: func(string) ([]byte, error) { return nil, errors.New("no file") },
+	}
+	if server != nil {
+		env.httpClient = server.Client()
+		env.baseURL = server.URL
+	} else {
+		env.httpClient = &http.Client{}
+		env.baseURL = "http://127.0.0.1:0"
+	}
+
+	return env, stdout, stderr
+}
+
+// fakeFiles returns a file reader serving the given in-memory files.
+func fakeFiles(files map[string]string) func(string) ([]byte, error) {
+	return func(path string) ([]byte, error) {
+		content, ok := files[path]
+		if !ok {
+			return nil, errors.New("file does not exist")
+		}
+		return []byte(content), nil
+	}
+}
+
+// newFakeGitHub creates a server that serves a single repository whose
+// delete_branch_on_merge flag starts at the given value and can be PATCHed.
+// The token is accepted as octocat's with the "repo" scope.
+func newFakeGitHub(t *testing.T, enabled bool) (*httptest.Server, *int) {
+	t.Helper()
+	patches := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/user" {
+			w.Header().Set("X-OAuth-Scopes", "repo")
+			_, _ = w.Write([]byte(`{"login":"octocat"}`))
+			return
+		}
+		if r.URL.Path != "/repos/octocat/hello-world" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodPatch {
+			patches++
+			var body struct {
+				DeleteBranchOnMerge bool `json:"delete_branch_on_merge"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			enabled = body.DeleteBranchOnMerge
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if enabled {
+			_, _ = w.Write([]byte(`{"owner":{"login":"octocat"},"name":"hello-world","default_branch":"main","delete_branch_on_merge":true}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"owner":{"login":"octocat"},"name":"hello-world","default_branch":"main","delete_branch_on_merge":false}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &patches
+}
+
+// =============================================================================
+// Help and Version Tests
+// =============================================================================
+
+// TestHelpFlagShowsUsageFlagsAndExamples verifies --help and -h output.
+func TestHelpFlagShowsUsageFlagsAndExamples(t *testing.T) {
+	for _, flag := range []string{"--help", "-h"} {
+		t.Run(flag, func(t *testing.T) {
+			// Arrange
+			env, stdout, _ := newTestEnvironment(nil, "")
+
+			// Act
+			err := execute(env, []string{flag})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("execute(%s) error = %v, expected nil", flag, err)
+			}
+			help := stdout.String()
+			expected := []string{
+				"Enable auto-delete branches",
+				"Usage:",
+				"--token",
+				"--check",
+				"--dry-run",
+				"--verbose",
+				"ghautodelete octocat/hello-world",
+				"https://github.com/octocat/hello-world",
+				"git@github.com:octocat/hello-world.git",
+			}
+			for _, want := range expected {
+				if !strings.Contains(help, want) {
+					t.Errorf("help output should contain %q, got:\n%s", want, help)
+				}
+			}
+		})
+	}
+}
+
+// TestVersionFlagShowsVersion verifies --version prints the injected version.
+func TestVersionFlagShowsVersion(t *testing.T) {
+	// Arrange
+	env, stdout, _ := newTestEnvironment(nil, "")
+
+	// Act
+	err := execute(env, []string{"--version"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("execute(--version) error = %v, expected nil", err)
+	}
+	if got := stdout.String(); got != "ghautodelete version "+version+"\n" {
+		t.Errorf("version output = %q, expected %q", got, "ghautodelete version "+version+"\n")
+	}
+}
+
+// =============================================================================
+// Argument Validation Tests
+// =============================================================================
+
+// TestArgumentErrorsExitWithCode2 verifies invalid invocations map to exit code 2.
+func TestArgumentErrorsExitWithCode2(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "missing repository", args: []string{}},
+		{name: "empty repository list file", args: []string{"--from-file", "empty.txt"}},
+		{name: "unreadable repository list file", args: []string{"--from-file", "missing.txt"}},
+		{name: "unknown flag", args: []string{"--bogus", "octocat/hello-world"}},
+		{name: "invalid repository format", args: []string{"invalid/repo/format/extra"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			env, _, stderr := newTestEnvironment(nil, "ghp_test")
+			env.readFile = fakeFiles(map[string]string{"empty.txt": "# nothing here\n"})
+
+			// Act
+			err := execute(env, tt.args)
+
+			// Assert
+			if code := apperrors.GetExitCode(err); code != 2 {
+				t.Errorf("exit code = %d, expected 2 (err: %v)", code, err)
+			}
+			if !strings.HasPrefix(stderr.String(), "Error: ") {
+				t.Errorf("stderr should contain an error message, got %q", stderr.String())
+			}
+		})
+	}
+}
+
+// TestMissingTokenExitsWithCode3 verifies a missing token maps to exit code 3.
+func TestMissingTokenExitsWithCode3(t *testing.T) {
+	// Arrange
+	env, _, stderr := newTestEnvironment(nil, "")
+
+	// Act
+	err := execute(env, []string{"octocat/hello-world"})
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 3 {
+		t.Errorf("exit code = %d, expected 3 (err: %v)", code, err)
+	}
+	if !strings.Contains(stderr.String(), "No GitHub token found") {
+		t.Errorf("stderr should explain the missing token, got %q", stderr.String())
+	}
+}
+
+// TestTokenFileIsTracedWithoutRevealingToken verifies --token-file supplies the token
+// and --verbose names the source but never prints the token.
+func TestTokenFileIsTracedWithoutRevealingToken(t *testing.T) {
+	// Arrange
+	server, _ := newFakeGitHub(t, true)
+	env, stdout, stderr := newTestEnvironment(server, "")
+	env.readFile = fakeFiles(map[string]string{"token.txt": "ghp_from_file\n"})
+
+	// Act
+	err := execute(env, []string{"--token-file", "token.txt", "--verbose", "octocat/hello-world"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("execute() error = %v, expected nil", err)
+	}
+	out := stdout.String() + stderr.String()
+	if !strings.Contains(out, "Using token from token file token.txt") {
+		t.Errorf("verbose output should name the token source, got:\n%s", out)
+	}
+	if strings.Contains(out, "ghp_from_file") {
+		t.Errorf("verbose output must not contain the token, got:\n%s", out)
+	}
+}
+
+// TestInvalidTokenSourcesExitsWithCode2 verifies an unknown --token-sources entry is rejected.
+func TestInvalidTokenSourcesExitsWithCode2(t *testing.T) {
+	// Arrange
+	env, _, _ := newTestEnvironment(nil, "ghp_test")
+
+	// Act
+	err := execute(env, []string{"--token-sources", "github-token,keychain", "octocat/hello-world"})
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 2 {
+		t.Errorf("exit code = %d, expected 2 (err: %v)", code, err)
+	}
+}
+
+// =============================================================================
+// End-to-End Mode Tests
+// =============================================================================
+
+// TestNormalModeEnablesAutoDelete verifies the full enable flow exits with code 0.
+func TestNormalModeEnablesAutoDelete(t *testing.T) {
+	// Arrange
+	server, patches := newFakeGitHub(t, false)
+	env, stdout, _ := newTestEnvironment(server, "ghp_test")
+
+	// Act
+	err := execute(env, []string{"octocat/hello-world"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("execute() error = %v, expected nil", err)
+	}
+	if *patches != 1 {
+		t.Errorf("expected 1 PATCH request, got %d", *patches)
+	}
+	if !strings.Contains(stdout.String(), "Successfully enabled auto-delete branches for octocat/hello-world") {
+		t.Errorf("stdout should contain success message, got %q", stdout.String())
+	}
+}
+
+// TestFlagsMapOntoCLIOptions verifies --check, --dry-run and --verbose reach the app.
+func TestFlagsMapOntoCLIOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{name: "check", args: []string{"--check", "octocat/hello-world"}, expected: "Auto-delete branches: disabled"},
+		{name: "check short", args: []string{"-c", "octocat/hello-world"}, expected: "Auto-delete branches: disabled"},
+		{name: "dry-run", args: []string{"--dry-run", "octocat/hello-world"}, expected: "[DRY-RUN] Would enable"},
+		{name: "dry-run short", args: []string{"-d", "octocat/hello-world"}, expected: "[DRY-RUN] Would enable"},
+		{name: "verbose", args: []string{"-v", "-d", "octocat/hello-world"}, expected: "[verbose] Fetching repository information"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server, patches := newFakeGitHub(t, false)
+			env, stdout, _ := newTestEnvironment(server, "ghp_test")
+
+			// Act
+			err := execute(env, tt.args)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("execute() error = %v, expected nil", err)
+			}
+			if *patches != 0 {
+				t.Errorf("expected no PATCH requests, got %d", *patches)
+			}
+			if !strings.Contains(stdout.String(), tt.expected) {
+				t.Errorf("stdout should contain %q, got %q", tt.expected, stdout.String())
+			}
+		})
+	}
+}
+
+// TestExplicitTokenFlagIsUsed verifies --token takes precedence over the environment.
+func TestExplicitTokenFlagIsUsed(t *testing.T) {
+	// Arrange
+	var authHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"owner":{"login":"octocat"},"name":"hello-world","default_branch":"main","delete_branch_on_merge":true}`))
+	}))
+	defer server.Close()
+	env, _, _ := newTestEnvironment(server, "ghp_from_env")
+
+	// Act
+	err := execute(env, []string{"--token", "ghp_from_flag", "--check", "octocat/hello-world"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("execute() error = %v, expected nil", err)
+	}
+	if authHeader != "Bearer ghp_from_flag" {
+		t.Errorf("Authorization header = %q, expected token from --token flag", authHeader)
+	}
+}
+
+// TestMissingRepoScopeExitsWithCode4 verifies a classic token without "repo" fails before any PATCH.
+func TestMissingRepoScopeExitsWithCode4(t *testing.T) {
+	// Arrange
+	inner, patches := newFakeGitHub(t, false)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/user" {
+			w.Header().Set("X-OAuth-Scopes", "read:org")
+			_, _ = w.Write([]byte(`{"login":"octocat"}`))
+			return
+		}
+		inner.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	env, _, stderr := newTestEnvironment(server, "ghp_test")
+
+	// Act
+	err := execute(env, []string{"octocat/hello-world"})
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 4 {
+		t.Errorf("exit code = %d, expected 4 (err: %v)", code, err)
+	}
+	if *patches != 0 {
+		t.Errorf("expected no PATCH requests, got %d", *patches)
+	}
+	if !strings.Contains(stderr.String(), "'repo' scope") {
+		t.Errorf("stderr should name the missing scope, got %q", stderr.String())
+	}
+}
+
+// TestRepositoryNotFoundExitsWithCode5 verifies API errors keep their exit codes.
+func TestRepositoryNotFoundExitsWithCode5(t *testing.T) {
+	// Arrange
+	server, _ := newFakeGitHub(t, false)
+	env, _, stderr := newTestEnvironment(server, "ghp_test")
+
+	// Act
+	err := execute(env, []string{"octocat/nonexistent"})
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 5 {
+		t.Errorf("exit code = %d, expected 5 (err: %v)", code, err)
+	}
+	if !strings.Contains(stderr.String(), "Repository not found: octocat/nonexistent") {
+		t.Errorf("stderr should contain not-found message, got %q", stderr.String())
+	}
+}
+
+// =============================================================================
+// Batch Mode Tests
+// =============================================================================
+
+// TestBatchModeCombinesArgumentsAndFile verifies positional and --from-file repositories run together.
+func TestBatchModeCombinesArgumentsAndFile(t *testing.T) {
+	// Arrange
+	server, patches := newFakeGitHub(t, false)
+	env, stdout, _ := newTestEnvironment(server, "ghp_test")
+	env.readFile = fakeFiles(map[string]string{
+		"repos.txt": "# services\nhttps://github.com/octocat/hello-world  # same repo, URL form\n",
+	})
+
+	// Act
+	err := execute(env, []string{"octocat/hello-world", "--from-file", "repos.txt"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("execute() error = %v, expected nil", err)
+	}
+	if *patches != 1 {
+		t.Errorf("expected 1 PATCH request, got %d", *patches)
+	}
+	for _, want := range []string{
+		"octocat/hello-world  enabled",
+		"octocat/hello-world  already enabled",
+		"2 repositories processed: 2 succeeded, 0 failed",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("stdout should contain %q, got:\n%s", want, stdout.String())
+		}
+	}
+}
+
+// TestBatchModeExitCodeReflectsFailures verifies failures keep processing and set the exit code.
+func TestBatchModeExitCodeReflectsFailures(t *testing.T) {
+	// Arrange
+	server, _ := newFakeGitHub(t, true)
+	env, stdout, stderr := newTestEnvironment(server, "ghp_test")
+
+	// Act
+	err := execute(env, []string{"octocat/nonexistent", "octocat/hello-world"})
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 5 {
+		t.Errorf("exit code = %d, expected 5 (err: %v)", code, err)
+	}
+	if !strings.Contains(stdout.String(), "octocat/hello-world  already enabled") {
+		t.Errorf("stdout should report the successful repository, got:\n%s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "1 of 2 repositories failed") {
+		t.Errorf("stderr should summarize failures, got %q", stderr.String())
+	}
+}
+
+// =============================================================================
+// Organization Mode Tests
+// =============================================================================
+
+// TestOrganizationModeSkipsArchivedByDefault verifies --org lists, filters and configures.
+func TestOrganizationModeSkipsArchivedByDefault(t *testing.T) {
+	// Arrange
+	inner, patches := newFakeGitHub(t, false)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/orgs/octocat/repos" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"owner":{"login":"octocat"},"name":"hello-world"},{"owner":{"login":"octocat"},"name":"old","archived":true}]`))
+			return
+		}
+		inner.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	env, stdout, _ := newTestEnvironment(server, "ghp_test")
+
+	// Act
+	err := execute(env, []string{"--org", "octocat", "-v"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("execute() error = %v, expected nil", err)
+	}
+	if *patches != 1 {
+		t.Errorf("expected 1 PATCH request, got %d", *patches)
+	}
+	for _, want := range []string{
+		"Skipping octocat/old: archived",
+		"Found 2 repositories in octocat (1 skipped)",
+		"octocat/hello-world  enabled",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("stdout should contain %q, got:\n%s", want, stdout.String())
+		}
+	}
+}
+
+// TestOrganizationModeRejectsRepositoryArguments verifies --org is exclusive with explicit repositories.
+func TestOrganizationModeRejectsRepositoryArguments(t *testing.T) {
+	// Arrange
+	env, _, _ := newTestEnvironment(nil, "ghp_test")
+
+	// Act
+	err := execute(env, []string{"--org", "octocat", "octocat/hello-world"})
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 2 {
+		t.Errorf("exit code = %d, expected 2 (err: %v)", code, err)
+	}
+}
+
+// TestOrganizationModeAppliesSelectionFilters verifies the listing metadata
+// reaches the selection filters and filters need a listing mode.
+func TestOrganizationModeAppliesSelectionFilters(t *testing.T) {
+	// Arrange
+	inner, patches := newFakeGitHub(t, false)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/orgs/octocat/repos" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[
+				{"owner":{"login":"octocat"},"name":"hello-world","visibility":"public","language":"Go","topics":["demo"],"pushed_at":"2024-05-01T00:00:00Z"},
+				{"owner":{"login":"octocat"},"name":"spoon-knife","visibility":"public","language":"HTML","topics":["demo"],"pushed_at":"2024-05-01T00:00:00Z"},
+				{"owner":{"login":"octocat"},"name":"secret","visibility":"private","language":"Go","topics":["demo"],"pushed_at":"2024-05-01T00:00:00Z"},
+				{"owner":{"login":"octocat"},"name":"linguist","visibility":"public","language":"Go","pushed_at":"2020-01-01T00:00:00Z"}]`))
+			return
+		}
+		inner.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	env, stdout, _ := newTestEnvironment(server, "ghp_test")
+
+	// Act
+	err := execute(env, []string{"--org", "octocat", "-v", "--topic", "demo", "--visibility", "public",
+		"--language", "go", "--pushed-since", "2024-01-01"})
+	listingErr := execute(env, []string{"--topic", "demo", "octocat/hello-world"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("execute() error = %v, expected nil", err)
+	}
+	if *patches != 1 {
+		t.Errorf("expected 1 PATCH request, got %d", *patches)
+	}
+	for _, want := range []string{
+		"Skipping octocat/spoon-knife: language is HTML, not go",
+		"Skipping octocat/secret: visibility is private, not public",
+		"Skipping octocat/linguist: last pushed 2020-01-01",
+		"Found 4 repositories in octocat (3 skipped)",
+		"octocat/hello-world  enabled",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("stdout should contain %q, got:\n%s", want, stdout.String())
+		}
+	}
+	if code := apperrors.GetExitCode(listingErr); code != 2 {
+		t.Errorf("filters with explicit repositories: exit code = %d, expected 2 (err: %v)", code, listingErr)
+	}
+}
+
+// TestSearchModeProcessesMatchingRepositories verifies --search pages through
+// /search/repositories and processes the results.
+func TestSearchModeProcessesMatchingRepositories(t *testing.T) {
+	// Arrange
+	inner, patches := newFakeGitHub(t, false)
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/search/repositories" {
+			query = r.URL.Query().Get("q")
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"total_count":1,"items":[{"owner":{"login":"octocat"},"name":"hello-world"}]}`))
+			return
+		}
+		inner.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	env, stdout, _ := newTestEnvironment(server, "ghp_test")
+
+	// Act
+	err := execute(env, []string{"--search", "user:octocat topic:demo"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("execute() error = %v, expected nil", err)
+	}
+	if query != "user:octocat topic:demo" {
+		t.Errorf("search query = %q, expected it unchanged", query)
+	}
+	if *patches != 1 {
+		t.Errorf("expected 1 PATCH request, got %d", *patches)
+	}
+	if !strings.Contains(stdout.String(), "octocat/hello-world  enabled") {
+		t.Errorf("stdout should report octocat/hello-world enabled, got:\n%s", stdout.String())
+	}
+}
+
+// TestSearchModeValidation verifies --search is exclusive with the other selections.
+func TestSearchModeValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "combined with --org", args: []string{"--search", "topic:demo", "--org", "acme"}},
+		{name: "combined with --user", args: []string{"--search", "topic:demo", "--user"}},
+		{name: "combined with repository", args: []string{"--search", "topic:demo", "octocat/hello-world"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			env, _, _ := newTestEnvironment(nil, "ghp_test")
+
+			// Act
+			err := execute(env, tt.args)
+
+			// Assert
+			if code := apperrors.GetExitCode(err); code != 2 {
+				t.Errorf("exit code = %d, expected 2 (err: %v)", code, err)
+			}
+		})
+	}
+}
+
+// TestEnterpriseModeProcessesEveryOrganization verifies --enterprise lists the
+// enterprise's organizations with GraphQL and processes each one.
+func TestEnterpriseModeProcessesEveryOrganization(t *testing.T) {
+	// Arrange
+	inner, patches := newFakeGitHub(t, false)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/graphql":
+			_, _ = w.Write([]byte(`{"data":{"enterprise":{"organizations":{"pageInfo":{"hasNextPage":false},"nodes":[{"login":"octocat"}]}}}}`))
+		case "/orgs/octocat/repos":
+			_, _ = w.Write([]byte(`[{"owner":{"login":"octocat"},"name":"hello-world"}]`))
+		default:
+			inner.Config.Handler.ServeHTTP(w, r)
+		}
+	}))
+	defer server.Close()
+	env, stdout, _ := newTestEnvironment(server, "ghp_test")
+
+	// Act
+	err := execute(env, []string{"--enterprise", "octo-corp"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("execute() error = %v, expected nil", err)
+	}
+	if *patches != 1 {
+		t.Errorf("expected 1 PATCH request, got %d", *patches)
+	}
+	for _, expected := range []string{"octocat/hello-world  enabled", "Organization 1/1: octocat"} {
+		if !strings.Contains(stdout.String(), expected) {
+			t.Errorf("stdout should contain %q, got:\n%s", expected, stdout.String())
+		}
+	}
+}
+
+// TestEnterpriseModeValidation verifies --enterprise is exclusive with the other
+// selections.
+func TestEnterpriseModeValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "combined with --org", args: []string{"--enterprise", "acme-corp", "--org", "acme"}},
+		{name: "combined with --search", args: []string{"--enterprise", "acme-corp", "--search", "topic:demo"}},
+		{name: "combined with repository", args: []string{"--enterprise", "acme-corp", "octocat/hello-world"}},
+		{name: "snapshot", args: []string{"snapshot", "--enterprise", "acme-corp"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			env, _, _ := newTestEnvironment(nil, "ghp_test")
+
+			// Act
+			err := execute(env, tt.args)
+
+			// Assert
+			if code := apperrors.GetExitCode(err); code != 2 {
+				t.Errorf("exit code = %d, expected 2 (err: %v)", code, err)
+			}
+		})
+	}
+}
+
+// TestResumeSkipsRepositoriesThatSucceeded verifies a failed batch run records
+// its progress and --resume only retries the repository that failed.
+func TestResumeSkipsRepositoriesThatSucceeded(t *testing.T) {
+	// Arrange
+	server, patches := newFakeGitHub(t, false)
+	defer server.Close()
+	env, stdout, _ := newTestEnvironment(server, "ghp_test")
+	path := filepath.Join(t.TempDir(), "progress.jsonl")
+	args := []string{"--checkpoint", path, "octocat/hello-world", "octocat/missing"}
+
+	// Act
+	firstErr := execute(env, args)
+	firstOutput := stdout.String()
+	stdout.Reset()
+	resumeErr := execute(env, append(args, "--resume"))
+
+	// Assert
+	if code := apperrors.GetExitCode(firstErr); code != 5 {
+		t.Fatalf("exit code = %d, expected 5 for the missing repository (err: %v)", code, firstErr)
+	}
+	if !strings.Contains(firstOutput, "Run again with --resume") {
+		t.Errorf("stdout should suggest --resume, got:\n%s", firstOutput)
+	}
+	if code := apperrors.GetExitCode(resumeErr); code != 5 {
+		t.Errorf("resumed exit code = %d, expected 5 (err: %v)", code, resumeErr)
+	}
+	if *patches != 1 {
+		t.Errorf("expected 1 PATCH request over both runs, got %d", *patches)
+	}
+	if strings.Contains(stdout.String(), "octocat/hello-world") {
+		t.Errorf("the resumed run should skip octocat/hello-world, got:\n%s", stdout.String())
+	}
+}
+
+// TestDefaultCheckpointOnlyForWriteRuns verifies only runs that modify
+// repositories record a checkpoint in the user cache directory by default.
+func TestDefaultCheckpointOnlyForWriteRuns(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected bool
+	}{
+		{name: "enable", args: []string{"octocat/hello-world", "octocat/missing"}, expected: true},
+		{name: "dry run", args: []string{"--dry-run", "octocat/hello-world", "octocat/missing"}},
+		{name: "check", args: []string{"--check", "octocat/hello-world", "octocat/missing"}},
+		{name: "audit", args: []string{"audit", "octocat/hello-world", "octocat/missing"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server, _ := newFakeGitHub(t, false)
+			env, _, _ := newTestEnvironment(server, "ghp_test")
+			cache := t.TempDir()
+			env.cacheDir = func() (string, error) { return cache, nil }
+
+			// Act
+			_ = execute(env, tt.args)
+
+			// Assert
+			files, _ := filepath.Glob(filepath.Join(cache, "ghautodelete", "checkpoints", "*.jsonl"))
+			if recorded := len(files) == 1; recorded != tt.expected {
+				t.Errorf("checkpoints recorded = %v, expected one: %v", files, tt.expected)
+			}
+		})
+	}
+}
+
+// TestDefaultCheckpointPerSelection verifies runs over other repositories
+// keep their own default checkpoint, so each can still be resumed.
+func TestDefaultCheckpointPerSelection(t *testing.T) {
+	// Arrange
+	server, patches := newFakeGitHub(t, false)
+	env, _, _ := newTestEnvironment(server, "ghp_test")
+	cache := t.TempDir()
+	env.cacheDir = func() (string, error) { return cache, nil }
+	first := []string{"octocat/hello-world", "octocat/missing"}
+	_ = execute(env, first)
+	_ = execute(env, []string{"octocat/hello-world", "octocat/other"})
+
+	// Act
+	err := execute(env, append(first, "--resume"))
+
+	// Assert
+	files, _ := filepath.Glob(filepath.Join(cache, "ghautodelete", "checkpoints", "*.jsonl"))
+	if len(files) != 2 {
+		t.Errorf("checkpoints = %v, expected one per selection", files)
+	}
+	if code := apperrors.GetExitCode(err); code != 5 {
+		t.Errorf("resumed exit code = %d, expected 5 for the missing repository (err: %v)", code, err)
+	}
+	if *patches != 1 {
+		t.Errorf("expected 1 PATCH request over all runs, got %d", *patches)
+	}
+}
+
+// TestFailedWiringKeepsCheckpoint verifies a run rejected before processing any
+// repository does not replace the checkpoint of the previous run.
+func TestFailedWiringKeepsCheckpoint(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		args  []string
+	}{
+		{name: "invalid output", token: "ghp_test", args: []string{"--output", "yaml"}},
+		{name: "no token", args: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server, _ := newFakeGitHub(t, false)
+			path := filepath.Join(t.TempDir(), "progress.jsonl")
+			repositories := []string{"--checkpoint", path, "octocat/hello-world", "octocat/missing"}
+			env, _, _ := newTestEnvironment(server, "ghp_test")
+			_ = execute(env, repositories)
+			recorded, _ := os.ReadFile(path)
+
+			// Act
+			env, _, _ = newTestEnvironment(server, tt.token)
+			err := execute(env, append(tt.args, repositories...))
+
+			// Assert
+			if err == nil {
+				t.Fatal("expected the run to be rejected")
+			}
+			if content, _ := os.ReadFile(path); string(content) != string(recorded) || len(recorded) == 0 {
+				t.Errorf("checkpoint = %q, expected the previous run %q", content, recorded)
+			}
+		})
+	}
+}
+
+// TestResumeRejectsOtherSelection verifies a checkpoint cannot be resumed by a
+// run over other repositories.
+func TestResumeRejectsOtherSelection(t *testing.T) {
+	// Arrange
+	server, patches := newFakeGitHub(t, false)
+	env, _, _ := newTestEnvironment(server, "ghp_test")
+	path := filepath.Join(t.TempDir(), "progress.jsonl")
+	_ = execute(env, []string{"--checkpoint", path, "octocat/hello-world", "octocat/missing"})
+
+	// Act
+	err := execute(env, []string{"--checkpoint", path, "--resume", "octocat/hello-world", "octocat/other"})
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 2 {
+		t.Fatalf("exit code = %d, expected 2 (err: %v)", code, err)
+	}
+	if !strings.Contains(err.Error(), "cannot be resumed") {
+		t.Errorf("error = %q, should explain the checkpoint cannot be resumed", err.Error())
+	}
+	if *patches != 1 {
+		t.Errorf("expected only the PATCH of the first run, got %d", *patches)
+	}
+}
+
+// TestResumeValidation verifies --resume is rejected when there is nothing to resume.
+func TestResumeValidation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.jsonl")
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "single repository", args: []string{"--resume", "--checkpoint", path, "octocat/hello-world"}},
+		{name: "no checkpoint", args: []string{"--resume", "--no-checkpoint", "--org", "acme"}},
+		{name: "no checkpoint file", args: []string{"--resume", "--checkpoint", path, "--org", "acme"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			env, _, _ := newTestEnvironment(nil, "ghp_test")
+
+			// Act
+			err := execute(env, tt.args)
+
+			// Assert
+			if code := apperrors.GetExitCode(err); code != 2 {
+				t.Errorf("exit code = %d, expected 2 (err: %v)", code, err)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// User Mode Tests
+// =============================================================================
+
+// TestUserModeListsAuthenticatedUserRepositories verifies --user resolves the login and lists /user/repos.
+func TestUserModeListsAuthenticatedUserRepositories(t *testing.T) {
+	// Arrange
+	inner, patches := newFakeGitHub(t, false)
+	var affiliation string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/user":
+			_, _ = w.Write([]byte(`{"login":"octocat"}`))
+		case "/user/repos":
+			affiliation = r.URL.Query().Get("affiliation")
+			_, _ = w.Write([]byte(`[{"owner":{"login":"octocat"},"name":"hello-world"}]`))
+		default:
+			inner.Config.Handler.ServeHTTP(w, r)
+		}
+	}))
+	defer server.Close()
+	env, stdout, _ := newTestEnvironment(server, "ghp_test")
+
+	// Act
+	err := execute(env, []string{"--user", "--affiliation", "owner,collaborator"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("execute() error = %v, expected nil", err)
+	}
+	if affiliation != "owner,collaborator" {
+		t.Errorf("affiliation = %q, expected %q", affiliation, "owner,collaborator")
+	}
+	if *patches != 1 {
+		t.Errorf("expected 1 PATCH request, got %d", *patches)
+	}
+	if !strings.Contains(stdout.String(), "Found 1 repositories for octocat (0 skipped)") {
+		t.Errorf("stdout should report the listing, got:\n%s", stdout.String())
+	}
+}
+
+// TestUserModeValidation verifies invalid --user combinations exit with code 2.
+func TestUserModeValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "unknown affiliation", args: []string{"--user", "--affiliation", "owner,admin"}},
+		{name: "combined with --org", args: []string{"--user", "--org", "acme"}},
+		{name: "combined with repository", args: []string{"--user", "octocat/hello-world"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			env, _, _ := newTestEnvironment(nil, "ghp_test")
+
+			// Act
+			err := execute(env, tt.args)
+
+			// Assert
+			if code := apperrors.GetExitCode(err); code != 2 {
+				t.Errorf("exit code = %d, expected 2 (err: %v)", code, err)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Disable Command Tests
+// =============================================================================
+
+// TestDisableCommandTurnsSettingOff verifies the disable subcommand PATCHes the flag back off.
+func TestDisableCommandTurnsSettingOff(t *testing.T) {
+	// Arrange
+	server, patches := newFakeGitHub(t, true)
+	env, stdout, _ := newTestEnvironment(server, "ghp_test")
+
+	// Act
+	err := execute(env, []string{"disable", "octocat/hello-world"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("execute() error = %v, expected nil", err)
+	}
+	if *patches != 1 {
+		t.Errorf("expected 1 PATCH request, got %d", *patches)
+	}
+	if !strings.Contains(stdout.String(), "Successfully disabled auto-delete branches for octocat/hello-world") {
+		t.Errorf("stdout should report the change, got:\n%s", stdout.String())
+	}
+}
+
+// TestDisableCommandModes verifies flags are accepted after the subcommand.
+func TestDisableCommandModes(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		args     []string
+		expected string
+	}{
+		{
+			name:     "dry run",
+			enabled:  true,
+			args:     []string{"disable", "--dry-run", "octocat/hello-world"},
+			expected: "[DRY-RUN] Would disable auto-delete branches for octocat/hello-world",
+		},
+		{
+			name:     "already disabled",
+			enabled:  false,
+			args:     []string{"disable", "-t", "ghp_flag", "octocat/hello-world"},
+			expected: "Auto-delete branches already disabled for octocat/hello-world",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server, patches := newFakeGitHub(t, tt.enabled)
+			env, stdout, _ := newTestEnvironment(server, "ghp_test")
+
+			// Act
+			err := execute(env, tt.args)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("execute() error = %v, expected nil", err)
+			}
+			if *patches != 0 {
+				t.Errorf("expected no PATCH request, got %d", *patches)
+			}
+			if !strings.Contains(stdout.String(), tt.expected) {
+				t.Errorf("stdout should contain %q, got:\n%s", tt.expected, stdout.String())
+			}
+		})
+	}
+}
+
+// TestDisableCommandRequiresRepository verifies a missing repository exits with code 2.
+func TestDisableCommandRequiresRepository(t *testing.T) {
+	// Arrange
+	env, _, _ := newTestEnvironment(nil, "ghp_test")
+
+	// Act
+	err := execute(env, []string{"disable"})
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 2 {
+		t.Errorf("exit code = %d, expected 2 (err: %v)", code, err)
+	}
+}
+
+// TestAuditCommandExitsWithDriftCode verifies audit fails with exit code 7 on drift.
+func TestAuditCommandExitsWithDriftCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		expected int
+	}{
+		{"non-compliant", false, 7},
+		{"compliant", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server, patches := newFakeGitHub(t, tt.enabled)
+			env, _, _ := newTestEnvironment(server, "ghp_test")
+
+			// Act
+			err := execute(env, []string{"audit", "octocat/hello-world"})
+
+			// Assert
+			if code := apperrors.GetExitCode(err); code != tt.expected {
+				t.Errorf("exit code = %d, expected %d (err: %v)", code, tt.expected, err)
+			}
+			if *patches != 0 {
+				t.Errorf("audit should not PATCH, got %d requests", *patches)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// GitHub Enterprise Server Tests
+// =============================================================================
+
+// TestEnterpriseHostnameDetectedFromURL verifies a GHES URL selects the enterprise token.
+func TestEnterpriseHostnameDetectedFromURL(t *testing.T) {
+	// Arrange
+	server, patches := newFakeGitHub(t, false)
+	env, _, _ := newTestEnvironment(server, "ghp_public")
+	env.getenv = func(key string) string {
+		if key == "GH_ENTERPRISE_TOKEN" {
+			return "ghp_enterprise"
+		}
+		return ""
+	}
+
+	// Act
+	err := execute(env, []string{"https://git.corp.example/octocat/hello-world"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("execute() error = %v, expected nil", err)
+	}
+	if *patches != 1 {
+		t.Errorf("expected 1 PATCH request, got %d", *patches)
+	}
+}
+
+// TestEnterpriseHostnameValidation verifies host mismatches and missing GHES tokens are rejected.
+func TestEnterpriseHostnameValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected int
+	}{
+		{"mixed hosts", []string{"https://git.corp.example/octocat/a", "git@github.com:octocat/b.git"}, 2},
+		{"URL not on hostname", []string{"--hostname", "git.corp.example", "https://github.com/octocat/a"}, 2},
+		{"invalid hostname", []string{"--hostname", "https://git.corp.example", "octocat/a"}, 2},
+		{"GITHUB_TOKEN not sent to GHES", []string{"--hostname", "git.corp.example", "octocat/a"}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			env, _, _ := newTestEnvironment(nil, "ghp_public")
+
+			// Act
+			err := execute(env, tt.args)
+
+			// Assert
+			if code := apperrors.GetExitCode(err); code != tt.expected {
+				t.Errorf("exit code = %d, expected %d (err: %v)", code, tt.expected, err)
+			}
+		})
+	}
+}
+
+// TestGitHubAppFlagValidation verifies incomplete or conflicting GitHub App flags exit with code 2.
+func TestGitHubAppFlagValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"missing installation ID", []string{"--app-id", "1", "--app-private-key-file", "app.pem", "octocat/a"}},
+		{"missing private key", []string{"--app-id", "1", "--app-installation-id", "2", "octocat/a"}},
+		{"combined with --token", []string{"--app-id", "1", "--app-installation-id", "2", "--app-private-key-file", "app.pem", "--token", "ghp_x", "octocat/a"}},
+		{"combined with --user", []string{"--app-id", "1", "--app-installation-id", "2", "--app-private-key-file", "app.pem", "--user"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			env, _, _ := newTestEnvironment(nil, "ghp_test")
+
+			// Act
+			err := execute(env, tt.args)
+
+			// Assert
+			if code := apperrors.GetExitCode(err); code != 2 {
+				t.Errorf("exit code = %d, expected 2 (err: %v)", code, err)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Output Format Tests
+// =============================================================================
+
+// TestJSONOutputWritesRecordsToStdout verifies --output json keeps stdout machine-readable.
+func TestJSONOutputWritesRecordsToStdout(t *testing.T) {
+	// Arrange
+	server, _ := newFakeGitHub(t, false)
+	env, stdout, _ := newTestEnvironment(server, "ghp_test")
+
+	// Act
+	err := execute(env, []string{"--output", "json", "octocat/hello-world"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("execute() error = %v, expected nil", err)
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &record); err != nil {
+		t.Fatalf("stdout is not a single JSON record: %v\n%s", err, stdout.String())
+	}
+	if record["repository"] != "octocat/hello-world" || record["default_branch"] != "main" ||
+		record["previous_state"] != "disabled" || record["new_state"] != "enabled" || record["mode"] != "normal" {
+		t.Errorf("unexpected record: %v", record)
+	}
+}
+
+// TestInvalidConcurrencyExitsWithCode2 verifies --concurrency must be at least 1.
+func TestInvalidConcurrencyExitsWithCode2(t *testing.T) {
+	// Arrange
+	env, _, _ := newTestEnvironment(nil, "ghp_test")
+
+	// Act
+	err := execute(env, []string{"--concurrency", "0", "acme/api", "acme/web"})
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 2 {
+		t.Errorf("exit code = %d, expected 2 (err: %v)", code, err)
+	}
+}
+
+// TestInvalidOutputFormatExitsWithCode2 verifies unknown --output values are rejected.
+func TestInvalidOutputFormatExitsWithCode2(t *testing.T) {
+	// Arrange
+	env, _, _ := newTestEnvironment(nil, "ghp_test")
+
+	// Act
+	err := execute(env, []string{"--output", "yaml", "octocat/hello-world"})
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 2 {
+		t.Errorf("exit code = %d, expected 2 (err: %v)", code, err)
+	}
+}
+
+// TestInvalidAPIExitsWithCode2 verifies unknown --api values are rejected.
+func TestInvalidAPIExitsWithCode2(t *testing.T) {
+	// Arrange
+	env, _, _ := newTestEnvironment(nil, "ghp_test")
+
+	// Act
+	err := execute(env, []string{"--api", "soap", "octocat/hello-world"})
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 2 {
+		t.Errorf("exit code = %d, expected 2 (err: %v)", code, err)
+	}
+}
+
+// TestChangesAreRecordedAndShownByHistory verifies an enabled repository is
+// recorded in the audit log and listed by the history command.
+func TestChangesAreRecordedAndShownByHistory(t *testing.T) {
+	// Arrange
+	server, _ := newFakeGitHub(t, false)
+	env, stdout, _ := newTestEnvironment(server, "ghp_test")
+	env.readFile = os.ReadFile
+	auditLog := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	// Act
+	runErr := execute(env, []string{"--audit-log", auditLog, "octocat/hello-world"})
+	stdout.Reset()
+	historyErr := execute(env, []string{"history", "--audit-log", auditLog, "--repo", "octocat/hello-world", "--since", "2000-01-01"})
+	history := stdout.String()
+	stdout.Reset()
+	otherErr := execute(env, []string{"history", "--audit-log", auditLog, "--repo", "octocat/other"})
+
+	// Assert
+	if runErr != nil || historyErr != nil || otherErr != nil {
+		t.Fatalf("execute() errors = %v, %v, %v", runErr, historyErr, otherErr)
+	}
+	for _, want := range []string{"octocat/hello-world", "delete_branch_on_merge", "false -> true", "octocat", "1 changes"} {
+		if !strings.Contains(history, want) {
+			t.Errorf("history should contain %q, got:\n%s", want, history)
+		}
+	}
+	if !strings.Contains(stdout.String(), "No setting changes recorded") {
+		t.Errorf("history for another repository should be empty, got:\n%s", stdout.String())
+	}
+}
+
+// TestHistoryReadsPastUnreadableLines verifies history accepts a repository URL
+// on the selected host and reports lines it cannot read without dropping the
+// records after them.
+func TestHistoryReadsPastUnreadableLines(t *testing.T) {
+	// Arrange
+	env, stdout, stderr := newTestEnvironment(nil, "ghp_test")
+	auditLog := filepath.Join(t.TempDir(), "audit.jsonl")
+	content := `{"repository":"acme/api","setting":"delete_branch_on_merge","before":"false","after":"true"}` + "\n" +
+		`{"repository":"acme/api","set` + "\n" +
+		`{"repository":"acme/api","setting":"allow_auto_merge","before":"false","after":"true"}` + "\n"
+	if err := os.WriteFile(auditLog, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env.readFile = os.ReadFile
+
+	// Act
+	err := execute(env, []string{"history", "--audit-log", auditLog, "--hostname", "git.corp.example",
+		"--repo", "https://git.corp.example/acme/api"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "allow_auto_merge") || !strings.Contains(stdout.String(), "2 changes") {
+		t.Errorf("history should list both readable records, got:\n%s", stdout.String())
+	}
+	if !strings.Contains(stdout.String()+stderr.String(), "skipped 1 unreadable lines") {
+		t.Errorf("history should report the unreadable line, got:\n%s%s", stdout.String(), stderr.String())
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/manifest"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+	"github.com/spf13/cobra"
+)
+
+const planDescription = `Show the changes needed to reach the desired state in a manifest.
+
+The manifest (ghautodelete.yaml by default) declares repositories and
+organizations and the settings they should have. plan reads each repository's
+current settings, prints every setting that differs and makes no changes.`
+
+const applyDescription = `Apply the desired state in a manifest.
+
+apply computes the same differences as plan and updates only the settings that
+differ, then reads them back to verify. Repositories that are already up to date
+are not modified. With --dry-run, apply behaves exactly like plan.`
+
+const manifestExamples = `  # Preview the changes for the manifest in the current directory
+  ghautodelete plan
+
+  # Apply a manifest stored elsewhere
+  ghautodelete apply -f policies/ghautodelete.yaml`
+
+// newManifestCmds creates the plan and apply commands.
+// They share the root persistent flags through opts; fromFile is only used to
+// reject --from-file, since targets come from the manifest.
+func newManifestCmds(env *environment, opts *interfaces.CLIOptions, fromFile *string) []*cobra.Command {
+	var file string
+
+	planCmd := &cobra.Command{
+		Use:     "plan [flags]",
+		Short:   "Show the changes needed to reach a manifest's desired state",
+		Long:    planDescription,
+		Example: manifestExamples,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			planOpts := *opts
+			planOpts.DryRun = true
+			return runManifestCommand(cmd.Context(), env, planOpts, args, *fromFile, file)
+		},
+	}
+
+	applyCmd := &cobra.Command{
+		Use:     "apply [flags]",
+		Short:   "Apply a manifest's desired state",
+		Long:    applyDescription,
+		Example: manifestExamples,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runManifestCommand(cmd.Context(), env, *opts, args, *fromFile, file)
+		},
+	}
+
+	for _, cmd := range []*cobra.Command{planCmd, applyCmd} {
+		cmd.Flags().StringVarP(&file, "file", "f", manifest.DefaultFile, "Manifest file declaring the desired state")
+	}
+
+	return []*cobra.Command{planCmd, applyCmd}
+}
+
+// runManifestCommand validates the invocation, loads the manifest and runs it.
+func runManifestCommand(ctx context.Context, env *environment, opts interfaces.CLIOptions, args []string, fromFile, file string) error {
+	if len(args) > 0 || fromFile != "" || opts.Organization != "" || opts.User || opts.Search != "" || opts.Enterprise != "" {
+		return errors.NewValidationError("plan and apply take their repositories from the manifest; " +
+			"repository arguments, --from-file, --org, --user, --search and --enterprise are not allowed")
+	}
+	if hasListingFilters(opts) || opts.SkipForks || opts.SkipTemplates {
+		return errors.NewValidationError("plan and apply filter organizations with the manifest's skip_* settings; " +
+			"--skip-forks, --skip-templates, --topic, --visibility, --language, --include, --exclude and --pushed-since are not allowed")
+	}
+	if opts.Resume || opts.Checkpoint != "" {
+		return errors.NewValidationError("plan and apply do not record a checkpoint; --resume and --checkpoint are not allowed")
+	}
+
+	content, err := env.readFile(file)
+	if err != nil {
+		return errors.NewValidationError(fmt.Sprintf("Cannot read manifest %s: %v", file, err))
+	}
+
+	m, err := manifest.Parse(content)
+	if err != nil {
+		return err
+	}
+
+	identifiers := make([]string, 0, len(m.Repositories))
+	for _, repo := range m.Repositories {
+		identifiers = append(identifiers, repo.Name)
+	}
+	opts.Hostname, err = resolveHostname(env, opts.Hostname, identifiers)
+	if err != nil {
+		return err
+	}
+
+	return runManifest(ctx, env, opts, m)
+}
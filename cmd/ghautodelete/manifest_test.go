@@ -0,0 +1,111 @@
+// Package main provides tests for the plan and apply commands.
+//
+// These tests drive plan/apply end to end against a fake GitHub API and verify
+// manifest loading, the dry-run/apply split and argument validation.
+package main
+
+import (
+	"strings"
+	"testing"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+)
+
+// testManifest declares the fake server's only repository.
+const testManifest = `settings:
+  delete_branch_on_merge: true
+repositories:
+  - name: octocat/hello-world
+`
+
+// TestPlanReportsChangesWithoutApplying verifies plan prints the diff and makes no PATCH.
+func TestPlanReportsChangesWithoutApplying(t *testing.T) {
+	// Arrange
+	server, patches := newFakeGitHub(t, false)
+	env, stdout, _ := newTestEnvironment(server, "ghp_test")
+	env.readFile = fakeFiles(map[string]string{"ghautodelete.yaml": testManifest})
+
+	// Act
+	err := execute(env, []string{"plan"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("execute() error = %v, expected nil", err)
+	}
+	if *patches != 0 {
+		t.Errorf("plan should not PATCH, got %d requests", *patches)
+	}
+	for _, want := range []string{
+		"~ octocat/hello-world",
+		"delete_branch_on_merge: false -> true",
+		"octocat/hello-world  would change",
+		"No changes made",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("stdout should contain %q, got:\n%s", want, stdout.String())
+		}
+	}
+}
+
+// TestApplyUpdatesOnlyWhenNeeded verifies apply PATCHes once and is idempotent.
+func TestApplyUpdatesOnlyWhenNeeded(t *testing.T) {
+	// Arrange
+	server, patches := newFakeGitHub(t, false)
+	env, stdout, _ := newTestEnvironment(server, "ghp_test")
+	env.readFile = fakeFiles(map[string]string{"policy.yaml": testManifest})
+
+	// Act
+	firstErr := execute(env, []string{"apply", "-f", "policy.yaml"})
+	secondErr := execute(env, []string{"apply", "--file", "policy.yaml"})
+
+	// Assert
+	if firstErr != nil || secondErr != nil {
+		t.Fatalf("execute() errors = %v, %v, expected nil", firstErr, secondErr)
+	}
+	if *patches != 1 {
+		t.Errorf("expected 1 PATCH request across both runs, got %d", *patches)
+	}
+	for _, want := range []string{"octocat/hello-world  changed", "octocat/hello-world  up to date"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("stdout should contain %q, got:\n%s", want, stdout.String())
+		}
+	}
+}
+
+// TestManifestCommandValidation verifies invalid invocations exit with code 2.
+func TestManifestCommandValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		files map[string]string
+	}{
+		{name: "missing manifest", args: []string{"plan"}, files: map[string]string{}},
+		{name: "invalid manifest", args: []string{"apply"}, files: map[string]string{"ghautodelete.yaml": "repositories: []\n"}},
+		{name: "repository argument", args: []string{"plan", "octocat/hello-world"}, files: map[string]string{"ghautodelete.yaml": testManifest}},
+		{name: "org flag", args: []string{"apply", "--org", "acme"}, files: map[string]string{"ghautodelete.yaml": testManifest}},
+		{name: "search flag", args: []string{"apply", "--search", "org:acme"}, files: map[string]string{"ghautodelete.yaml": testManifest}},
+		{name: "enterprise flag", args: []string{"plan", "--enterprise", "acme-corp"}, files: map[string]string{"ghautodelete.yaml": testManifest}},
+		{name: "topic filter", args: []string{"apply", "--topic", "service"}, files: map[string]string{"ghautodelete.yaml": testManifest}},
+		{name: "visibility filter", args: []string{"plan", "--visibility", "private"}, files: map[string]string{"ghautodelete.yaml": testManifest}},
+		{name: "pushed-since filter", args: []string{"apply", "--pushed-since", "2024-01-01"}, files: map[string]string{"ghautodelete.yaml": testManifest}},
+		{name: "skip forks", args: []string{"apply", "--skip-forks"}, files: map[string]string{"ghautodelete.yaml": testManifest}},
+		{name: "resume", args: []string{"apply", "--resume"}, files: map[string]string{"ghautodelete.yaml": testManifest}},
+		{name: "checkpoint", args: []string{"apply", "--checkpoint", "progress.jsonl"}, files: map[string]string{"ghautodelete.yaml": testManifest}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			env, _, _ := newTestEnvironment(nil, "ghp_test")
+			env.readFile = fakeFiles(tt.files)
+
+			// Act
+			err := execute(env, tt.args)
+
+			// Assert
+			if code := apperrors.GetExitCode(err); code != 2 {
+				t.Errorf("exit code = %d, expected 2 (err: %v)", code, err)
+			}
+		})
+	}
+}
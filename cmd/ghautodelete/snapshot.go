@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/snapshot"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+	"github.com/spf13/cobra"
+)
+
+const snapshotDescription = `Capture the current settings of GitHub repositories into a file.
+
+snapshot reads delete-branch-on-merge and the merge settings of each selected
+repository and writes them, with the time and host, to a versioned snapshot
+file. Repositories are selected exactly as for the root command; nothing is
+changed. Merge settings are only visible to repository admins and are left out
+of the snapshot otherwise. Take a snapshot before a large rollout so it can be
+reverted with restore.`
+
+const restoreDescription = `Put repository settings back to the values in a snapshot.
+
+restore reconciles every repository in the snapshot with its recorded settings:
+only settings that differ are updated, then read back to verify. With --dry-run
+the differences are shown and nothing is changed.`
+
+const snapshotExamples = `  # Capture every repository of an organization before a rollout
+  ghautodelete snapshot --org acme -f before-rollout.yaml
+
+  # Preview reverting the rollout, then revert it
+  ghautodelete restore -f before-rollout.yaml --dry-run
+  ghautodelete restore -f before-rollout.yaml`
+
+// newSnapshotCmds creates the snapshot and restore commands.
+// They share the root persistent flags through opts.
+func newSnapshotCmds(env *environment, opts *interfaces.CLIOptions, fromFile *string) []*cobra.Command {
+	var file string
+
+	snapshotCmd := &cobra.Command{
+		Use:     "snapshot [flags] <repository>...",
+		Short:   "Capture repository settings into a snapshot file",
+		Long:    snapshotDescription,
+		Example: snapshotExamples,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSnapshotCommand(cmd.Context(), env, *opts, args, *fromFile, file)
+		},
+	}
+
+	restoreCmd := &cobra.Command{
+		Use:     "restore [flags]",
+		Short:   "Restore repository settings from a snapshot file",
+		Long:    restoreDescription,
+		Example: snapshotExamples,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRestoreCommand(cmd.Context(), env, *opts, args, *fromFile, file)
+		},
+	}
+
+	for _, cmd := range []*cobra.Command{snapshotCmd, restoreCmd} {
+		cmd.Flags().StringVarP(&file, "file", "f", snapshot.DefaultFile, "Snapshot file")
+	}
+
+	return []*cobra.Command{snapshotCmd, restoreCmd}
+}
+
+// runSnapshotCommand captures the selected repositories and writes the snapshot.
+// The snapshot is written even if some repositories could not be read; their
+// failures are returned as the aggregate batch error.
+func runSnapshotCommand(ctx context.Context, env *environment, opts interfaces.CLIOptions, args []string, fromFile, file string) error {
+	identifiers, _, err := resolveSelection(env, &opts, args, fromFile)
+	if err != nil {
+		return err
+	}
+	if opts.Enterprise != "" {
+		return errors.NewValidationError("snapshot does not support --enterprise; take a snapshot per organization with --org")
+	}
+
+	application, err := newApplication(env, opts)
+	if err != nil {
+		return err
+	}
+	defer application.writeRateLimit()
+
+	snap, captureErr := application.CaptureSnapshot(ctx, opts, identifiers)
+	if snap == nil || len(snap.Repositories) == 0 {
+		return captureErr
+	}
+
+	content, err := snap.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := env.writeFile(file, content, 0o644); err != nil {
+		return errors.NewValidationError(fmt.Sprintf("Cannot write snapshot %s: %v", file, err))
+	}
+	application.writer.Info(fmt.Sprintf("Snapshot of %d repositories written to %s", len(snap.Repositories), file))
+
+	return captureErr
+}
+
+// runRestoreCommand validates the invocation, loads the snapshot and applies it.
+// The repositories are restored on the snapshot's host.
+func runRestoreCommand(ctx context.Context, env *environment, opts interfaces.CLIOptions, args []string, fromFile, file string) error {
+	if len(args) > 0 || fromFile != "" || opts.Organization != "" || opts.User || opts.Search != "" || opts.Enterprise != "" {
+		return errors.NewValidationError("restore takes its repositories from the snapshot; " +
+			"repository arguments, --from-file, --org, --user, --search and --enterprise are not allowed")
+	}
+
+	content, err := env.readFile(file)
+	if err != nil {
+		return errors.NewValidationError(fmt.Sprintf("Cannot read snapshot %s: %v", file, err))
+	}
+
+	snap, err := snapshot.Parse(content)
+	if err != nil {
+		return err
+	}
+
+	identifiers := make([]string, 0, len(snap.Repositories))
+	for _, repo := range snap.Repositories {
+		identifiers = append(identifiers, repo.Name)
+	}
+	if opts.Hostname == "" {
+		opts.Hostname = snap.Hostname
+	}
+	opts.Hostname, err = resolveHostname(env, opts.Hostname, identifiers)
+	if err != nil {
+		return err
+	}
+	if snap.Hostname != "" && opts.Hostname != snap.Hostname {
+		return errors.NewValidationError(fmt.Sprintf(
+			"Snapshot %s was taken on %s, not %s", file, snap.Hostname, opts.Hostname))
+	}
+
+	application, err := newApplication(env, opts)
+	if err != nil {
+		return err
+	}
+	defer application.writeRateLimit()
+
+	application.writer.Info(fmt.Sprintf("Restoring %d repositories from the snapshot taken at %s",
+		len(snap.Repositories), snap.CreatedAt.Format("2006-01-02 15:04:05 MST")))
+	return application.RunManifest(ctx, opts, snap.Manifest())
+}
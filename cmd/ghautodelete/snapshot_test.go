@@ -0,0 +1,92 @@
+// Package main provides tests for the snapshot and restore commands.
+//
+// These tests drive snapshot/restore end to end against a fake GitHub API and
+// verify that a snapshot taken before a change can preview and revert it.
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+)
+
+// TestSnapshotAndRestoreRevertsAChange verifies a snapshot taken before
+// enabling the setting restores it, with restore --dry-run changing nothing.
+func TestSnapshotAndRestoreRevertsAChange(t *testing.T) {
+	// Arrange
+	server, patches := newFakeGitHub(t, false)
+	env, stdout, _ := newTestEnvironment(server, "ghp_test")
+	env.readFile = os.ReadFile
+	env.writeFile = os.WriteFile
+	file := filepath.Join(t.TempDir(), "before.yaml")
+
+	// Act
+	snapshotErr := execute(env, []string{"snapshot", "-f", file, "octocat/hello-world"})
+	enableErr := execute(env, []string{"octocat/hello-world"})
+	stdout.Reset()
+	previewErr := execute(env, []string{"restore", "-f", file, "--dry-run"})
+	preview := stdout.String()
+	patchesAfterPreview := *patches
+	restoreErr := execute(env, []string{"restore", "--file", file})
+
+	// Assert
+	if snapshotErr != nil || enableErr != nil || previewErr != nil || restoreErr != nil {
+		t.Fatalf("execute() errors = %v, %v, %v, %v", snapshotErr, enableErr, previewErr, restoreErr)
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("snapshot file was not written: %v", err)
+	}
+	for _, want := range []string{"version: 1", "name: octocat/hello-world", "delete_branch_on_merge: false"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("snapshot should contain %q, got:\n%s", want, content)
+		}
+	}
+	if !strings.Contains(preview, "delete_branch_on_merge: true -> false") {
+		t.Errorf("restore --dry-run should show the difference, got:\n%s", preview)
+	}
+	if patchesAfterPreview != 1 || *patches != 2 {
+		t.Errorf("PATCH requests = %d after preview and %d after restore, expected 1 and 2", patchesAfterPreview, *patches)
+	}
+}
+
+// TestRestoreCommandValidation verifies invalid invocations exit with code 2.
+func TestRestoreCommandValidation(t *testing.T) {
+	snapshotFile := "version: 1\nhostname: github.com\nrepositories:\n" +
+		"  - name: octocat/hello-world\n    settings:\n      delete_branch_on_merge: false\n"
+
+	tests := []struct {
+		name  string
+		args  []string
+		files map[string]string
+	}{
+		{name: "missing file", args: []string{"restore"}},
+		{name: "repository arguments", args: []string{"restore", "octocat/hello-world"}, files: map[string]string{"ghautodelete-snapshot.yaml": snapshotFile}},
+		{name: "organization", args: []string{"restore", "--org", "acme"}, files: map[string]string{"ghautodelete-snapshot.yaml": snapshotFile}},
+		{name: "other host", args: []string{"restore", "--hostname", "github.example.com"}, files: map[string]string{"ghautodelete-snapshot.yaml": snapshotFile}},
+		{name: "newer version", args: []string{"restore", "-f", "new.yaml"}, files: map[string]string{"new.yaml": strings.Replace(snapshotFile, "version: 1", "version: 2", 1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server, patches := newFakeGitHub(t, true)
+			env, _, _ := newTestEnvironment(server, "ghp_test")
+			env.readFile = fakeFiles(tt.files)
+
+			// Act
+			err := execute(env, tt.args)
+
+			// Assert
+			if code := apperrors.GetExitCode(err); code != 2 {
+				t.Errorf("exit code = %d, expected 2 (err: %v)", code, err)
+			}
+			if *patches != 0 {
+				t.Errorf("no PATCH expected, got %d", *patches)
+			}
+		})
+	}
+}
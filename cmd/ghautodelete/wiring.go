@@ -0,0 +1,368 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/josejulio/ghautodelete/internal/app"
+	"github.com/josejulio/ghautodelete/internal/changelog"
+	"github.com/josejulio/ghautodelete/internal/checkpoint"
+	"github.com/josejulio/ghautodelete/internal/config"
+	"github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/github"
+	"github.com/josejulio/ghautodelete/internal/httpcache"
+	"github.com/josejulio/ghautodelete/internal/manifest"
+	"github.com/josejulio/ghautodelete/internal/output"
+	"github.com/josejulio/ghautodelete/internal/parser"
+	"github.com/josejulio/ghautodelete/internal/token"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// environment holds the process-level dependencies used to build the application.
+//
+// main populates it from the real process; tests substitute their own streams,
+// environment lookups and a test HTTP server.
+type environment struct {
+	out        io.Writer
+	errOut     io.Writer
+	getenv     func(string) string
+	homeDir    func() (string, error)
+	cacheDir   func() (string, error)
+	configDir  func() (string, error)
+	readFile   func(string) ([]byte, error)
+	writeFile  func(string, []byte, os.FileMode) error
+	runCommand token.CommandRunner
+	httpClient *http.Client
+
+	// baseURL overrides the API endpoint derived from the hostname when set.
+	baseURL string
+}
+
+// application is the wired App together with the dependencies the commands
+// report on once a run has finished.
+type application struct {
+	*app.App
+	writer interfaces.IOutputWriter
+	client *github.GitHubClient
+}
+
+// writeRateLimit writes the remaining REST and GraphQL API rate-limit budgets,
+// and how many responses the cache saved, to the verbose output.
+func (a *application) writeRateLimit() {
+	if budget, ok := a.client.RateLimit(); ok {
+		a.writer.Verbose(fmt.Sprintf("GitHub API rate limit: %d of %d remaining (%d used), resets at %s",
+			budget.Remaining, budget.Limit, budget.Used, budget.Reset.Format(time.RFC3339)))
+	}
+	if budget, ok := a.client.GraphQLRateLimit(); ok {
+		a.writer.Verbose(fmt.Sprintf("GitHub GraphQL rate limit: %d of %d remaining (%d used), resets at %s",
+			budget.Remaining, budget.Limit, budget.Used, budget.Reset.Format(time.RFC3339)))
+	}
+	if hits := a.client.CacheHits(); hits > 0 {
+		a.writer.Verbose(fmt.Sprintf("Served %d API responses from the cache (not counted against the rate limit)", hits))
+	}
+}
+
+// newApplication wires the application dependencies for the given options.
+// extra supplies further App options, e.g. a checkpoint.
+func newApplication(env *environment, opts interfaces.CLIOptions, extra ...app.Option) (*application, error) {
+	writer := output.NewOutputWriter(opts.Verbose, env.out, env.errOut)
+	appOpts := append([]app.Option{}, extra...)
+
+	switch opts.Output {
+	case "", output.FormatText:
+	case output.FormatJSON:
+		// Keep stdout machine-readable: text messages go to stderr instead
+		writer = output.NewOutputWriter(opts.Verbose, env.errOut, env.errOut)
+		appOpts = append(appOpts, app.WithResultReporter(output.NewJSONReporter(env.out)))
+	default:
+		return nil, errors.NewValidationError(fmt.Sprintf("Invalid output format %q. Expected %s or %s",
+			opts.Output, output.FormatText, output.FormatJSON))
+	}
+
+	if opts.Concurrency < 1 {
+		return nil, errors.NewValidationError(fmt.Sprintf("Invalid concurrency %d. Expected at least 1", opts.Concurrency))
+	}
+
+	switch opts.API {
+	case "", github.BackendREST, github.BackendGraphQL:
+	default:
+		return nil, errors.NewValidationError(fmt.Sprintf("Invalid API %q. Expected %s or %s",
+			opts.API, github.BackendREST, github.BackendGraphQL))
+	}
+
+	hostname := opts.Hostname
+	if hostname == "" {
+		hostname = github.DefaultHostname
+	}
+
+	baseURL := env.baseURL
+	if baseURL == "" {
+		baseURL = github.BaseURLForHostname(hostname)
+	}
+
+	client, err := newGitHubClient(env, opts, writer, hostname, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	writer.Verbose(fmt.Sprintf("Using GitHub API at %s", baseURL))
+
+	var apiClient interfaces.IGitHubClient = client
+	if opts.API == github.BackendGraphQL {
+		writer.Verbose("Reading repositories with the GraphQL API")
+		apiClient = github.NewGraphQLClient(client)
+	}
+
+	var svcOpts []config.ServiceOption
+	if path := auditLogPath(env, opts); path != "" && !opts.NoAuditLog {
+		runID := changelog.NewRunID()
+		writer.Verbose(fmt.Sprintf("Recording setting changes in %s (run %s)", path, runID))
+		svcOpts = append(svcOpts, config.WithChangeLog(changelog.NewFileLog(path, version, runID)))
+	}
+
+	configSvc := config.NewConfigService(apiClient, writer, svcOpts...)
+	appOpts = append(appOpts, app.WithGitHubClient(apiClient))
+	return &application{
+		App:    app.NewApp(writer, configSvc, parser.NewRepoParserForHostname(hostname), appOpts...),
+		writer: writer,
+		client: client,
+	}, nil
+}
+
+// newGitHubClient creates the GitHub client. It authenticates as a GitHub App
+// installation when an App is configured, and otherwise resolves a token up front
+// so a missing token is reported before any request is made. The token sources
+// tried are traced to the verbose output.
+func newGitHubClient(env *environment, opts interfaces.CLIOptions, writer interfaces.IOutputWriter, hostname, baseURL string) (*github.GitHubClient, error) {
+	if opts.AppID == 0 && opts.AppInstallationID == 0 && opts.AppPrivateKeyFile == "" {
+		order, err := token.ParseSourceOrder(opts.TokenSources)
+		if err != nil {
+			return nil, err
+		}
+		if err := token.CheckConfiguredSources(order, opts.TokenFile, opts.TokenCommand); err != nil {
+			return nil, err
+		}
+
+		tokenProvider := token.NewTokenProviderForHostname(hostname, opts.Token, env.getenv, env.homeDir, env.readFile,
+			token.WithSourceOrder(order),
+			token.WithTokenFile(opts.TokenFile),
+			token.WithTokenCommand(opts.TokenCommand),
+			token.WithCommandRunner(env.runCommand),
+			token.WithTrace(writer.Verbose))
+		apiToken, err := tokenProvider.GetToken()
+		if err != nil {
+			return nil, err
+		}
+		return github.NewGitHubClient(env.httpClient, baseURL, apiToken, clientOptions(env, opts, writer)...), nil
+	}
+
+	if opts.AppID == 0 || opts.AppInstallationID == 0 || opts.AppPrivateKeyFile == "" {
+		return nil, errors.NewValidationError("--app-id, --app-installation-id and --app-private-key-file must be used together")
+	}
+	if opts.Token != "" || opts.TokenFile != "" || opts.TokenCommand != "" {
+		return nil, errors.NewValidationError("--token, --token-file and --token-command cannot be combined with GitHub App authentication")
+	}
+
+	privateKey, err := env.readFile(opts.AppPrivateKeyFile)
+	if err != nil {
+		return nil, errors.NewValidationError(fmt.Sprintf("Cannot read GitHub App private key %s: %v", opts.AppPrivateKeyFile, err))
+	}
+	appTokens, err := token.NewAppTokenProvider(env.httpClient, baseURL, opts.AppID, opts.AppInstallationID, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return github.NewGitHubClientWithTokenProvider(env.httpClient, baseURL, appTokens, clientOptions(env, opts, writer)...), nil
+}
+
+// clientOptions returns the GitHub client options for the given CLI options:
+// the rate-limit governor and, unless disabled, the on-disk response cache.
+// Pacing and waiting are announced on the info output, since they slow the run down.
+func clientOptions(env *environment, opts interfaces.CLIOptions, writer interfaces.IOutputWriter) []github.ClientOption {
+	clientOpts := []github.ClientOption{
+		github.WithRateLimiter(github.NewRateLimiter(opts.WaitOnRateLimit, github.WithRateLimitNotify(writer.Info))),
+	}
+	if dir := responseCacheDir(env, opts); dir != "" {
+		writer.Verbose(fmt.Sprintf("Caching API responses in %s", dir))
+		cache := httpcache.NewDiskCache(dir)
+		if removed, err := cache.Prune(httpcache.DefaultMaxAge); err != nil {
+			writer.Verbose(fmt.Sprintf("Cannot prune the response cache: %v", err))
+		} else if removed > 0 {
+			writer.Verbose(fmt.Sprintf("Removed %d unused responses from the cache", removed))
+		}
+		clientOpts = append(clientOpts, github.WithResponseCache(cache))
+	}
+	return clientOpts
+}
+
+// responseCacheDir returns the response cache directory, or "" when caching is
+// disabled or no user cache directory is available.
+func responseCacheDir(env *environment, opts interfaces.CLIOptions) string {
+	if opts.NoCache {
+		return ""
+	}
+	if opts.CacheDir != "" {
+		return opts.CacheDir
+	}
+	if env.cacheDir == nil {
+		return ""
+	}
+	dir, err := env.cacheDir()
+	if err != nil || dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "ghautodelete", "http")
+}
+
+// auditLogPath returns the audit log file, or "" when no user configuration
+// directory is available and none was given.
+func auditLogPath(env *environment, opts interfaces.CLIOptions) string {
+	if opts.AuditLog != "" {
+		return opts.AuditLog
+	}
+	if env.configDir == nil {
+		return ""
+	}
+	dir, err := env.configDir()
+	if err != nil || dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "ghautodelete", changelog.DefaultFile)
+}
+
+// checkpointPath returns the checkpoint file, or "" when checkpoints are
+// disabled or no user cache directory is available and none was given.
+// Only enable and disable runs that modify repositories record a checkpoint by
+// default, in a file named after the operation and selection so runs over other
+// repositories keep their own; checks, dry runs and audits record one only at an
+// explicit path.
+func checkpointPath(env *environment, opts interfaces.CLIOptions, operation, selection string) string {
+	if opts.NoCheckpoint {
+		return ""
+	}
+	if opts.Checkpoint != "" {
+		return opts.Checkpoint
+	}
+	if !app.ModifiesRepositories(opts) || env.cacheDir == nil {
+		return ""
+	}
+	dir, err := env.cacheDir()
+	if err != nil || dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "ghautodelete", "checkpoints", checkpoint.DefaultFileName(operation, selection, opts.Hostname))
+}
+
+// openCheckpoint starts the checkpoint of a multi-repository run, or continues
+// it with opts.Resume. Returns nil when checkpoints are disabled. The file is
+// only written once the first repository completes.
+func openCheckpoint(env *environment, opts interfaces.CLIOptions, identifiers []string) (*checkpoint.File, string, error) {
+	operation := app.CheckpointOperation(opts)
+	selection := app.CheckpointSelection(opts, identifiers)
+	path := checkpointPath(env, opts, operation, selection)
+
+	switch {
+	case opts.Resume && opts.NoCheckpoint:
+		return nil, "", errors.NewValidationError("--resume cannot be combined with --no-checkpoint")
+	case opts.Resume && path == "":
+		return nil, "", errors.NewValidationError("--resume requires a checkpoint file; set one with --checkpoint")
+	case opts.Resume:
+		file, err := checkpoint.Resume(path, operation, selection, opts.Hostname)
+		return file, path, err
+	case path == "":
+		return nil, "", nil
+	}
+	file, err := checkpoint.Create(path, operation, selection, opts.Hostname)
+	return file, path, err
+}
+
+// runApp wires the application dependencies and runs it against the given repositories.
+// A single positional repository keeps the single-repository output; anything else
+// (several repositories, or a list file) runs in batch mode. When an organization,
+// user, search or enterprise is selected, the repositories are listed instead of
+// using identifiers.
+//
+// Multi-repository runs record their progress in a checkpoint; after a failed or
+// interrupted run, opts.Resume skips the repositories that already succeeded.
+func runApp(ctx context.Context, env *environment, opts interfaces.CLIOptions, identifiers []string, batch bool) error {
+	multiple := batch || len(identifiers) > 1 ||
+		opts.Organization != "" || opts.User || opts.Search != "" || opts.Enterprise != ""
+	if !multiple {
+		if opts.Resume {
+			return errors.NewValidationError("--resume applies to runs over several repositories")
+		}
+		opts.NoCheckpoint = true
+	}
+
+	progress, path, err := openCheckpoint(env, opts, identifiers)
+	if err != nil {
+		return err
+	}
+	var extra []app.Option
+	if progress != nil {
+		defer progress.Close()
+		extra = append(extra, app.WithCheckpoint(progress))
+	}
+
+	application, err := newApplication(env, opts, extra...)
+	if err != nil {
+		return err
+	}
+	defer application.writeRateLimit()
+
+	if progress == nil {
+		return runSelection(ctx, application, opts, identifiers, batch)
+	}
+
+	application.writer.Verbose(fmt.Sprintf("Recording progress in %s", path))
+	err = runSelection(ctx, application, opts, identifiers, batch)
+	// A validation error means nothing was processed, so there is nothing to resume,
+	// and a resumed check, dry run or audit skips nothing
+	if err != nil && errors.GetExitCode(err) != int(errors.ErrInvalidArguments) && app.ModifiesRepositories(opts) {
+		application.writer.Info(fmt.Sprintf(
+			"Progress was recorded in %s. Run again with --resume to skip the repositories that succeeded", path))
+	}
+	return err
+}
+
+// runSelection runs the application in the mode selected by opts.
+func runSelection(ctx context.Context, application *application, opts interfaces.CLIOptions, identifiers []string, batch bool) error {
+
+	if opts.Organization != "" {
+		return application.RunOrganization(ctx, opts)
+	}
+
+	if opts.User {
+		return application.RunUser(ctx, opts)
+	}
+
+	if opts.Search != "" {
+		return application.RunSearch(ctx, opts)
+	}
+
+	if opts.Enterprise != "" {
+		return application.RunEnterprise(ctx, opts)
+	}
+
+	if batch || len(identifiers) > 1 {
+		return application.RunBatch(ctx, opts, identifiers)
+	}
+
+	opts.Repository = identifiers[0]
+	return application.Run(ctx, opts)
+}
+
+// runManifest wires the application dependencies and reconciles the manifest.
+// opts.DryRun selects plan (true) or apply (false).
+func runManifest(ctx context.Context, env *environment, opts interfaces.CLIOptions, m *manifest.Manifest) error {
+	application, err := newApplication(env, opts)
+	if err != nil {
+		return err
+	}
+	defer application.writeRateLimit()
+
+	return application.RunManifest(ctx, opts, m)
+}
@@ -5,12 +5,16 @@
 // - Check mode: Shows current status without making changes
 // - Dry-run mode: Shows what would happen without making changes
 // - Normal mode: Actually enables auto-delete branches
+//
+// Dry-run and normal mode can also turn the feature off (see disable.go), and
+// audit mode checks for drift (see audit.go).
 package app
 
 import (
 	"context"
 	"fmt"
 
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
 	"github.com/josejulio/ghautodelete/pkg/interfaces"
 )
 
@@ -18,19 +22,38 @@ import (
 // It coordinates between the repository parser, configuration service, and output writer
 // to handle different operational modes (check, dry-run, normal).
 type App struct {
-	writer    interfaces.IOutputWriter
-	configSvc interfaces.IConfigService
-	parser    interfaces.IRepoParser
+	writer     interfaces.IOutputWriter
+	configSvc  interfaces.IConfigService
+	parser     interfaces.IRepoParser
+	client     interfaces.IGitHubClient
+	reporter   interfaces.IResultReporter
+	checkpoint interfaces.ICheckpoint
+}
+
+// Option configures optional App dependencies.
+type Option func(*App)
+
+// WithGitHubClient sets the GitHub client used to list repositories
+// (e.g., all repositories of an organization).
+func WithGitHubClient(client interfaces.IGitHubClient) Option {
+	return func(a *App) {
+		a.client = client
+	}
 }
 
 // NewApp creates a new App with the provided dependencies.
 // All dependencies are injected via interfaces for testability.
-func NewApp(writer interfaces.IOutputWriter, configSvc interfaces.IConfigService, parser interfaces.IRepoParser) *App {
-	return &App{
+// Optional dependencies are supplied as Option values.
+func NewApp(writer interfaces.IOutputWriter, configSvc interfaces.IConfigService, parser interfaces.IRepoParser, opts ...Option) *App {
+	a := &App{
 		writer:    writer,
 		configSvc: configSvc,
 		parser:    parser,
 	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
 }
 
 // Run executes the application logic based on the provided CLI options.
@@ -39,10 +62,34 @@ func NewApp(writer interfaces.IOutputWriter, configSvc interfaces.IConfigService
 // - Dry-run mode (opts.DryRun): Shows what would happen without modification
 // - Normal mode: Actually enables auto-delete branches
 //
-// Returns an error if repository parsing fails or if the configuration service fails.
+// When opts.Disable is set, dry-run and normal mode turn the feature off instead.
+// When opts.Audit is set, a drift error is returned if the repository is non-compliant.
+// When a result reporter is set, the outcome is also reported in machine-readable form.
+//
+// Returns an error if repository parsing fails, if the token is invalid or lacks the
+// scopes needed for the selected mode, or if the configuration service fails.
 func (a *App) Run(ctx context.Context, opts interfaces.CLIOptions) error {
 	// Parse repository identifier to extract owner and name
 	owner, name, err := a.parser.Parse(opts.Repository)
+	ref := repositoryRef{identifier: opts.Repository, owner: owner, name: name, err: err}
+
+	// Validate the token before touching a well-formed repository
+	if ref.err == nil {
+		if _, err := a.validateToken(ctx, opts); err != nil {
+			return err
+		}
+	}
+
+	// Audit mode reports a single repository with the same table and drift error as a batch
+	if opts.Audit {
+		return a.runRepositories(ctx, opts, []repositoryRef{ref})
+	}
+
+	// With a result reporter the repository is processed and reported like a batch entry
+	if a.reporter != nil {
+		return a.processRepository(ctx, opts, ref).Err
+	}
+
 	if err != nil {
 		return fmt.Errorf("failed to parse repository: %w", err)
 	}
@@ -52,6 +99,13 @@ func (a *App) Run(ctx context.Context, opts interfaces.CLIOptions) error {
 		return a.handleCheckMode(ctx, owner, name)
 	}
 
+	if opts.Disable {
+		if opts.DryRun {
+			return a.handleDisableDryRunMode(ctx, owner, name)
+		}
+		return a.handleDisableMode(ctx, owner, name)
+	}
+
 	// Dry-run mode
 	if opts.DryRun {
 		return a.handleDryRunMode(ctx, owner, name)
@@ -61,6 +115,13 @@ func (a *App) Run(ctx context.Context, opts interfaces.CLIOptions) error {
 	return a.handleNormalMode(ctx, owner, name)
 }
 
+// validateToken validates the token before any repository is processed.
+// Write access is only required outside check, audit and dry-run mode.
+func (a *App) validateToken(ctx context.Context, opts interfaces.CLIOptions) (interfaces.ITokenInfo, error) {
+	write := !opts.CheckOnly && !opts.Audit && !opts.DryRun
+	return a.configSvc.ValidateToken(ctx, write)
+}
+
 // handleCheckMode handles check-only mode.
 // It retrieves the current status and outputs it without making any changes.
 func (a *App) handleCheckMode(ctx context.Context, owner, name string) error {
@@ -127,5 +188,5 @@ func (a *App) handleNormalMode(ctx context.Context, owner, name string) error {
 	}
 
 	// This shouldn't happen, but handle it just in case
-	return fmt.Errorf("unexpected state: feature was not enabled")
+	return apperrors.NewVerificationError(result.GetRepositoryFullName(), "feature was not enabled")
 }
@@ -25,9 +25,11 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/josejulio/ghautodelete/internal/app"
+	"github.com/josejulio/ghautodelete/internal/token"
 	"github.com/josejulio/ghautodelete/pkg/interfaces"
 )
 
@@ -58,6 +60,18 @@ type mockGitHubClient struct {
 
 	// ValidateTokenFunc is called when ValidateToken is invoked.
 	ValidateTokenFunc func(ctx context.Context) (interfaces.ITokenInfo, error)
+
+	// ListOrganizationRepositoriesFunc is called when ListOrganizationRepositories is invoked.
+	ListOrganizationRepositoriesFunc func(ctx context.Context, org string) ([]interfaces.IRepository, error)
+
+	// ListUserRepositoriesFunc is called when ListUserRepositories is invoked.
+	ListUserRepositoriesFunc func(ctx context.Context, affiliation string) ([]interfaces.IRepository, error)
+
+	// SearchRepositoriesFunc is called when SearchRepositories is invoked.
+	SearchRepositoriesFunc func(ctx context.Context, query string) ([]interfaces.IRepository, int, error)
+
+	// ListEnterpriseOrganizationsFunc is called when ListEnterpriseOrganizations is invoked.
+	ListEnterpriseOrganizationsFunc func(ctx context.Context, slug string) ([]string, error)
 }
 
 func (m *mockGitHubClient) GetRepository(ctx context.Context, owner, name string) (interfaces.IRepository, error) {
@@ -92,8 +106,38 @@ func (m *mockGitHubClient) ValidateToken(ctx context.Context) (interfaces.IToken
 	return nil, errors.New("ValidateTokenFunc not set")
 }
 
+func (m *mockGitHubClient) ListOrganizationRepositories(ctx context.Context, org string) ([]interfaces.IRepository, error) {
+	if m.ListOrganizationRepositoriesFunc != nil {
+		return m.ListOrganizationRepositoriesFunc(ctx, org)
+	}
+	return nil, errors.New("ListOrganizationRepositoriesFunc not set")
+}
+
+func (m *mockGitHubClient) ListUserRepositories(ctx context.Context, affiliation string) ([]interfaces.IRepository, error) {
+	if m.ListUserRepositoriesFunc != nil {
+		return m.ListUserRepositoriesFunc(ctx, affiliation)
+	}
+	return nil, errors.New("ListUserRepositoriesFunc not set")
+}
+
+func (m *mockGitHubClient) SearchRepositories(ctx context.Context, query string) ([]interfaces.IRepository, int, error) {
+	if m.SearchRepositoriesFunc != nil {
+		return m.SearchRepositoriesFunc(ctx, query)
+	}
+	return nil, 0, errors.New("SearchRepositoriesFunc not set")
+}
+
+func (m *mockGitHubClient) ListEnterpriseOrganizations(ctx context.Context, slug string) ([]string, error) {
+	if m.ListEnterpriseOrganizationsFunc != nil {
+		return m.ListEnterpriseOrganizationsFunc(ctx, slug)
+	}
+	return nil, errors.New("ListEnterpriseOrganizationsFunc not set")
+}
+
 // mockOutputWriter implements IOutputWriter for testing with string capture.
+// It is safe for concurrent use.
 type mockOutputWriter struct {
+	mu sync.Mutex
 	// Messages captures all messages by type.
 	SuccessCalls []string
 	ErrorCalls   []string
@@ -104,21 +148,29 @@ type mockOutputWriter struct {
 }
 
 func (m *mockOutputWriter) Success(message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.SuccessCalls = append(m.SuccessCalls, message)
 	m.AllMessages = append(m.AllMessages, message)
 }
 
 func (m *mockOutputWriter) Error(message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.ErrorCalls = append(m.ErrorCalls, message)
 	m.AllMessages = append(m.AllMessages, message)
 }
 
 func (m *mockOutputWriter) Info(message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.InfoCalls = append(m.InfoCalls, message)
 	m.AllMessages = append(m.AllMessages, message)
 }
 
 func (m *mockOutputWriter) Verbose(message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.VerboseCalls = append(m.VerboseCalls, message)
 	m.AllMessages = append(m.AllMessages, message)
 }
@@ -129,7 +181,10 @@ func (m *mockOutputWriter) GetAllOutput() string {
 }
 
 // mockConfigService implements IConfigService for testing.
+// Recording Configure and CheckStatus calls is safe for concurrent use.
 type mockConfigService struct {
+	mu sync.Mutex
+
 	// ConfigureFunc is called when Configure is invoked.
 	ConfigureFunc func(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error)
 	// ConfigureCalls tracks all calls to Configure.
@@ -148,15 +203,43 @@ type mockConfigService struct {
 		Owner string
 		Name  string
 	}
+
+	// DisableFunc is called when Disable is invoked.
+	DisableFunc func(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error)
+	// DisableCalls tracks all calls to Disable.
+	DisableCalls []struct {
+		Ctx    context.Context
+		Owner  string
+		Name   string
+		DryRun bool
+	}
+
+	// ReconcileFunc is called when Reconcile is invoked.
+	ReconcileFunc func(ctx context.Context, owner, name string, desired interfaces.IRepositorySettings, dryRun bool) ([]interfaces.SettingChange, error)
+	// ReconcileCalls tracks all calls to Reconcile.
+	ReconcileCalls []struct {
+		Ctx     context.Context
+		Owner   string
+		Name    string
+		Desired interfaces.IRepositorySettings
+		DryRun  bool
+	}
+
+	// ValidateTokenFunc is called when ValidateToken is invoked.
+	ValidateTokenFunc func(ctx context.Context, write bool) (interfaces.ITokenInfo, error)
+	// ValidateTokenCalls tracks the write argument of all calls to ValidateToken.
+	ValidateTokenCalls []bool
 }
 
 func (m *mockConfigService) Configure(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+	m.mu.Lock()
 	m.ConfigureCalls = append(m.ConfigureCalls, struct {
 		Ctx    context.Context
 		Owner  string
 		Name   string
 		DryRun bool
 	}{ctx, owner, name, dryRun})
+	m.mu.Unlock()
 	if m.ConfigureFunc != nil {
 		return m.ConfigureFunc(ctx, owner, name, dryRun)
 	}
@@ -164,17 +247,56 @@ func (m *mockConfigService) Configure(ctx context.Context, owner, name string, d
 }
 
 func (m *mockConfigService) CheckStatus(ctx context.Context, owner, name string) (interfaces.IConfigResult, error) {
+	m.mu.Lock()
 	m.CheckStatusCalls = append(m.CheckStatusCalls, struct {
 		Ctx   context.Context
 		Owner string
 		Name  string
 	}{ctx, owner, name})
+	m.mu.Unlock()
 	if m.CheckStatusFunc != nil {
 		return m.CheckStatusFunc(ctx, owner, name)
 	}
 	return nil, errors.New("CheckStatusFunc not set")
 }
 
+func (m *mockConfigService) Disable(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+	m.DisableCalls = append(m.DisableCalls, struct {
+		Ctx    context.Context
+		Owner  string
+		Name   string
+		DryRun bool
+	}{ctx, owner, name, dryRun})
+	if m.DisableFunc != nil {
+		return m.DisableFunc(ctx, owner, name, dryRun)
+	}
+	return nil, errors.New("DisableFunc not set")
+}
+
+func (m *mockConfigService) Reconcile(ctx context.Context, owner, name string, desired interfaces.IRepositorySettings, dryRun bool) ([]interfaces.SettingChange, error) {
+	m.ReconcileCalls = append(m.ReconcileCalls, struct {
+		Ctx     context.Context
+		Owner   string
+		Name    string
+		Desired interfaces.IRepositorySettings
+		DryRun  bool
+	}{ctx, owner, name, desired, dryRun})
+	if m.ReconcileFunc != nil {
+		return m.ReconcileFunc(ctx, owner, name, desired, dryRun)
+	}
+	return nil, errors.New("ReconcileFunc not set")
+}
+
+// ValidateToken accepts any token unless ValidateTokenFunc is set, so tests that
+// are not about token validation need no setup.
+func (m *mockConfigService) ValidateToken(ctx context.Context, write bool) (interfaces.ITokenInfo, error) {
+	m.ValidateTokenCalls = append(m.ValidateTokenCalls, write)
+	if m.ValidateTokenFunc != nil {
+		return m.ValidateTokenFunc(ctx, write)
+	}
+	return token.NewTokenInfo("", nil), nil
+}
+
 // mockConfigResult implements IConfigResult for testing.
 type mockConfigResult struct {
 	wasAlreadyEnabled  bool
@@ -1387,3 +1509,81 @@ func TestRunCheckOnlyTakesPrecedenceOverDryRun(t *testing.T) {
 		t.Errorf("Configure should NOT be called when CheckOnly=true, called %d times", len(mockConfigSvc.ConfigureCalls))
 	}
 }
+
+// =============================================================================
+// Token Validation Tests
+// =============================================================================
+
+// TestRunValidatesTokenBeforeProcessing verifies the token is validated once per run,
+// requiring write access only when the repository may be changed.
+func TestRunValidatesTokenBeforeProcessing(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          interfaces.CLIOptions
+		expectedWrite bool
+	}{
+		{"normal mode", interfaces.CLIOptions{}, true},
+		{"dry-run mode", interfaces.CLIOptions{DryRun: true}, false},
+		{"check mode", interfaces.CLIOptions{CheckOnly: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			result := newMockConfigResult(true, true, "main", "octocat/hello-world")
+			mockConfigSvc := &mockConfigService{
+				ConfigureFunc: func(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+					return result, nil
+				},
+				CheckStatusFunc: func(ctx context.Context, owner, name string) (interfaces.IConfigResult, error) {
+					return result, nil
+				},
+			}
+			mockParser := &mockRepoParser{
+				ParseFunc: func(repoIdentifier string) (string, string, error) {
+					return "octocat", "hello-world", nil
+				},
+			}
+			application := app.NewApp(&mockOutputWriter{}, mockConfigSvc, mockParser)
+			tt.opts.Repository = "octocat/hello-world"
+
+			// Act
+			err := application.Run(context.Background(), tt.opts)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Run() error = %v, expected nil", err)
+			}
+			if len(mockConfigSvc.ValidateTokenCalls) != 1 || mockConfigSvc.ValidateTokenCalls[0] != tt.expectedWrite {
+				t.Errorf("ValidateToken calls = %v, expected one call with write=%v", mockConfigSvc.ValidateTokenCalls, tt.expectedWrite)
+			}
+		})
+	}
+}
+
+// TestRunStopsWhenTokenLacksScope verifies no repository is processed after a failed validation.
+func TestRunStopsWhenTokenLacksScope(t *testing.T) {
+	// Arrange
+	mockConfigSvc := &mockConfigService{
+		ValidateTokenFunc: func(ctx context.Context, write bool) (interfaces.ITokenInfo, error) {
+			return nil, errors.New("token lacks the 'repo' scope")
+		},
+	}
+	mockParser := &mockRepoParser{
+		ParseFunc: func(repoIdentifier string) (string, string, error) {
+			return "octocat", "hello-world", nil
+		},
+	}
+	application := app.NewApp(&mockOutputWriter{}, mockConfigSvc, mockParser)
+
+	// Act
+	err := application.RunBatch(context.Background(), interfaces.CLIOptions{}, []string{"octocat/hello-world"})
+
+	// Assert
+	if err == nil {
+		t.Fatal("RunBatch() error = nil, expected validation error")
+	}
+	if len(mockConfigSvc.ConfigureCalls) != 0 {
+		t.Errorf("Configure should not be called, called %d times", len(mockConfigSvc.ConfigureCalls))
+	}
+}
@@ -0,0 +1,42 @@
+// Package app provides the audit workflow.
+//
+// Audit mode checks one or many repositories without modifying them, reports
+// the repositories that do not have auto-delete branches enabled as
+// non-compliant, and fails with a dedicated drift error so it can gate a pipeline.
+package app
+
+import (
+	"fmt"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+)
+
+// Audit status values reported in the result table.
+const (
+	statusCompliant    = "compliant"
+	statusNonCompliant = "non-compliant"
+)
+
+// auditError summarizes an audit and returns its aggregate error.
+//
+// Failures take precedence, since the compliance of a failed repository is unknown.
+// Otherwise returns a drift error if any repository is non-compliant.
+func (a *App) auditError(results []RepositoryResult) error {
+	if err := batchError(results); err != nil {
+		return err
+	}
+
+	nonCompliant := 0
+	for _, result := range results {
+		if result.Status == statusNonCompliant {
+			nonCompliant++
+		}
+	}
+
+	if nonCompliant == 0 {
+		a.writer.Success(fmt.Sprintf("All %d repositories are compliant", len(results)))
+		return nil
+	}
+
+	return apperrors.NewDriftError(nonCompliant, len(results))
+}
@@ -0,0 +1,102 @@
+// Package app_test provides tests for audit mode in the App.
+//
+// These tests verify that audit mode only checks status, marks repositories with
+// auto-delete disabled as non-compliant and returns the drift exit code.
+package app_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/josejulio/ghautodelete/internal/app"
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// newAuditConfigService returns a config service whose CheckStatus reports
+// delete-branch-on-merge enabled for every repository except the disabled ones.
+func newAuditConfigService(disabled ...string) *mockConfigService {
+	return &mockConfigService{
+		CheckStatusFunc: func(ctx context.Context, owner, name string) (interfaces.IConfigResult, error) {
+			enabled := true
+			for _, d := range disabled {
+				if d == name {
+					enabled = false
+				}
+			}
+			return newMockConfigResult(enabled, enabled, "main", owner+"/"+name), nil
+		},
+	}
+}
+
+// TestAuditReportsDrift verifies non-compliant repositories produce the drift exit code.
+func TestAuditReportsDrift(t *testing.T) {
+	// Arrange
+	mockWriter := &mockOutputWriter{}
+	mockConfigSvc := newAuditConfigService("web")
+	application := app.NewApp(mockWriter, mockConfigSvc, splitOwnerRepoParser())
+	opts := interfaces.CLIOptions{Audit: true}
+
+	// Act
+	err := application.RunBatch(context.Background(), opts, []string{"acme/api", "acme/web"})
+
+	// Assert
+	if apperrors.GetExitCode(err) != int(apperrors.ErrDriftDetected) {
+		t.Fatalf("exit code = %d, expected %d (err: %v)", apperrors.GetExitCode(err), apperrors.ErrDriftDetected, err)
+	}
+	if err.Error() != "1 of 2 repositories are non-compliant" {
+		t.Errorf("error = %q, expected drift summary", err.Error())
+	}
+	if len(mockConfigSvc.ConfigureCalls) != 0 || len(mockConfigSvc.DisableCalls) != 0 {
+		t.Error("audit should never modify repositories")
+	}
+	output := mockWriter.GetAllOutput()
+	if !strings.Contains(output, "acme/api    compliant") || !strings.Contains(output, "acme/web    non-compliant") {
+		t.Errorf("result table should mark each repository, got:\n%s", output)
+	}
+}
+
+// TestAuditSucceedsWhenCompliant verifies a fully compliant audit exits 0.
+func TestAuditSucceedsWhenCompliant(t *testing.T) {
+	// Arrange
+	mockWriter := &mockOutputWriter{}
+	application := app.NewApp(mockWriter, newAuditConfigService(), splitOwnerRepoParser())
+	opts := interfaces.CLIOptions{Audit: true, Repository: "octocat/hello-world"}
+
+	// Act
+	err := application.Run(context.Background(), opts)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Run() error = %v, expected nil", err)
+	}
+	if !strings.Contains(strings.Join(mockWriter.SuccessCalls, "\n"), "All 1 repositories are compliant") {
+		t.Errorf("expected compliance summary, got %v", mockWriter.SuccessCalls)
+	}
+}
+
+// TestAuditFailuresTakePrecedence verifies failures keep their own exit code.
+func TestAuditFailuresTakePrecedence(t *testing.T) {
+	// Arrange
+	mockConfigSvc := newAuditConfigService("web")
+	checkStatus := mockConfigSvc.CheckStatusFunc
+	mockConfigSvc.CheckStatusFunc = func(ctx context.Context, owner, name string) (interfaces.IConfigResult, error) {
+		if name == "missing" {
+			return nil, apperrors.NewRepositoryNotFoundError(owner, name)
+		}
+		return checkStatus(ctx, owner, name)
+	}
+	application := app.NewApp(&mockOutputWriter{}, mockConfigSvc, splitOwnerRepoParser())
+
+	// Act
+	err := application.RunBatch(context.Background(), interfaces.CLIOptions{Audit: true},
+		[]string{"acme/web", "acme/missing"})
+
+	// Assert
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) || appErr.Code != apperrors.ErrRepositoryNotFound {
+		t.Errorf("error = %v, expected repository-not-found batch error", err)
+	}
+}
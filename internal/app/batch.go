@@ -0,0 +1,330 @@
+// Package app provides batch processing for multiple repositories.
+//
+// Batch mode runs every repository through the same check, dry-run or normal
+// workflow as a single repository, continues past failures, and reports the
+// outcome of each repository in a result table.
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// Batch status values reported in the result table.
+const (
+	statusEnabled         = "enabled"
+	statusDisabled        = "disabled"
+	statusAlreadyEnabled  = "already enabled"
+	statusWouldEnable     = "would enable"
+	statusAlreadyDisabled = "already disabled"
+	statusWouldDisable    = "would disable"
+	statusFailed          = "failed"
+)
+
+// RepositoryResult records the outcome of processing one repository in batch mode.
+type RepositoryResult struct {
+	// Repository is the full repository name, or the identifier as given if it could not be resolved.
+	Repository string
+
+	// Status is a short description of the outcome (e.g., "enabled", "would enable", "failed").
+	Status string
+
+	// Err is the error that occurred while processing the repository, or nil on
+	// success. Failures are recorded as *errors.AppError values.
+	Err error
+}
+
+// failedResult records a repository that failed with err. The error is kept as
+// an AppError so failures can be summarized and aggregated by code.
+func failedResult(repository string, err error) RepositoryResult {
+	return RepositoryResult{Repository: repository, Status: statusFailed, Err: apperrors.AsAppError(err)}
+}
+
+// repositoryRef identifies a repository to process in a batch.
+// If the repository could not be resolved, err is set and owner/name are empty.
+type repositoryRef struct {
+	identifier string
+	owner      string
+	name       string
+	err        error
+}
+
+// RunBatch processes every repository identifier and writes a result table.
+// The mode is selected by opts exactly as in Run; opts.Repository is ignored.
+//
+// The token is validated before any repository is processed; an invalid token or
+// missing scope fails the whole batch. Other failures do not stop processing. Returns nil when every repository succeeded,
+// otherwise a batch AppError whose code aggregates the individual failures.
+func (a *App) RunBatch(ctx context.Context, opts interfaces.CLIOptions, identifiers []string) error {
+	refs := a.parseRefs(identifiers)
+
+	if _, err := a.validateToken(ctx, opts); err != nil {
+		return err
+	}
+
+	return a.runRepositories(ctx, opts, refs)
+}
+
+// parseRefs resolves repository identifiers; identifiers that cannot be parsed
+// keep their error for the result table.
+func (a *App) parseRefs(identifiers []string) []repositoryRef {
+	refs := make([]repositoryRef, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		owner, name, err := a.parser.Parse(identifier)
+		refs = append(refs, repositoryRef{identifier: identifier, owner: owner, name: name, err: err})
+	}
+	return refs
+}
+
+// runRepositories processes each resolved repository on up to opts.Concurrency
+// workers, writes the result table in input order and returns the aggregate
+// batch error, or the audit result in audit mode.
+func (a *App) runRepositories(ctx context.Context, opts interfaces.CLIOptions, refs []repositoryRef) error {
+	results := a.processRepositories(ctx, opts, refs)
+
+	a.writeResultTable(results)
+
+	if opts.Audit {
+		return a.auditError(results)
+	}
+	return batchError(results)
+}
+
+// processRepositories processes each resolved repository on up to
+// opts.Concurrency workers and returns and reports the results in input order.
+//
+// With a checkpoint, repositories that succeeded in the checkpointed run are
+// left out and every outcome is recorded as it completes. A failure to record
+// is reported once and does not stop the run.
+func (a *App) processRepositories(ctx context.Context, opts interfaces.CLIOptions, refs []repositoryRef) []RepositoryResult {
+	refs = a.skipCompleted(opts, refs)
+	a.prefetch(ctx, refs)
+
+	var recordFailure sync.Once
+	results := make([]RepositoryResult, len(refs))
+	a.runInOrder(ctx, opts.Concurrency, len(refs), func(worker *App, i int) {
+		results[i] = worker.processRepository(ctx, opts, refs[i])
+		if err := worker.recordCheckpoint(results[i]); err != nil {
+			recordFailure.Do(func() {
+				worker.writer.Error(fmt.Sprintf("Cannot record progress in the checkpoint: %v", err))
+			})
+		}
+	}, func(worker *App, i int) {
+		results[i] = worker.reportResult(opts, cancelledResult(refs[i].displayName()), nil)
+	})
+	return results
+}
+
+// prefetch reads the resolved repositories in bulk when the GitHub client
+// supports it. A failed bulk read is not fatal: each repository is then read on its own.
+func (a *App) prefetch(ctx context.Context, refs []repositoryRef) {
+	prefetcher, ok := a.client.(interfaces.IRepositoryPrefetcher)
+	if !ok {
+		return
+	}
+
+	fullNames := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if ref.err == nil {
+			fullNames = append(fullNames, ref.displayName())
+		}
+	}
+	if len(fullNames) < 2 {
+		return
+	}
+
+	a.writer.Verbose(fmt.Sprintf("Reading %d repositories in bulk", len(fullNames)))
+	if err := prefetcher.PrefetchRepositories(ctx, fullNames); err != nil {
+		a.writer.Verbose(fmt.Sprintf("Bulk read failed, reading repositories one at a time: %v", err))
+	}
+}
+
+// displayName returns the full repository name, or the identifier as given if
+// the repository could not be resolved.
+func (r repositoryRef) displayName() string {
+	if r.err != nil {
+		return r.identifier
+	}
+	return fmt.Sprintf("%s/%s", r.owner, r.name)
+}
+
+// processRepository runs a single repository through the mode selected in opts.
+// Errors are captured in the returned result rather than returned.
+func (a *App) processRepository(ctx context.Context, opts interfaces.CLIOptions, ref repositoryRef) RepositoryResult {
+	if ref.err != nil {
+		return a.reportResult(opts, failedResult(ref.identifier, ref.err), nil)
+	}
+
+	fullName := fmt.Sprintf("%s/%s", ref.owner, ref.name)
+	a.writer.Verbose(fmt.Sprintf("Processing %s", fullName))
+
+	result, err := a.performOperation(ctx, opts, ref.owner, ref.name)
+	if err != nil {
+		return a.reportResult(opts, failedResult(fullName, err), nil)
+	}
+
+	status, err := resultStatus(opts, result)
+	if err != nil {
+		return a.reportResult(opts, failedResult(fullName, err), result)
+	}
+
+	return a.reportResult(opts, RepositoryResult{Repository: fullName, Status: status}, result)
+}
+
+// performOperation performs the check, dry-run or normal operation selected in opts.
+func (a *App) performOperation(ctx context.Context, opts interfaces.CLIOptions, owner, name string) (interfaces.IConfigResult, error) {
+	// Check and audit mode take precedence over dry-run mode
+	if opts.CheckOnly || opts.Audit {
+		return a.configSvc.CheckStatus(ctx, owner, name)
+	}
+	if opts.Disable {
+		return a.configSvc.Disable(ctx, owner, name, opts.DryRun)
+	}
+	return a.configSvc.Configure(ctx, owner, name, opts.DryRun)
+}
+
+// resultStatus describes the outcome of the operation selected in opts.
+// Returns an error if the result does not match the requested state.
+func resultStatus(opts interfaces.CLIOptions, result interfaces.IConfigResult) (string, error) {
+	switch {
+	case opts.Audit && result.IsNowEnabled():
+		return statusCompliant, nil
+	case opts.Audit:
+		return statusNonCompliant, nil
+	case opts.CheckOnly && result.IsNowEnabled():
+		return statusEnabled, nil
+	case opts.CheckOnly:
+		return statusDisabled, nil
+	case opts.Disable:
+		return disableStatus(opts, result)
+	case result.WasAlreadyEnabled():
+		return statusAlreadyEnabled, nil
+	case opts.DryRun:
+		return statusWouldEnable, nil
+	case result.IsNowEnabled():
+		return statusEnabled, nil
+	}
+
+	return "", apperrors.NewVerificationError(result.GetRepositoryFullName(), "feature was not enabled")
+}
+
+// disableStatus describes the outcome of a dry-run or normal disable operation.
+func disableStatus(opts interfaces.CLIOptions, result interfaces.IConfigResult) (string, error) {
+	switch {
+	case !result.WasAlreadyEnabled():
+		return statusAlreadyDisabled, nil
+	case opts.DryRun:
+		return statusWouldDisable, nil
+	case !result.IsNowEnabled():
+		return statusDisabled, nil
+	}
+
+	return "", apperrors.NewVerificationError(result.GetRepositoryFullName(), "feature was not disabled")
+}
+
+// writeResultTable writes one row per repository followed by a summary line
+// and, if any repository failed, the failures by category.
+func (a *App) writeResultTable(results []RepositoryResult) {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REPOSITORY\tSTATUS")
+	for _, result := range results {
+		status := result.Status
+		if result.Err != nil {
+			status = fmt.Sprintf("%s: %v", status, result.Err)
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", result.Repository, status)
+	}
+	_ = tw.Flush()
+	a.writeLines(buf.String())
+
+	failed := countFailures(results)
+	a.writer.Info(fmt.Sprintf("%d repositories processed: %d succeeded, %d failed",
+		len(results), len(results)-failed, failed))
+
+	a.writeFailureSummary(results)
+}
+
+// writeFailureSummary writes the number of failures per error code, most
+// severe first. Nothing is written when every repository succeeded.
+func (a *App) writeFailureSummary(results []RepositoryResult) {
+	counts := make(map[apperrors.ErrorCode]int)
+	var codes []apperrors.ErrorCode
+	for _, result := range results {
+		if result.Err == nil {
+			continue
+		}
+		code := failureCode(result.Err)
+		if counts[code] == 0 {
+			codes = append(codes, code)
+		}
+		counts[code]++
+	}
+	if len(codes) == 0 {
+		return
+	}
+	sort.SliceStable(codes, func(i, j int) bool {
+		return codes[i].Severity() > codes[j].Severity()
+	})
+
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FAILURE\tEXIT CODE\tCOUNT")
+	for _, code := range codes {
+		fmt.Fprintf(tw, "%s\t%d\t%d\n", code, int(code), counts[code])
+	}
+	_ = tw.Flush()
+	a.writeLines(buf.String())
+}
+
+// writeLines writes each line of text as an info message.
+func (a *App) writeLines(text string) {
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		a.writer.Info(line)
+	}
+}
+
+// failureCode returns the error code of a repository failure.
+func failureCode(err error) apperrors.ErrorCode {
+	return apperrors.AsAppError(err).Code
+}
+
+// batchError aggregates per-repository failures into a single error.
+//
+// The error carries the most severe code among the failures (see
+// ErrorCode.Severity), so the exit code reflects the worst category
+// encountered: e.g., 4 when some repositories were forbidden and others not found.
+func batchError(results []RepositoryResult) error {
+	failed := countFailures(results)
+	if failed == 0 {
+		return nil
+	}
+
+	var code apperrors.ErrorCode
+	for _, result := range results {
+		if result.Err != nil {
+			code = apperrors.MostSevere(code, failureCode(result.Err))
+		}
+	}
+
+	return apperrors.NewBatchError(failed, len(results), code)
+}
+
+// countFailures returns the number of results that carry an error.
+func countFailures(results []RepositoryResult) int {
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
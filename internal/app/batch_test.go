@@ -0,0 +1,354 @@
+// Package app_test provides tests for batch mode in the App.
+//
+// These tests verify that App.RunBatch processes every repository identifier,
+// continues past failures, writes a per-repository result table and returns an
+// aggregate error with a meaningful exit code.
+package app_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/josejulio/ghautodelete/internal/app"
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+// splitOwnerRepoParser returns a parser that splits "owner/name" identifiers
+// and rejects anything else with a validation error.
+func splitOwnerRepoParser() *mockRepoParser {
+	return &mockRepoParser{
+		ParseFunc: func(repoIdentifier string) (string, string, error) {
+			parts := strings.Split(repoIdentifier, "/")
+			if len(parts) != 2 {
+				return "", "", apperrors.NewValidationError("Expected format: owner/repo")
+			}
+			return parts[0], parts[1], nil
+		},
+	}
+}
+
+// =============================================================================
+// Batch Mode Tests
+// =============================================================================
+
+// TestRunBatchProcessesEveryRepository verifies each repository is configured once.
+func TestRunBatchProcessesEveryRepository(t *testing.T) {
+	// Arrange
+	mockWriter := &mockOutputWriter{}
+	mockConfigSvc := &mockConfigService{
+		ConfigureFunc: func(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+			if name == "api" {
+				return newMockConfigResult(true, true, "main", owner+"/"+name), nil
+			}
+			return newMockConfigResult(false, true, "main", owner+"/"+name), nil
+		},
+	}
+	application := app.NewApp(mockWriter, mockConfigSvc, splitOwnerRepoParser())
+	identifiers := []string{"acme/api", "acme/web", "acme/worker"}
+
+	// Act
+	err := application.RunBatch(context.Background(), interfaces.CLIOptions{}, identifiers)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("RunBatch() error = %v, expected nil", err)
+	}
+	if len(mockConfigSvc.ConfigureCalls) != 3 {
+		t.Fatalf("expected 3 Configure calls, got %d", len(mockConfigSvc.ConfigureCalls))
+	}
+	for i, want := range []string{"api", "web", "worker"} {
+		if mockConfigSvc.ConfigureCalls[i].Name != want {
+			t.Errorf("Configure call %d name = %q, expected %q", i, mockConfigSvc.ConfigureCalls[i].Name, want)
+		}
+	}
+
+	output := mockWriter.GetAllOutput()
+	for _, want := range []string{
+		"REPOSITORY",
+		"acme/api     already enabled",
+		"acme/web     enabled",
+		"acme/worker  enabled",
+		"3 repositories processed: 3 succeeded, 0 failed",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+// TestRunBatchContinuesPastFailures verifies a failing repository does not stop the run.
+func TestRunBatchContinuesPastFailures(t *testing.T) {
+	// Arrange
+	mockWriter := &mockOutputWriter{}
+	mockConfigSvc := &mockConfigService{
+		ConfigureFunc: func(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+			if name == "missing" {
+				return nil, apperrors.NewRepositoryNotFoundError(owner, name)
+			}
+			return newMockConfigResult(false, true, "main", owner+"/"+name), nil
+		},
+	}
+	application := app.NewApp(mockWriter, mockConfigSvc, splitOwnerRepoParser())
+	identifiers := []string{"acme/missing", "acme/web"}
+
+	// Act
+	err := application.RunBatch(context.Background(), interfaces.CLIOptions{}, identifiers)
+
+	// Assert
+	if len(mockConfigSvc.ConfigureCalls) != 2 {
+		t.Errorf("expected 2 Configure calls, got %d", len(mockConfigSvc.ConfigureCalls))
+	}
+	if code := apperrors.GetExitCode(err); code != 5 {
+		t.Errorf("exit code = %d, expected 5 (err: %v)", code, err)
+	}
+	output := mockWriter.GetAllOutput()
+	if !strings.Contains(output, "acme/missing  failed: Repository not found: acme/missing") {
+		t.Errorf("output should report the failed repository, got:\n%s", output)
+	}
+	if !strings.Contains(output, "2 repositories processed: 1 succeeded, 1 failed") {
+		t.Errorf("output should contain summary, got:\n%s", output)
+	}
+}
+
+// TestRunBatchAggregateExitCode verifies how failure codes combine into the exit code.
+func TestRunBatchAggregateExitCode(t *testing.T) {
+	tests := []struct {
+		name         string
+		repositories []string
+		failures     map[string]error
+		expectedCode int
+	}{
+		{
+			name:         "all succeed",
+			repositories: []string{"acme/a", "acme/b"},
+			failures:     map[string]error{},
+			expectedCode: 0,
+		},
+		{
+			name:         "same failure code is preserved",
+			repositories: []string{"acme/a", "acme/b", "acme/c"},
+			failures: map[string]error{
+				"a": apperrors.NewAuthorizationError("insufficient permissions"),
+				"c": apperrors.NewAuthorizationError("insufficient permissions"),
+			},
+			expectedCode: 4,
+		},
+		{
+			name:         "mixed failure codes map to the most severe",
+			repositories: []string{"acme/a", "acme/b"},
+			failures: map[string]error{
+				"a": apperrors.NewRepositoryNotFoundError("acme", "a"),
+				"b": apperrors.NewAuthorizationError("insufficient permissions"),
+			},
+			expectedCode: 4,
+		},
+		{
+			name:         "rate limit outranks general and not found errors",
+			repositories: []string{"acme/a", "acme/b", "acme/c"},
+			failures: map[string]error{
+				"a": errors.New("connection reset"),
+				"b": apperrors.NewRateLimitError(time.Now()),
+				"c": apperrors.NewRepositoryNotFoundError("acme", "c"),
+			},
+			expectedCode: 6,
+		},
+		{
+			name:         "uncategorized errors map to general error",
+			repositories: []string{"acme/a", "acme/b"},
+			failures: map[string]error{
+				"a": errors.New("connection reset"),
+				"b": apperrors.NewRepositoryNotFoundError("acme", "b"),
+			},
+			expectedCode: 1,
+		},
+		{
+			name:         "invalid identifier is reported with code 2",
+			repositories: []string{"not-a-repo"},
+			failures:     map[string]error{},
+			expectedCode: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockConfigSvc := &mockConfigService{
+				ConfigureFunc: func(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+					if err, ok := tt.failures[name]; ok {
+						return nil, err
+					}
+					return newMockConfigResult(false, true, "main", owner+"/"+name), nil
+				},
+			}
+			application := app.NewApp(&mockOutputWriter{}, mockConfigSvc, splitOwnerRepoParser())
+
+			// Act
+			err := application.RunBatch(context.Background(), interfaces.CLIOptions{}, tt.repositories)
+
+			// Assert
+			if code := apperrors.GetExitCode(err); code != tt.expectedCode {
+				t.Errorf("exit code = %d, expected %d (err: %v)", code, tt.expectedCode, err)
+			}
+		})
+	}
+}
+
+// TestRunBatchSummarizesFailuresByCategory verifies failures are counted per
+// error code, most severe first, and uncategorized errors are reported as AppErrors.
+func TestRunBatchSummarizesFailuresByCategory(t *testing.T) {
+	// Arrange
+	mockWriter := &mockOutputWriter{}
+	mockConfigSvc := &mockConfigService{
+		ConfigureFunc: func(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+			switch name {
+			case "gone", "moved":
+				return nil, apperrors.NewRepositoryNotFoundError(owner, name)
+			case "locked":
+				return nil, apperrors.NewAuthorizationError("insufficient permissions")
+			case "flaky":
+				return nil, errors.New("connection reset")
+			case "stuck":
+				return newMockConfigResult(false, false, "main", owner+"/"+name), nil
+			}
+			return newMockConfigResult(false, true, "main", owner+"/"+name), nil
+		},
+	}
+	application := app.NewApp(mockWriter, mockConfigSvc, splitOwnerRepoParser())
+	identifiers := []string{"acme/gone", "acme/api", "acme/flaky", "acme/locked", "acme/moved", "acme/stuck"}
+
+	// Act
+	err := application.RunBatch(context.Background(), interfaces.CLIOptions{Concurrency: 3}, identifiers)
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 4 {
+		t.Errorf("exit code = %d, expected 4 for the most severe failure (err: %v)", code, err)
+	}
+	output := mockWriter.GetAllOutput()
+	if !strings.Contains(output, "acme/stuck   failed: Verification failed for acme/stuck: feature was not enabled") {
+		t.Errorf("output should report the verification mismatch, got:\n%s", output)
+	}
+	start := strings.Index(output, "FAILURE")
+	if start < 0 {
+		t.Fatalf("output should contain the failure summary, got:\n%s", output)
+	}
+	summary := output[start:]
+	expected := []string{
+		"insufficient permissions  4          1",
+		"general error             1          2",
+		"repository not found      5          2",
+	}
+	last := 0
+	for _, line := range expected {
+		i := strings.Index(summary, line)
+		if i < last {
+			t.Fatalf("summary should list %q in severity order, got:\n%s", line, summary)
+		}
+		last = i
+	}
+}
+
+// TestRunBatchHonorsCheckAndDryRunModes verifies the mode flags apply to each repository.
+func TestRunBatchHonorsCheckAndDryRunModes(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           interfaces.CLIOptions
+		expectedStatus string
+		expectCheck    bool
+	}{
+		{
+			name:           "check mode uses CheckStatus",
+			opts:           interfaces.CLIOptions{CheckOnly: true, DryRun: true},
+			expectedStatus: "acme/api    disabled",
+			expectCheck:    true,
+		},
+		{
+			name:           "dry-run mode configures with dryRun=true",
+			opts:           interfaces.CLIOptions{DryRun: true},
+			expectedStatus: "acme/api    would enable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockWriter := &mockOutputWriter{}
+			mockConfigSvc := &mockConfigService{
+				CheckStatusFunc: func(ctx context.Context, owner, name string) (interfaces.IConfigResult, error) {
+					return newMockConfigResult(false, false, "main", owner+"/"+name), nil
+				},
+				ConfigureFunc: func(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+					return newMockConfigResult(false, false, "main", owner+"/"+name), nil
+				},
+			}
+			application := app.NewApp(mockWriter, mockConfigSvc, splitOwnerRepoParser())
+
+			// Act
+			err := application.RunBatch(context.Background(), tt.opts, []string{"acme/api"})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("RunBatch() error = %v, expected nil", err)
+			}
+			if tt.expectCheck && len(mockConfigSvc.ConfigureCalls) != 0 {
+				t.Errorf("check mode should not call Configure, got %d calls", len(mockConfigSvc.ConfigureCalls))
+			}
+			if !tt.expectCheck && (len(mockConfigSvc.ConfigureCalls) != 1 || !mockConfigSvc.ConfigureCalls[0].DryRun) {
+				t.Errorf("dry-run mode should call Configure once with dryRun=true, got %+v", mockConfigSvc.ConfigureCalls)
+			}
+			if !strings.Contains(mockWriter.GetAllOutput(), tt.expectedStatus) {
+				t.Errorf("output should contain %q, got:\n%s", tt.expectedStatus, mockWriter.GetAllOutput())
+			}
+		})
+	}
+}
+
+// prefetchingClient is a mock GitHub client that records bulk reads.
+type prefetchingClient struct {
+	mockGitHubClient
+	prefetched []string
+	err        error
+}
+
+func (c *prefetchingClient) PrefetchRepositories(ctx context.Context, fullNames []string) error {
+	c.prefetched = append(c.prefetched, fullNames...)
+	return c.err
+}
+
+// TestRunBatchPrefetchesRepositories verifies a client that reads in bulk is
+// given every resolved repository before processing, and that a failed bulk
+// read does not stop the batch.
+func TestRunBatchPrefetchesRepositories(t *testing.T) {
+	for _, prefetchErr := range []error{nil, apperrors.NewAPIError("GraphQL query failed", nil)} {
+		// Arrange
+		mockWriter := &mockOutputWriter{}
+		mockConfigSvc := &mockConfigService{
+			ConfigureFunc: func(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+				return newMockConfigResult(true, true, "main", owner+"/"+name), nil
+			},
+		}
+		client := &prefetchingClient{err: prefetchErr}
+		application := app.NewApp(mockWriter, mockConfigSvc, splitOwnerRepoParser(), app.WithGitHubClient(client))
+
+		// Act
+		err := application.RunBatch(context.Background(), interfaces.CLIOptions{}, []string{"acme/api", "invalid", "acme/web"})
+
+		// Assert
+		if strings.Join(client.prefetched, ",") != "acme/api,acme/web" {
+			t.Errorf("prefetched = %v, expected the resolved repositories", client.prefetched)
+		}
+		if len(mockConfigSvc.ConfigureCalls) != 2 {
+			t.Errorf("expected 2 Configure calls, got %d", len(mockConfigSvc.ConfigureCalls))
+		}
+		if code := apperrors.GetExitCode(err); code != 2 {
+			t.Errorf("exit code = %d, expected 2 for the invalid identifier (err: %v)", code, err)
+		}
+	}
+}
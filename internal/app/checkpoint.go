@@ -0,0 +1,171 @@
+// Package app provides resumable multi-repository runs.
+//
+// When a checkpoint is supplied via WithCheckpoint, the outcome of every
+// repository is recorded as soon as it completes. When an enable or disable run
+// is resumed, the repositories whose setting was already applied are skipped,
+// while failed and unprocessed repositories run again. Checks, dry runs and
+// audits only report on repositories, so a resumed one reports on every
+// repository again to keep its totals complete.
+package app
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// WithCheckpoint sets the checkpoint that records the outcome of every
+// repository of a multi-repository run and lists those that can be skipped.
+func WithCheckpoint(checkpoint interfaces.ICheckpoint) Option {
+	return func(a *App) {
+		a.checkpoint = checkpoint
+	}
+}
+
+// CheckpointOperation describes the mode selected by opts, e.g. "enable (dry-run)".
+// A checkpoint recorded by one operation must not be resumed by another: a
+// repository that "would enable" in a dry run has not been enabled.
+func CheckpointOperation(opts interfaces.CLIOptions) string {
+	switch {
+	case opts.Audit:
+		return modeAudit
+	case opts.CheckOnly:
+		return modeCheck
+	}
+
+	operation := operationEnable
+	if opts.Disable {
+		operation = operationDisable
+	}
+	return fmt.Sprintf("%s (%s)", operation, modeName(false, opts.DryRun))
+}
+
+// CheckpointSelection describes the repositories selected by opts, or by the
+// identifiers when no organization, user, search or enterprise is selected,
+// e.g. "organization acme". A checkpoint recorded for one selection must not be
+// resumed for another. Listed repositories are described together with the
+// filters applied to the listing, e.g. "organization acme topic=go
+// visibility=private". A list of repositories is described by its size and a
+// digest of the names, in any order.
+func CheckpointSelection(opts interfaces.CLIOptions, identifiers []string) string {
+	switch {
+	case opts.Organization != "":
+		return "organization " + strings.ToLower(opts.Organization) + listingFilters(opts)
+	case opts.User:
+		return "user" + listingFilters(opts)
+	case opts.Search != "":
+		return "search " + opts.Search + listingFilters(opts)
+	case opts.Enterprise != "":
+		return "enterprise " + strings.ToLower(opts.Enterprise) + listingFilters(opts)
+	}
+
+	names := make([]string, len(identifiers))
+	for i, identifier := range identifiers {
+		names[i] = strings.ToLower(strings.TrimSpace(identifier))
+	}
+	sort.Strings(names)
+	digest := sha256.Sum256([]byte(strings.Join(names, "\n")))
+	return fmt.Sprintf("%d repositories %x", len(names), digest[:8])
+}
+
+// listingFilters describes the filters opts applies to listed repositories,
+// each preceded by a space, or "" when there are none. Lists of values are
+// described in any order.
+func listingFilters(opts interfaces.CLIOptions) string {
+	var filters []string
+	if opts.User && opts.Affiliation != "" {
+		filters = append(filters, "affiliation="+sortedValues(strings.Split(strings.ToLower(opts.Affiliation), ",")))
+	}
+	if !opts.SkipArchived {
+		filters = append(filters, "archived")
+	}
+	if opts.SkipForks {
+		filters = append(filters, "skip-forks")
+	}
+	if opts.SkipTemplates {
+		filters = append(filters, "skip-templates")
+	}
+	if len(opts.Topics) > 0 {
+		filters = append(filters, "topic="+sortedValues(strings.Split(strings.ToLower(strings.Join(opts.Topics, ",")), ",")))
+	}
+	if opts.Visibility != "" {
+		filters = append(filters, "visibility="+strings.ToLower(opts.Visibility))
+	}
+	if opts.Language != "" {
+		filters = append(filters, "language="+strings.ToLower(opts.Language))
+	}
+	if len(opts.Include) > 0 {
+		filters = append(filters, "include="+sortedValues(opts.Include))
+	}
+	if len(opts.Exclude) > 0 {
+		filters = append(filters, "exclude="+sortedValues(opts.Exclude))
+	}
+	if opts.PushedSince != "" {
+		filters = append(filters, "pushed-since="+opts.PushedSince)
+	}
+
+	if len(filters) == 0 {
+		return ""
+	}
+	return " " + strings.Join(filters, " ")
+}
+
+// sortedValues joins a sorted copy of values with commas.
+func sortedValues(values []string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// ModifiesRepositories reports whether opts selects an enable or disable run
+// that changes repositories, as opposed to a check, a dry run or an audit.
+func ModifiesRepositories(opts interfaces.CLIOptions) bool {
+	return !opts.Audit && !opts.CheckOnly && !opts.DryRun
+}
+
+// appliedStatuses are the checkpointed statuses of repositories whose setting
+// is already in place; a resumed run can skip them.
+var appliedStatuses = map[string]bool{
+	statusEnabled:         true,
+	statusAlreadyEnabled:  true,
+	statusDisabled:        true,
+	statusAlreadyDisabled: true,
+}
+
+// skipCompleted drops the repositories that the checkpointed run applied the
+// setting to. Only enable and disable runs that modify repositories skip any.
+func (a *App) skipCompleted(opts interfaces.CLIOptions, refs []repositoryRef) []repositoryRef {
+	if a.checkpoint == nil || !ModifiesRepositories(opts) {
+		return refs
+	}
+
+	remaining := make([]repositoryRef, 0, len(refs))
+	for _, ref := range refs {
+		if ref.err == nil && appliedStatuses[a.checkpoint.Status(ref.displayName())] {
+			a.writer.Verbose(fmt.Sprintf("Skipping %s: succeeded in the checkpointed run", ref.displayName()))
+			continue
+		}
+		remaining = append(remaining, ref)
+	}
+
+	if skipped := len(refs) - len(remaining); skipped > 0 {
+		a.writer.Info(fmt.Sprintf("Skipping %d repositories that succeeded in the checkpointed run", skipped))
+	}
+	return remaining
+}
+
+// recordCheckpoint records the outcome of one repository in the checkpoint, if one is set.
+func (a *App) recordCheckpoint(result RepositoryResult) error {
+	if a.checkpoint == nil {
+		return nil
+	}
+
+	outcome := interfaces.CheckpointRecord{Repository: result.Repository, Status: result.Status}
+	if result.Err != nil {
+		outcome.Error = result.Err.Error()
+	}
+	return a.checkpoint.Record(outcome)
+}
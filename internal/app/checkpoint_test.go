@@ -0,0 +1,198 @@
+// Package app_test provides tests for resumable runs in the App.
+//
+// These tests verify that a batch run with a checkpoint skips the repositories
+// that succeeded in the checkpointed run and records the outcome of every
+// repository it processes, that a resumed audit checks every repository again,
+// and that the checkpoint operation and selection tell runs apart.
+package app_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/josejulio/ghautodelete/internal/app"
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// mockCheckpoint is a mock implementation of ICheckpoint.
+type mockCheckpoint struct {
+	statuses map[string]string
+
+	mu      sync.Mutex
+	records []interfaces.CheckpointRecord
+}
+
+// Status returns the status set for the repository.
+func (m *mockCheckpoint) Status(repository string) string {
+	return m.statuses[repository]
+}
+
+// Record stores the outcome.
+func (m *mockCheckpoint) Record(outcome interfaces.CheckpointRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = append(m.records, outcome)
+	return nil
+}
+
+// TestRunBatchResumesFromCheckpoint verifies succeeded repositories are skipped
+// and every processed repository is recorded, failures included.
+func TestRunBatchResumesFromCheckpoint(t *testing.T) {
+	// Arrange
+	mockWriter := &mockOutputWriter{}
+	mockConfigSvc := &mockConfigService{
+		ConfigureFunc: func(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+			if name == "docs" {
+				return nil, errors.New("connection reset")
+			}
+			return newMockConfigResult(false, true, "main", owner+"/"+name), nil
+		},
+	}
+	progress := &mockCheckpoint{statuses: map[string]string{"acme/api": "enabled"}}
+	application := app.NewApp(mockWriter, mockConfigSvc, splitOwnerRepoParser(),
+		app.WithGitHubClient(&mockGitHubClient{}), app.WithCheckpoint(progress))
+
+	// Act
+	err := application.RunBatch(context.Background(), interfaces.CLIOptions{}, []string{"acme/api", "acme/web", "acme/docs"})
+
+	// Assert
+	if err == nil {
+		t.Fatal("RunBatch() error = nil, expected the batch error for acme/docs")
+	}
+	if len(mockConfigSvc.ConfigureCalls) != 2 {
+		t.Errorf("configured %d repositories, expected 2", len(mockConfigSvc.ConfigureCalls))
+	}
+	var recorded []string
+	for _, record := range progress.records {
+		recorded = append(recorded, record.Repository+"="+record.Status+":"+record.Error)
+	}
+	if got := strings.Join(recorded, ","); got != "acme/web=enabled:,acme/docs=failed:connection reset" {
+		t.Errorf("recorded %s, expected acme/web and the acme/docs failure", got)
+	}
+	if output := mockWriter.GetAllOutput(); !strings.Contains(output, "Skipping 1 repositories that succeeded in the checkpointed run") {
+		t.Errorf("output should report the skipped repository, got:\n%s", output)
+	}
+}
+
+// TestRunBatchResumedAuditChecksEveryRepository verifies a resumed audit does
+// not skip repositories, so drift found by the checkpointed run is reported again.
+func TestRunBatchResumedAuditChecksEveryRepository(t *testing.T) {
+	// Arrange
+	mockWriter := &mockOutputWriter{}
+	mockConfigSvc := newAuditConfigService("web")
+	progress := &mockCheckpoint{statuses: map[string]string{"acme/api": "compliant", "acme/web": "non-compliant"}}
+	application := app.NewApp(mockWriter, mockConfigSvc, splitOwnerRepoParser(), app.WithCheckpoint(progress))
+	opts := interfaces.CLIOptions{Audit: true}
+
+	// Act
+	err := application.RunBatch(context.Background(), opts, []string{"acme/api", "acme/web", "acme/docs"})
+
+	// Assert
+	if apperrors.GetExitCode(err) != int(apperrors.ErrDriftDetected) {
+		t.Fatalf("exit code = %d, expected %d (err: %v)", apperrors.GetExitCode(err), apperrors.ErrDriftDetected, err)
+	}
+	if err.Error() != "1 of 3 repositories are non-compliant" {
+		t.Errorf("error = %q, expected the drift of acme/web out of every repository", err.Error())
+	}
+	if output := mockWriter.GetAllOutput(); strings.Contains(output, "Skipping") {
+		t.Errorf("a resumed audit should not skip repositories, got:\n%s", output)
+	}
+	if len(progress.records) != 3 {
+		t.Errorf("recorded %d outcomes, expected 3", len(progress.records))
+	}
+}
+
+// TestCheckpointOperation verifies each mode has its own operation.
+func TestCheckpointOperation(t *testing.T) {
+	tests := []struct {
+		opts     interfaces.CLIOptions
+		expected string
+	}{
+		{opts: interfaces.CLIOptions{}, expected: "enable (normal)"},
+		{opts: interfaces.CLIOptions{DryRun: true}, expected: "enable (dry-run)"},
+		{opts: interfaces.CLIOptions{Disable: true}, expected: "disable (normal)"},
+		{opts: interfaces.CLIOptions{CheckOnly: true, DryRun: true}, expected: "check"},
+		{opts: interfaces.CLIOptions{Audit: true}, expected: "audit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			// Act
+			operation := app.CheckpointOperation(tt.opts)
+
+			// Assert
+			if operation != tt.expected {
+				t.Errorf("CheckpointOperation() = %q, expected %q", operation, tt.expected)
+			}
+		})
+	}
+}
+
+// TestCheckpointSelection verifies each selection has its own description and
+// a repository list is identified by its names in any order.
+func TestCheckpointSelection(t *testing.T) {
+	// Act
+	org := app.CheckpointSelection(interfaces.CLIOptions{Organization: "Acme", SkipArchived: true}, nil)
+	search := app.CheckpointSelection(interfaces.CLIOptions{Search: "topic:go", SkipArchived: true}, nil)
+	list := app.CheckpointSelection(interfaces.CLIOptions{}, []string{"acme/api", "acme/web"})
+	reordered := app.CheckpointSelection(interfaces.CLIOptions{}, []string{"ACME/web", "acme/api"})
+	other := app.CheckpointSelection(interfaces.CLIOptions{}, []string{"acme/api", "acme/docs"})
+
+	// Assert
+	if org != "organization acme" || search != "search topic:go" {
+		t.Errorf("selections = %q, %q, expected the organization and the query", org, search)
+	}
+	if !strings.HasPrefix(list, "2 repositories ") || list != reordered {
+		t.Errorf("selection = %q, expected the same description as %q", list, reordered)
+	}
+	if list == other {
+		t.Errorf("lists of different repositories share the selection %q", list)
+	}
+}
+
+// TestCheckpointSelectionIncludesListingFilters verifies listings with other
+// filters have other selections, and the order of listed filter values does
+// not matter.
+func TestCheckpointSelectionIncludesListingFilters(t *testing.T) {
+	base := interfaces.CLIOptions{Organization: "acme", SkipArchived: true}
+	withTopics := func(topics ...string) interfaces.CLIOptions {
+		opts := base
+		opts.Topics = topics
+		return opts
+	}
+	tests := []struct {
+		name string
+		opts interfaces.CLIOptions
+	}{
+		{name: "topic", opts: withTopics("go")},
+		{name: "visibility", opts: interfaces.CLIOptions{Organization: "acme", SkipArchived: true, Visibility: "private"}},
+		{name: "language", opts: interfaces.CLIOptions{Organization: "acme", SkipArchived: true, Language: "Go"}},
+		{name: "include", opts: interfaces.CLIOptions{Organization: "acme", SkipArchived: true, Include: []string{"api-*"}}},
+		{name: "exclude", opts: interfaces.CLIOptions{Organization: "acme", SkipArchived: true, Exclude: []string{"legacy-*"}}},
+		{name: "pushed since", opts: interfaces.CLIOptions{Organization: "acme", SkipArchived: true, PushedSince: "2024-01-01"}},
+		{name: "archived", opts: interfaces.CLIOptions{Organization: "acme"}},
+		{name: "skip forks", opts: interfaces.CLIOptions{Organization: "acme", SkipArchived: true, SkipForks: true}},
+		{name: "skip templates", opts: interfaces.CLIOptions{Organization: "acme", SkipArchived: true, SkipTemplates: true}},
+	}
+
+	unfiltered := app.CheckpointSelection(base, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			selection := app.CheckpointSelection(tt.opts, nil)
+
+			// Assert
+			if selection == unfiltered {
+				t.Errorf("selection = %q, expected the filter to be described", selection)
+			}
+		})
+	}
+
+	if a, b := app.CheckpointSelection(withTopics("go", "API"), nil), app.CheckpointSelection(withTopics("api", "go"), nil); a != b {
+		t.Errorf("selections = %q, %q, expected the same topics in any order to match", a, b)
+	}
+}
@@ -0,0 +1,59 @@
+// Package app provides the disable workflow.
+//
+// Disable mode turns delete-branch-on-merge back off. It supports the same dry-run
+// and check modes as enabling, and reports a repository that is already disabled
+// as a no-op rather than an error.
+package app
+
+import (
+	"context"
+	"fmt"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+)
+
+// handleDisableDryRunMode handles dry-run mode when disabling.
+// It shows what would happen without making any actual changes.
+func (a *App) handleDisableDryRunMode(ctx context.Context, owner, name string) error {
+	result, err := a.configSvc.Disable(ctx, owner, name, true)
+	if err != nil {
+		return fmt.Errorf("dry-run disable failed: %w", err)
+	}
+
+	// If feature was already disabled
+	if !result.WasAlreadyEnabled() {
+		a.writer.Info(fmt.Sprintf("Auto-delete branches already disabled for %s", result.GetRepositoryFullName()))
+		a.writer.Info("No changes needed")
+		return nil
+	}
+
+	// If feature would be disabled
+	a.writer.Info(fmt.Sprintf("[DRY-RUN] Would disable auto-delete branches for %s", result.GetRepositoryFullName()))
+	a.writer.Info("No changes made")
+
+	return nil
+}
+
+// handleDisableMode handles normal mode when disabling.
+// It actually turns auto-delete branches off on the repository.
+func (a *App) handleDisableMode(ctx context.Context, owner, name string) error {
+	result, err := a.configSvc.Disable(ctx, owner, name, false)
+	if err != nil {
+		return fmt.Errorf("disable failed: %w", err)
+	}
+
+	// If feature was already disabled
+	if !result.WasAlreadyEnabled() {
+		a.writer.Success(fmt.Sprintf("Auto-delete branches already disabled for %s", result.GetRepositoryFullName()))
+		return nil
+	}
+
+	// If feature was successfully disabled
+	if !result.IsNowEnabled() {
+		a.writer.Success(fmt.Sprintf("Successfully disabled auto-delete branches for %s", result.GetRepositoryFullName()))
+		return nil
+	}
+
+	// Verification showed the setting is still on
+	return apperrors.NewVerificationError(result.GetRepositoryFullName(), "feature was not disabled")
+}
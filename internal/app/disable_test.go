@@ -0,0 +1,177 @@
+// Package app_test provides tests for disable mode in the App.
+//
+// These tests verify that App.Run and App.RunBatch route to IConfigService.Disable
+// when opts.Disable is set, and report already-disabled, dry-run and disabled
+// outcomes.
+package app_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/josejulio/ghautodelete/internal/app"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// =============================================================================
+// Single Repository Disable Tests
+// =============================================================================
+
+// TestRunDisableModes verifies the messages for each disable outcome.
+func TestRunDisableModes(t *testing.T) {
+	tests := []struct {
+		name            string
+		dryRun          bool
+		wasEnabled      bool
+		nowEnabled      bool
+		expectedMessage string
+		expectError     bool
+	}{
+		{
+			name:            "disables an enabled repository",
+			wasEnabled:      true,
+			nowEnabled:      false,
+			expectedMessage: "Successfully disabled auto-delete branches for octocat/hello-world",
+		},
+		{
+			name:            "already disabled",
+			wasEnabled:      false,
+			nowEnabled:      false,
+			expectedMessage: "Auto-delete branches already disabled for octocat/hello-world",
+		},
+		{
+			name:            "dry run",
+			dryRun:          true,
+			wasEnabled:      true,
+			nowEnabled:      true,
+			expectedMessage: "[DRY-RUN] Would disable auto-delete branches for octocat/hello-world",
+		},
+		{
+			name:        "verification still enabled",
+			wasEnabled:  true,
+			nowEnabled:  true,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockWriter := &mockOutputWriter{}
+			mockConfigSvc := &mockConfigService{
+				DisableFunc: func(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+					return newMockConfigResult(tt.wasEnabled, tt.nowEnabled, "main", owner+"/"+name), nil
+				},
+			}
+			application := app.NewApp(mockWriter, mockConfigSvc, splitOwnerRepoParser())
+			opts := interfaces.CLIOptions{Repository: "octocat/hello-world", Disable: true, DryRun: tt.dryRun}
+
+			// Act
+			err := application.Run(context.Background(), opts)
+
+			// Assert
+			if len(mockConfigSvc.ConfigureCalls) != 0 {
+				t.Errorf("disable mode should not call Configure, got %d calls", len(mockConfigSvc.ConfigureCalls))
+			}
+			if len(mockConfigSvc.DisableCalls) != 1 || mockConfigSvc.DisableCalls[0].DryRun != tt.dryRun {
+				t.Errorf("expected one Disable call with dryRun=%v, got %+v", tt.dryRun, mockConfigSvc.DisableCalls)
+			}
+			if tt.expectError {
+				if err == nil {
+					t.Error("Run() error = nil, expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run() error = %v, expected nil", err)
+			}
+			if !strings.Contains(mockWriter.GetAllOutput(), tt.expectedMessage) {
+				t.Errorf("output should contain %q, got:\n%s", tt.expectedMessage, mockWriter.GetAllOutput())
+			}
+		})
+	}
+}
+
+// TestRunDisableCheckModeUsesCheckStatus verifies --check takes precedence over disable.
+func TestRunDisableCheckModeUsesCheckStatus(t *testing.T) {
+	// Arrange
+	mockConfigSvc := &mockConfigService{
+		CheckStatusFunc: func(ctx context.Context, owner, name string) (interfaces.IConfigResult, error) {
+			return newMockConfigResult(true, true, "main", owner+"/"+name), nil
+		},
+	}
+	application := app.NewApp(&mockOutputWriter{}, mockConfigSvc, splitOwnerRepoParser())
+
+	// Act
+	err := application.Run(context.Background(), interfaces.CLIOptions{Repository: "octocat/hello-world", Disable: true, CheckOnly: true})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Run() error = %v, expected nil", err)
+	}
+	if len(mockConfigSvc.CheckStatusCalls) != 1 || len(mockConfigSvc.DisableCalls) != 0 {
+		t.Errorf("check mode should only call CheckStatus, got %d CheckStatus and %d Disable calls",
+			len(mockConfigSvc.CheckStatusCalls), len(mockConfigSvc.DisableCalls))
+	}
+}
+
+// =============================================================================
+// Batch Disable Tests
+// =============================================================================
+
+// TestRunBatchDisableStatuses verifies the result table statuses in disable mode.
+func TestRunBatchDisableStatuses(t *testing.T) {
+	// Arrange
+	mockWriter := &mockOutputWriter{}
+	mockConfigSvc := &mockConfigService{
+		DisableFunc: func(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+			if name == "api" {
+				return newMockConfigResult(false, false, "main", owner+"/"+name), nil
+			}
+			return newMockConfigResult(true, dryRun, "main", owner+"/"+name), nil
+		},
+	}
+	application := app.NewApp(mockWriter, mockConfigSvc, splitOwnerRepoParser())
+
+	// Act
+	err := application.RunBatch(context.Background(), interfaces.CLIOptions{Disable: true}, []string{"acme/api", "acme/web"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("RunBatch() error = %v, expected nil", err)
+	}
+	output := mockWriter.GetAllOutput()
+	for _, want := range []string{
+		"acme/api    already disabled",
+		"acme/web    disabled",
+		"2 repositories processed: 2 succeeded, 0 failed",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+// TestRunBatchDisableDryRun verifies dry-run disable reports "would disable".
+func TestRunBatchDisableDryRun(t *testing.T) {
+	// Arrange
+	mockWriter := &mockOutputWriter{}
+	mockConfigSvc := &mockConfigService{
+		DisableFunc: func(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+			return newMockConfigResult(true, true, "main", owner+"/"+name), nil
+		},
+	}
+	application := app.NewApp(mockWriter, mockConfigSvc, splitOwnerRepoParser())
+
+	// Act
+	err := application.RunBatch(context.Background(), interfaces.CLIOptions{Disable: true, DryRun: true}, []string{"acme/api"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("RunBatch() error = %v, expected nil", err)
+	}
+	if !strings.Contains(mockWriter.GetAllOutput(), "acme/api    would disable") {
+		t.Errorf("output should report would disable, got:\n%s", mockWriter.GetAllOutput())
+	}
+}
@@ -0,0 +1,157 @@
+// Package app provides enterprise-wide processing.
+//
+// Enterprise mode lists the member organizations of a GitHub enterprise and
+// processes each organization in turn, exactly as organization mode does. The
+// result table is followed by a subtotal per organization. An organization
+// whose repositories cannot be listed is shown as such in the subtotals and
+// counted apart from the repositories in the totals. With a checkpoint,
+// an interrupted run is resumed like any other multi-repository run: the
+// repositories that already succeeded are skipped in every organization.
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// organizationSubtotal counts the results of one organization of an enterprise run.
+type organizationSubtotal struct {
+	org       string
+	processed int
+	failed    int
+
+	// listingErr is why the organization's repositories could not be listed, if they could not
+	listingErr error
+}
+
+// RunEnterprise processes every repository of every organization of opts.Enterprise.
+// The mode is selected by opts exactly as in Run; opts.Repository is ignored.
+//
+// Requires a GitHub client supplied via WithGitHubClient.
+// Returns an error if the token is invalid or the organizations cannot be listed,
+// otherwise the aggregate batch result. An organization whose repositories cannot
+// be listed is reported as failed and the run continues with the next one; the
+// error then also counts the organizations that could not be listed.
+func (a *App) RunEnterprise(ctx context.Context, opts interfaces.CLIOptions) error {
+	if a.client == nil {
+		return fmt.Errorf("enterprise mode requires a GitHub client")
+	}
+
+	filter, err := newRepositoryFilter(opts)
+	if err != nil {
+		return err
+	}
+
+	if _, err := a.validateToken(ctx, opts); err != nil {
+		return err
+	}
+
+	a.writer.Verbose(fmt.Sprintf("Listing organizations for enterprise %s", opts.Enterprise))
+	orgs, err := a.client.ListEnterpriseOrganizations(ctx, opts.Enterprise)
+	if err != nil {
+		return fmt.Errorf("failed to list organizations: %w", err)
+	}
+	a.writer.Info(fmt.Sprintf("Found %d organizations in enterprise %s", len(orgs), opts.Enterprise))
+
+	var results []RepositoryResult
+	subtotals := make([]organizationSubtotal, 0, len(orgs))
+	for i, org := range orgs {
+		a.writer.Info(fmt.Sprintf("Organization %d/%d: %s", i+1, len(orgs), org))
+		orgResults, listingErr := a.processEnterpriseOrganization(ctx, opts, filter, org)
+
+		results = append(results, orgResults...)
+		subtotals = append(subtotals, organizationSubtotal{
+			org:        org,
+			processed:  len(orgResults),
+			failed:     countFailures(orgResults),
+			listingErr: listingErr,
+		})
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	a.writeResultTable(results)
+	a.writeOrganizationSubtotals(subtotals)
+
+	if opts.Audit {
+		err = a.auditError(results)
+	} else {
+		err = batchError(results)
+	}
+	return listingError(subtotals, err)
+}
+
+// listingError adds the organizations whose repositories could not be listed
+// to err, the error of the repositories that were. Returns err unchanged when
+// every organization was listed.
+func listingError(subtotals []organizationSubtotal, err error) error {
+	failed := 0
+	var code apperrors.ErrorCode
+	for _, subtotal := range subtotals {
+		if subtotal.listingErr != nil {
+			failed++
+			code = apperrors.MostSevere(code, failureCode(subtotal.listingErr))
+		}
+	}
+	if failed == 0 {
+		return err
+	}
+
+	if err != nil {
+		code = apperrors.MostSevere(code, failureCode(err))
+	}
+	return apperrors.NewListingError(failed, len(subtotals), code, err)
+}
+
+// processEnterpriseOrganization lists, filters and processes one organization's repositories.
+// A listing failure is returned, after reporting it and recording it in the
+// checkpoint as a failed result for the organization.
+func (a *App) processEnterpriseOrganization(ctx context.Context, opts interfaces.CLIOptions, filter *repositoryFilter, org string) ([]RepositoryResult, error) {
+	a.writer.Verbose(fmt.Sprintf("Listing repositories for organization %s", org))
+	repos, err := a.client.ListOrganizationRepositories(ctx, org)
+	if err != nil {
+		result := a.reportResult(opts, failedResult(org, fmt.Errorf("failed to list repositories: %w", err)), nil)
+		if err := a.recordCheckpoint(result); err != nil {
+			a.writer.Error(fmt.Sprintf("Cannot record progress in the checkpoint: %v", err))
+		}
+		return nil, result.Err
+	}
+
+	refs := a.selectRepositories(repos, filter)
+	a.writer.Info(fmt.Sprintf("Found %d repositories in %s (%d skipped)",
+		len(repos), org, len(repos)-len(refs)))
+
+	return a.processRepositories(ctx, opts, refs), nil
+}
+
+// writeOrganizationSubtotals writes one row per organization with its result
+// counts, or why its repositories could not be listed, followed by the number
+// of organizations that could not be listed, if any.
+func (a *App) writeOrganizationSubtotals(subtotals []organizationSubtotal) {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ORGANIZATION\tPROCESSED\tSUCCEEDED\tFAILED")
+	unlisted := 0
+	for _, subtotal := range subtotals {
+		if subtotal.listingErr != nil {
+			unlisted++
+			fmt.Fprintf(tw, "%s\tlisting failed: %v\n", subtotal.org, subtotal.listingErr)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\n", subtotal.org,
+			subtotal.processed, subtotal.processed-subtotal.failed, subtotal.failed)
+	}
+	_ = tw.Flush()
+	a.writeLines(buf.String())
+
+	if unlisted > 0 {
+		a.writer.Info(fmt.Sprintf("%d of %d organizations could not be listed", unlisted, len(subtotals)))
+	}
+}
@@ -0,0 +1,135 @@
+// Package app_test provides tests for enterprise mode in the App.
+//
+// These tests verify that App.RunEnterprise processes every organization of the
+// enterprise, reports a subtotal per organization, and continues past an
+// organization that cannot be listed, reporting and recording it as failed and
+// counting it apart from the repositories.
+package app_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/josejulio/ghautodelete/internal/app"
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// enterpriseClient returns a client for an enterprise with three organizations.
+// Listing the repositories of acme-legacy fails.
+func enterpriseClient() *mockGitHubClient {
+	return &mockGitHubClient{
+		ListEnterpriseOrganizationsFunc: func(ctx context.Context, slug string) ([]string, error) {
+			return []string{"acme", "acme-legacy", "acme-payments"}, nil
+		},
+		ListOrganizationRepositoriesFunc: func(ctx context.Context, org string) ([]interfaces.IRepository, error) {
+			if org == "acme-legacy" {
+				return nil, errors.New("forbidden")
+			}
+			return []interfaces.IRepository{
+				&mockListedRepository{owner: org, name: "api"},
+				&mockListedRepository{owner: org, name: "web"},
+			}, nil
+		},
+	}
+}
+
+// TestRunEnterpriseProcessesEveryOrganization verifies each organization is
+// processed and subtotalled, and a listing failure does not stop the run and
+// is counted apart from the repositories.
+func TestRunEnterpriseProcessesEveryOrganization(t *testing.T) {
+	// Arrange
+	mockWriter := &mockOutputWriter{}
+	mockConfigSvc := enablingConfigService()
+	application := app.NewApp(mockWriter, mockConfigSvc, &mockRepoParser{}, app.WithGitHubClient(enterpriseClient()))
+	opts := interfaces.CLIOptions{Enterprise: "acme-corp"}
+
+	// Act
+	err := application.RunEnterprise(context.Background(), opts)
+
+	// Assert
+	if err == nil {
+		t.Fatal("RunEnterprise() error = nil, expected the batch error for acme-legacy")
+	}
+	if len(mockConfigSvc.ConfigureCalls) != 4 {
+		t.Errorf("configured %d repositories, expected 4", len(mockConfigSvc.ConfigureCalls))
+	}
+	output := mockWriter.GetAllOutput()
+	for _, expected := range []string{
+		"Found 3 organizations in enterprise acme-corp",
+		"Organization 3/3: acme-payments",
+		"ORGANIZATION",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("output should contain %q, got:\n%s", expected, output)
+		}
+	}
+	for _, expected := range []string{
+		"4 repositories processed: 4 succeeded, 0 failed",
+		"listing failed: failed to list repositories: forbidden",
+		"1 of 3 organizations could not be listed",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("output should contain %q, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(err.Error(), "repositories failed") {
+		t.Errorf("error = %q, should not count acme-legacy as a failed repository", err.Error())
+	}
+}
+
+// TestRunEnterpriseListingErrorKeepsRepositoryFailures verifies the error of
+// a run with a listing failure also reports the repositories that failed, and
+// the exit code is the most severe of both.
+func TestRunEnterpriseListingErrorKeepsRepositoryFailures(t *testing.T) {
+	// Arrange
+	mockConfigSvc := &mockConfigService{
+		ConfigureFunc: func(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+			if owner == "acme" && name == "web" {
+				return nil, apperrors.NewAuthorizationError("forbidden")
+			}
+			return newMockConfigResult(false, true, "main", owner+"/"+name), nil
+		},
+	}
+	application := app.NewApp(&mockOutputWriter{}, mockConfigSvc, &mockRepoParser{}, app.WithGitHubClient(enterpriseClient()))
+
+	// Act
+	err := application.RunEnterprise(context.Background(), interfaces.CLIOptions{Enterprise: "acme-corp"})
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 4 {
+		t.Errorf("exit code = %d, expected 4 (err: %v)", code, err)
+	}
+	if err == nil || err.Error() != "1 of 3 organizations could not be listed: 1 of 4 repositories failed" {
+		t.Errorf("error = %v, expected both the listing and the repository failures", err)
+	}
+}
+
+// TestRunEnterpriseReportsListingFailures verifies an organization that cannot
+// be listed is reported and recorded in the checkpoint like a failed repository.
+func TestRunEnterpriseReportsListingFailures(t *testing.T) {
+	// Arrange
+	reporter := &mockResultReporter{}
+	progress := &mockCheckpoint{}
+	application := app.NewApp(&mockOutputWriter{}, enablingConfigService(), &mockRepoParser{},
+		app.WithGitHubClient(enterpriseClient()), app.WithResultReporter(reporter), app.WithCheckpoint(progress))
+
+	// Act
+	err := application.RunEnterprise(context.Background(), interfaces.CLIOptions{Enterprise: "acme-corp"})
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 1 {
+		t.Errorf("exit code = %d, expected 1 (err: %v)", code, err)
+	}
+	if len(reporter.Reports) != 5 {
+		t.Fatalf("reported %d results, expected 4 repositories and acme-legacy", len(reporter.Reports))
+	}
+	if report := reporter.Reports[2]; report.Repository != "acme-legacy" || report.Status != "failed" || !strings.Contains(report.Error, "forbidden") {
+		t.Errorf("report = %+v, expected the listing failure of acme-legacy", report)
+	}
+	if len(progress.records) != 5 || progress.records[2].Repository != "acme-legacy" || progress.records[2].Error == "" {
+		t.Errorf("records = %+v, expected the listing failure of acme-legacy among them", progress.records)
+	}
+}
@@ -0,0 +1,185 @@
+// Package app provides the repository selection filters for listing modes.
+//
+// Organization, user, search and enterprise runs list many repositories;
+// repositoryFilter narrows them down by archived/fork/template status, topics,
+// visibility, primary language, name patterns and last push before any
+// repository is configured.
+// Every decision is reported as a verbose message.
+package app
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/josejulio/ghautodelete/internal/dates"
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// validVisibilities lists the accepted values of CLIOptions.Visibility.
+var validVisibilities = map[string]bool{"public": true, "private": true, "internal": true}
+
+// repositoryFilter selects listed repositories. The zero value selects every repository.
+type repositoryFilter struct {
+	skipArchived  bool
+	skipForks     bool
+	skipTemplates bool
+	topics        []string
+	visibility    string
+	language      string
+	include       []namePattern
+	exclude       []namePattern
+	pushedSince   time.Time
+}
+
+// namePattern matches repository names against a glob or a regular expression.
+type namePattern struct {
+	source string
+	glob   string
+	regex  *regexp.Regexp
+}
+
+// newRepositoryFilter builds the filter described by opts.
+// Invalid visibilities, patterns and dates are reported as validation errors.
+func newRepositoryFilter(opts interfaces.CLIOptions) (*repositoryFilter, error) {
+	f := &repositoryFilter{
+		skipArchived:  opts.SkipArchived,
+		skipForks:     opts.SkipForks,
+		skipTemplates: opts.SkipTemplates,
+		visibility:    strings.ToLower(opts.Visibility),
+		language:      opts.Language,
+	}
+
+	for _, topic := range opts.Topics {
+		if topic = strings.ToLower(strings.TrimSpace(topic)); topic != "" {
+			f.topics = append(f.topics, topic)
+		}
+	}
+
+	if f.visibility != "" && !validVisibilities[f.visibility] {
+		return nil, apperrors.NewValidationError(fmt.Sprintf(
+			"Invalid visibility %q. Expected public, private or internal", opts.Visibility))
+	}
+
+	var err error
+	if f.include, err = compilePatterns("--include", opts.Include); err != nil {
+		return nil, err
+	}
+	if f.exclude, err = compilePatterns("--exclude", opts.Exclude); err != nil {
+		return nil, err
+	}
+
+	if opts.PushedSince != "" {
+		if f.pushedSince, err = dates.Parse(opts.PushedSince, false); err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+// compilePatterns compiles the name patterns given to flag.
+// A pattern written as /regex/ is a regular expression; anything else is a glob.
+func compilePatterns(flag string, sources []string) ([]namePattern, error) {
+	patterns := make([]namePattern, 0, len(sources))
+	for _, source := range sources {
+		pattern := namePattern{source: source}
+
+		if len(source) > 2 && strings.HasPrefix(source, "/") && strings.HasSuffix(source, "/") {
+			regex, err := regexp.Compile("(?i)" + source[1:len(source)-1])
+			if err != nil {
+				return nil, apperrors.NewValidationError(fmt.Sprintf("Invalid %s regular expression %s: %v", flag, source, err))
+			}
+			pattern.regex = regex
+		} else {
+			pattern.glob = strings.ToLower(source)
+			if _, err := path.Match(pattern.glob, ""); err != nil {
+				return nil, apperrors.NewValidationError(fmt.Sprintf("Invalid %s pattern %q: %v", flag, source, err))
+			}
+		}
+
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// matches reports whether the pattern matches the repository name or its
+// owner/name full name, case-insensitively.
+func (p namePattern) matches(repo interfaces.IRepository) bool {
+	for _, name := range []string{repo.GetName(), repo.GetFullName()} {
+		if p.regex != nil {
+			if p.regex.MatchString(name) {
+				return true
+			}
+			continue
+		}
+		if matched, _ := path.Match(p.glob, strings.ToLower(name)); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// skipReason returns why a repository is excluded by the filter, or "" if it is selected.
+func (f *repositoryFilter) skipReason(repo interfaces.IRepository) string {
+	switch {
+	case f.skipArchived && repo.IsArchived():
+		return "archived"
+	case f.skipForks && repo.IsFork():
+		return "fork"
+	case f.skipTemplates && repo.IsTemplate():
+		return "template"
+	case f.visibility != "" && !strings.EqualFold(repo.GetVisibility(), f.visibility):
+		return fmt.Sprintf("visibility is %s, not %s", repo.GetVisibility(), f.visibility)
+	case f.language != "" && !strings.EqualFold(repo.GetLanguage(), f.language):
+		if repo.GetLanguage() == "" {
+			return fmt.Sprintf("no primary language, not %s", f.language)
+		}
+		return fmt.Sprintf("language is %s, not %s", repo.GetLanguage(), f.language)
+	case !f.pushedSince.IsZero() && repo.GetPushedAt().Before(f.pushedSince):
+		if repo.GetPushedAt().IsZero() {
+			return "never pushed to"
+		}
+		return fmt.Sprintf("last pushed %s", repo.GetPushedAt().Format("2006-01-02"))
+	}
+
+	if topic := f.missingTopic(repo); topic != "" {
+		return fmt.Sprintf("no topic %q", topic)
+	}
+	if len(f.include) > 0 && !anyMatches(f.include, repo) {
+		return "name does not match --include"
+	}
+	for _, pattern := range f.exclude {
+		if pattern.matches(repo) {
+			return fmt.Sprintf("name matches --exclude %s", pattern.source)
+		}
+	}
+	return ""
+}
+
+// missingTopic returns the first required topic the repository does not have, or "".
+func (f *repositoryFilter) missingTopic(repo interfaces.IRepository) string {
+	have := make(map[string]bool, len(repo.GetTopics()))
+	for _, topic := range repo.GetTopics() {
+		have[strings.ToLower(topic)] = true
+	}
+	for _, topic := range f.topics {
+		if !have[topic] {
+			return topic
+		}
+	}
+	return ""
+}
+
+// anyMatches reports whether any of patterns matches the repository.
+func anyMatches(patterns []namePattern, repo interfaces.IRepository) bool {
+	for _, pattern := range patterns {
+		if pattern.matches(repo) {
+			return true
+		}
+	}
+	return false
+}
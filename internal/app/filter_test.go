@@ -0,0 +1,168 @@
+// Package app_test provides tests for the repository selection filters.
+//
+// These tests verify that listed repositories are narrowed down by topic,
+// visibility, language, name patterns and last push before any repository is
+// configured, that each decision is reported verbosely, and that invalid
+// filters are rejected before anything is listed.
+package app_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/josejulio/ghautodelete/internal/app"
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+// catalogRepositories returns an organization listing with varied metadata.
+func catalogRepositories() []interfaces.IRepository {
+	recent := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	stale := time.Date(2021, 1, 15, 0, 0, 0, 0, time.UTC)
+	return []interfaces.IRepository{
+		&mockListedRepository{owner: "acme", name: "api", visibility: "private", language: "Go", topics: []string{"service", "Backend"}, pushedAt: recent},
+		&mockListedRepository{owner: "acme", name: "web", visibility: "public", language: "TypeScript", topics: []string{"service"}, pushedAt: recent},
+		&mockListedRepository{owner: "acme", name: "exp-cache", visibility: "internal", language: "Go", topics: []string{"service"}, pushedAt: stale},
+		&mockListedRepository{owner: "acme", name: "docs", visibility: "public"},
+	}
+}
+
+// =============================================================================
+// Selection Filter Tests
+// =============================================================================
+
+// TestRunOrganizationAppliesSelectionFilters verifies each filter and its verbose reason.
+func TestRunOrganizationAppliesSelectionFilters(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          interfaces.CLIOptions
+		expectedNames []string
+		expectedSkip  string
+	}{
+		{
+			name:          "topics must all match",
+			opts:          interfaces.CLIOptions{Topics: []string{"service", "backend"}},
+			expectedNames: []string{"api"},
+			expectedSkip:  `Skipping acme/web: no topic "backend"`,
+		},
+		{
+			name:          "visibility",
+			opts:          interfaces.CLIOptions{Visibility: "Public"},
+			expectedNames: []string{"web", "docs"},
+			expectedSkip:  "Skipping acme/api: visibility is private, not public",
+		},
+		{
+			name:          "language",
+			opts:          interfaces.CLIOptions{Language: "go"},
+			expectedNames: []string{"api", "exp-cache"},
+			expectedSkip:  "Skipping acme/docs: no primary language, not go",
+		},
+		{
+			name:          "include glob on the full name",
+			opts:          interfaces.CLIOptions{Include: []string{"acme/*e*"}},
+			expectedNames: []string{"web", "exp-cache"},
+			expectedSkip:  "Skipping acme/api: name does not match --include",
+		},
+		{
+			name:          "exclude regex",
+			opts:          interfaces.CLIOptions{Exclude: []string{"/^(exp-|docs$)/"}},
+			expectedNames: []string{"api", "web"},
+			expectedSkip:  "Skipping acme/exp-cache: name matches --exclude /^(exp-|docs$)/",
+		},
+		{
+			name:          "pushed since",
+			opts:          interfaces.CLIOptions{PushedSince: "2024-01-01"},
+			expectedNames: []string{"api", "web"},
+			expectedSkip:  "Skipping acme/exp-cache: last pushed 2021-01-15",
+		},
+		{
+			name:          "combined",
+			opts:          interfaces.CLIOptions{Topics: []string{"service"}, Language: "Go", Exclude: []string{"exp-*"}},
+			expectedNames: []string{"api"},
+			expectedSkip:  "Found 4 repositories in acme (3 skipped)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockWriter := &mockOutputWriter{}
+			mockConfigSvc := enablingConfigService()
+			mockClient := &mockGitHubClient{
+				ListOrganizationRepositoriesFunc: func(ctx context.Context, org string) ([]interfaces.IRepository, error) {
+					return catalogRepositories(), nil
+				},
+			}
+			application := app.NewApp(mockWriter, mockConfigSvc, &mockRepoParser{}, app.WithGitHubClient(mockClient))
+			tt.opts.Organization = "acme"
+
+			// Act
+			err := application.RunOrganization(context.Background(), tt.opts)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("RunOrganization() error = %v, expected nil", err)
+			}
+			var names []string
+			for _, call := range mockConfigSvc.ConfigureCalls {
+				names = append(names, call.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expectedNames, ",") {
+				t.Errorf("configured %v, expected %v", names, tt.expectedNames)
+			}
+			output := mockWriter.GetAllOutput()
+			if !strings.Contains(output, tt.expectedSkip) {
+				t.Errorf("output should contain %q, got:\n%s", tt.expectedSkip, output)
+			}
+			if !strings.Contains(output, "Selected acme/"+tt.expectedNames[0]) {
+				t.Errorf("output should report the selection of acme/%s, got:\n%s", tt.expectedNames[0], output)
+			}
+		})
+	}
+}
+
+// TestRunOrganizationRejectsInvalidFilters verifies invalid filters fail with a
+// validation error before the organization is listed.
+func TestRunOrganizationRejectsInvalidFilters(t *testing.T) {
+	tests := []struct {
+		name string
+		opts interfaces.CLIOptions
+	}{
+		{name: "visibility", opts: interfaces.CLIOptions{Visibility: "secret"}},
+		{name: "include glob", opts: interfaces.CLIOptions{Include: []string{"api-["}}},
+		{name: "exclude regex", opts: interfaces.CLIOptions{Exclude: []string{"/api(/"}}},
+		{name: "pushed since", opts: interfaces.CLIOptions{PushedSince: "last week"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			listed := false
+			mockClient := &mockGitHubClient{
+				ListOrganizationRepositoriesFunc: func(ctx context.Context, org string) ([]interfaces.IRepository, error) {
+					listed = true
+					return catalogRepositories(), nil
+				},
+			}
+			application := app.NewApp(&mockOutputWriter{}, enablingConfigService(), &mockRepoParser{}, app.WithGitHubClient(mockClient))
+			tt.opts.Organization = "acme"
+
+			// Act
+			err := application.RunOrganization(context.Background(), tt.opts)
+
+			// Assert
+			if code := apperrors.GetExitCode(err); code != 2 {
+				t.Errorf("exit code = %d, expected 2 (err: %v)", code, err)
+			}
+			if listed {
+				t.Error("the organization should not be listed with invalid filters")
+			}
+		})
+	}
+}
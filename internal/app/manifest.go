@@ -0,0 +1,146 @@
+// Package app provides the plan/apply workflow for desired-state manifests.
+//
+// The manifest's repositories and organizations are resolved to individual
+// repositories, each repository is reconciled against its desired settings and
+// the differences are reported. Plan is apply in dry-run mode.
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/josejulio/ghautodelete/internal/manifest"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// Manifest status values reported in the result table.
+const (
+	statusUpToDate    = "up to date"
+	statusChanged     = "changed"
+	statusWouldChange = "would change"
+)
+
+// manifestTarget is a repository resolved from a manifest with its desired settings.
+type manifestTarget struct {
+	ref      repositoryRef
+	settings interfaces.IRepositorySettings
+}
+
+// RunManifest reconciles every repository declared in m with its desired settings.
+// With opts.DryRun or opts.CheckOnly the differences are reported without updating
+// (plan); otherwise only the differing settings are updated (apply).
+//
+// Organizations require a GitHub client supplied via WithGitHubClient. A repository
+// declared explicitly takes precedence over the same repository found in an organization.
+// The token is validated first; other failures do not stop processing and are
+// returned as the aggregate batch result.
+func (a *App) RunManifest(ctx context.Context, opts interfaces.CLIOptions, m *manifest.Manifest) error {
+	dryRun := opts.DryRun || opts.CheckOnly
+	if _, err := a.configSvc.ValidateToken(ctx, !dryRun); err != nil {
+		return err
+	}
+
+	targets, failures := a.manifestTargets(ctx, m)
+
+	results := make([]RepositoryResult, len(targets))
+	a.runInOrder(ctx, opts.Concurrency, len(targets), func(worker *App, i int) {
+		results[i] = worker.reconcileRepository(ctx, targets[i], dryRun)
+	}, func(worker *App, i int) {
+		results[i] = worker.reportReconcile(dryRun, cancelledResult(targets[i].ref.displayName()), nil)
+	})
+	results = append(failures, results...)
+
+	a.writeResultTable(results)
+	if dryRun {
+		a.writer.Info("No changes made")
+	}
+
+	return batchError(results)
+}
+
+// manifestTargets resolves the manifest's repositories and organizations.
+// Organizations that cannot be listed are returned as failed results.
+func (a *App) manifestTargets(ctx context.Context, m *manifest.Manifest) ([]manifestTarget, []RepositoryResult) {
+	var targets []manifestTarget
+	var failures []RepositoryResult
+	seen := make(map[string]bool)
+
+	for _, repo := range m.Repositories {
+		owner, name, err := a.parser.Parse(repo.Name)
+		ref := repositoryRef{identifier: repo.Name, owner: owner, name: name, err: err}
+		if err == nil {
+			seen[strings.ToLower(fmt.Sprintf("%s/%s", owner, name))] = true
+		}
+		targets = append(targets, manifestTarget{ref: ref, settings: m.RepositorySettings(repo)})
+	}
+
+	for _, org := range m.Organizations {
+		if a.client == nil {
+			failures = append(failures, failedResult(org.Name, fmt.Errorf("organization mode requires a GitHub client")))
+			continue
+		}
+
+		a.writer.Verbose(fmt.Sprintf("Listing repositories for organization %s", org.Name))
+		repos, err := a.client.ListOrganizationRepositories(ctx, org.Name)
+		if err != nil {
+			failures = append(failures, failedResult(org.Name, fmt.Errorf("failed to list repositories: %w", err)))
+			continue
+		}
+
+		filter := &repositoryFilter{
+			skipArchived:  org.ShouldSkipArchived(),
+			skipForks:     org.SkipForks,
+			skipTemplates: org.SkipTemplates,
+		}
+		refs := a.selectRepositories(repos, filter)
+		a.writer.Info(fmt.Sprintf("Found %d repositories in %s (%d skipped)", len(repos), org.Name, len(repos)-len(refs)))
+
+		settings := m.OrganizationSettings(org)
+		for _, ref := range refs {
+			// GitHub repository names are case-insensitive
+			key := strings.ToLower(ref.identifier)
+			if seen[key] {
+				a.writer.Verbose(fmt.Sprintf("Skipping %s: declared explicitly", ref.identifier))
+				continue
+			}
+			seen[key] = true
+			targets = append(targets, manifestTarget{ref: ref, settings: settings})
+		}
+	}
+
+	return targets, failures
+}
+
+// reconcileRepository reconciles a single repository and reports its differences.
+// Errors are captured in the returned result rather than returned.
+func (a *App) reconcileRepository(ctx context.Context, target manifestTarget, dryRun bool) RepositoryResult {
+	ref := target.ref
+	if ref.err != nil {
+		return a.reportReconcile(dryRun, failedResult(ref.identifier, ref.err), nil)
+	}
+
+	fullName := fmt.Sprintf("%s/%s", ref.owner, ref.name)
+	a.writer.Verbose(fmt.Sprintf("Processing %s", fullName))
+
+	changes, err := a.configSvc.Reconcile(ctx, ref.owner, ref.name, target.settings, dryRun)
+	if err != nil {
+		return a.reportReconcile(dryRun, failedResult(fullName, err), nil)
+	}
+
+	if len(changes) == 0 {
+		return a.reportReconcile(dryRun, RepositoryResult{Repository: fullName, Status: statusUpToDate}, nil)
+	}
+
+	// Written as one message so concurrent workers cannot interleave the lines
+	lines := []string{fmt.Sprintf("~ %s", fullName)}
+	for _, change := range changes {
+		lines = append(lines, fmt.Sprintf("    %s: %s -> %s", change.Setting, change.Current, change.Desired))
+	}
+	a.writer.Info(strings.Join(lines, "\n"))
+
+	if dryRun {
+		return a.reportReconcile(dryRun, RepositoryResult{Repository: fullName, Status: statusWouldChange}, changes)
+	}
+	return a.reportReconcile(dryRun, RepositoryResult{Repository: fullName, Status: statusChanged}, changes)
+}
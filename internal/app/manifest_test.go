@@ -0,0 +1,257 @@
+// Package app_test provides tests for the manifest plan/apply workflow in the App.
+//
+// These tests verify that App.RunManifest resolves declared repositories and
+// organizations, reconciles each with its effective settings, reports the
+// differences and honors dry-run (plan) mode.
+package app_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/josejulio/ghautodelete/internal/app"
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/manifest"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+// parseManifest parses a manifest or fails the test.
+func parseManifest(t *testing.T, content string) *manifest.Manifest {
+	t.Helper()
+	m, err := manifest.Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("manifest.Parse() error = %v", err)
+	}
+	return m
+}
+
+// =============================================================================
+// Manifest Tests
+// =============================================================================
+
+// TestRunManifestPlanReportsDifferences verifies plan mode reports changes without applying them.
+func TestRunManifestPlanReportsDifferences(t *testing.T) {
+	// Arrange
+	mockWriter := &mockOutputWriter{}
+	mockConfigSvc := &mockConfigService{
+		ReconcileFunc: func(ctx context.Context, owner, name string, desired interfaces.IRepositorySettings, dryRun bool) ([]interfaces.SettingChange, error) {
+			if name == "api" {
+				return []interfaces.SettingChange{{Setting: "delete_branch_on_merge", Current: "false", Desired: "true"}}, nil
+			}
+			return nil, nil
+		},
+	}
+	application := app.NewApp(mockWriter, mockConfigSvc, splitOwnerRepoParser())
+	m := parseManifest(t, "settings:\n  delete_branch_on_merge: true\nrepositories:\n  - name: acme/api\n  - name: acme/web\n")
+
+	// Act
+	err := application.RunManifest(context.Background(), interfaces.CLIOptions{DryRun: true}, m)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("RunManifest() error = %v, expected nil", err)
+	}
+	for _, call := range mockConfigSvc.ReconcileCalls {
+		if !call.DryRun {
+			t.Errorf("Reconcile(%s) dryRun = false, expected true in plan mode", call.Name)
+		}
+	}
+	output := mockWriter.GetAllOutput()
+	for _, want := range []string{
+		"~ acme/api",
+		"    delete_branch_on_merge: false -> true",
+		"acme/api    would change",
+		"acme/web    up to date",
+		"No changes made",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+// TestRunManifestApplyUsesEffectiveSettings verifies apply mode and per-target settings.
+func TestRunManifestApplyUsesEffectiveSettings(t *testing.T) {
+	// Arrange
+	mockWriter := &mockOutputWriter{}
+	mockConfigSvc := &mockConfigService{
+		ReconcileFunc: func(ctx context.Context, owner, name string, desired interfaces.IRepositorySettings, dryRun bool) ([]interfaces.SettingChange, error) {
+			return []interfaces.SettingChange{{Setting: "allow_auto_merge", Current: "false", Desired: "true"}}, nil
+		},
+	}
+	application := app.NewApp(mockWriter, mockConfigSvc, splitOwnerRepoParser())
+	m := parseManifest(t, `
+settings:
+  delete_branch_on_merge: true
+repositories:
+  - name: acme/api
+    settings:
+      allow_auto_merge: true
+`)
+
+	// Act
+	err := application.RunManifest(context.Background(), interfaces.CLIOptions{}, m)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("RunManifest() error = %v, expected nil", err)
+	}
+	if len(mockConfigSvc.ReconcileCalls) != 1 {
+		t.Fatalf("expected 1 Reconcile call, got %d", len(mockConfigSvc.ReconcileCalls))
+	}
+	call := mockConfigSvc.ReconcileCalls[0]
+	if call.DryRun {
+		t.Error("Reconcile dryRun = true, expected false in apply mode")
+	}
+	if !call.Desired.GetDeleteBranchOnMerge() || call.Desired.GetAllowAutoMerge() == nil || !*call.Desired.GetAllowAutoMerge() {
+		t.Error("desired settings should combine manifest-wide and repository settings")
+	}
+	if !strings.Contains(mockWriter.GetAllOutput(), "acme/api    changed") {
+		t.Errorf("output should report the change, got:\n%s", mockWriter.GetAllOutput())
+	}
+}
+
+// TestRunManifestResolvesOrganizations verifies organizations are listed, filtered and de-duplicated.
+func TestRunManifestResolvesOrganizations(t *testing.T) {
+	// Arrange
+	mockWriter := &mockOutputWriter{}
+	mockConfigSvc := &mockConfigService{
+		ReconcileFunc: func(ctx context.Context, owner, name string, desired interfaces.IRepositorySettings, dryRun bool) ([]interfaces.SettingChange, error) {
+			return nil, nil
+		},
+	}
+	mockClient := &mockGitHubClient{
+		ListOrganizationRepositoriesFunc: func(ctx context.Context, org string) ([]interfaces.IRepository, error) {
+			return acmeRepositories(), nil
+		},
+	}
+	application := app.NewApp(mockWriter, mockConfigSvc, splitOwnerRepoParser(), app.WithGitHubClient(mockClient))
+	m := parseManifest(t, `
+settings:
+  delete_branch_on_merge: true
+repositories:
+  - name: acme/api
+    settings:
+      allow_auto_merge: true
+organizations:
+  - name: acme
+    skip_forks: true
+`)
+
+	// Act
+	err := application.RunManifest(context.Background(), interfaces.CLIOptions{}, m)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("RunManifest() error = %v, expected nil", err)
+	}
+	var names []string
+	for _, call := range mockConfigSvc.ReconcileCalls {
+		names = append(names, call.Name)
+	}
+	if strings.Join(names, ",") != "api,service-template" {
+		t.Errorf("reconciled %v, expected [api service-template]", names)
+	}
+	if mockConfigSvc.ReconcileCalls[0].Desired.GetAllowAutoMerge() == nil {
+		t.Error("explicitly declared repository should keep its own settings")
+	}
+	if !strings.Contains(mockWriter.GetAllOutput(), "Found 4 repositories in acme (2 skipped)") {
+		t.Errorf("output should report the organization listing, got:\n%s", mockWriter.GetAllOutput())
+	}
+}
+
+// TestRunManifestDeduplicatesMixedCase verifies a repository declared, or
+// listed, with other letter case is reconciled once.
+func TestRunManifestDeduplicatesMixedCase(t *testing.T) {
+	// Arrange
+	mockConfigSvc := &mockConfigService{
+		ReconcileFunc: func(ctx context.Context, owner, name string, desired interfaces.IRepositorySettings, dryRun bool) ([]interfaces.SettingChange, error) {
+			return nil, nil
+		},
+	}
+	mockClient := &mockGitHubClient{
+		ListOrganizationRepositoriesFunc: func(ctx context.Context, org string) ([]interfaces.IRepository, error) {
+			return []interfaces.IRepository{
+				&mockListedRepository{owner: "acme", name: "api"},
+				&mockListedRepository{owner: "acme", name: "web"},
+			}, nil
+		},
+	}
+	application := app.NewApp(&mockOutputWriter{}, mockConfigSvc, splitOwnerRepoParser(), app.WithGitHubClient(mockClient))
+	m := parseManifest(t, `
+settings:
+  delete_branch_on_merge: true
+repositories:
+  - name: ACME/Api
+organizations:
+  - name: acme
+  - name: Acme
+`)
+
+	// Act
+	err := application.RunManifest(context.Background(), interfaces.CLIOptions{}, m)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("RunManifest() error = %v, expected nil", err)
+	}
+	var names []string
+	for _, call := range mockConfigSvc.ReconcileCalls {
+		names = append(names, call.Owner+"/"+call.Name)
+	}
+	if strings.Join(names, ",") != "ACME/Api,acme/web" {
+		t.Errorf("reconciled %v, expected [ACME/Api acme/web]", names)
+	}
+}
+
+// TestRunManifestContinuesPastFailures verifies failures are reported and aggregated.
+func TestRunManifestContinuesPastFailures(t *testing.T) {
+	// Arrange
+	mockWriter := &mockOutputWriter{}
+	mockConfigSvc := &mockConfigService{
+		ReconcileFunc: func(ctx context.Context, owner, name string, desired interfaces.IRepositorySettings, dryRun bool) ([]interfaces.SettingChange, error) {
+			if name == "missing" {
+				return nil, apperrors.NewRepositoryNotFoundError(owner, name)
+			}
+			return nil, nil
+		},
+	}
+	mockClient := &mockGitHubClient{
+		ListOrganizationRepositoriesFunc: func(ctx context.Context, org string) ([]interfaces.IRepository, error) {
+			return nil, apperrors.NewOrganizationNotFoundError(org)
+		},
+	}
+	application := app.NewApp(mockWriter, mockConfigSvc, splitOwnerRepoParser(), app.WithGitHubClient(mockClient))
+	m := parseManifest(t, `
+settings:
+  delete_branch_on_merge: true
+repositories:
+  - name: acme/missing
+  - name: acme/web
+organizations:
+  - name: ghost
+`)
+
+	// Act
+	err := application.RunManifest(context.Background(), interfaces.CLIOptions{}, m)
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 5 {
+		t.Errorf("exit code = %d, expected 5 (err: %v)", code, err)
+	}
+	output := mockWriter.GetAllOutput()
+	for _, want := range []string{
+		"ghost         failed: failed to list repositories: Organization not found: ghost",
+		"acme/web      up to date",
+		"3 repositories processed: 1 succeeded, 2 failed",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q, got:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Package app provides organization-wide processing.
+//
+// Organization mode lists every repository of a GitHub organization, drops the
+// repositories excluded by the selection filters, and runs the rest through the
+// same batch workflow as an explicit repository list.
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// RunOrganization processes every repository of opts.Organization.
+// The mode is selected by opts exactly as in Run; opts.Repository is ignored.
+//
+// Requires a GitHub client supplied via WithGitHubClient.
+// Returns an error if the token is invalid or listing fails, otherwise the aggregate batch result.
+func (a *App) RunOrganization(ctx context.Context, opts interfaces.CLIOptions) error {
+	refs, err := a.organizationRefs(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	return a.runRepositories(ctx, opts, refs)
+}
+
+// organizationRefs validates the token, lists every repository of
+// opts.Organization and applies the filters from opts.
+func (a *App) organizationRefs(ctx context.Context, opts interfaces.CLIOptions) ([]repositoryRef, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("organization mode requires a GitHub client")
+	}
+
+	filter, err := newRepositoryFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := a.validateToken(ctx, opts); err != nil {
+		return nil, err
+	}
+
+	a.writer.Verbose(fmt.Sprintf("Listing repositories for organization %s", opts.Organization))
+	repos, err := a.client.ListOrganizationRepositories(ctx, opts.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	refs := a.selectRepositories(repos, filter)
+	a.writer.Info(fmt.Sprintf("Found %d repositories in %s (%d skipped)",
+		len(repos), opts.Organization, len(repos)-len(refs)))
+	return refs, nil
+}
+
+// selectRepositories applies filter to the listed repositories.
+// Every selection and skip is reported as a verbose message.
+func (a *App) selectRepositories(repos []interfaces.IRepository, filter *repositoryFilter) []repositoryRef {
+	refs := make([]repositoryRef, 0, len(repos))
+	for _, repo := range repos {
+		if reason := filter.skipReason(repo); reason != "" {
+			a.writer.Verbose(fmt.Sprintf("Skipping %s: %s", repo.GetFullName(), reason))
+			continue
+		}
+
+		a.writer.Verbose(fmt.Sprintf("Selected %s", repo.GetFullName()))
+		refs = append(refs, repositoryRef{
+			identifier: repo.GetFullName(),
+			owner:      repo.GetOwner(),
+			name:       repo.GetName(),
+		})
+	}
+	return refs
+}
@@ -0,0 +1,255 @@
+// Package app_test provides tests for organization mode in the App.
+//
+// These tests verify that App.RunOrganization lists the organization's
+// repositories through the GitHub client, applies the archived/fork/template
+// filters, and runs the remaining repositories through the batch workflow.
+package app_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/josejulio/ghautodelete/internal/app"
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// =============================================================================
+// Mock Implementations for Testing
+// =============================================================================
+
+// mockListedRepository implements IRepository for repositories returned by listings.
+type mockListedRepository struct {
+	owner    string
+	name     string
+	archived bool
+	fork     bool
+	template bool
+
+	visibility string
+	topics     []string
+	language   string
+	pushedAt   time.Time
+}
+
+func (m *mockListedRepository) GetOwner() string             { return m.owner }
+func (m *mockListedRepository) GetName() string              { return m.name }
+func (m *mockListedRepository) GetDefaultBranch() string     { return "main" }
+func (m *mockListedRepository) GetDeleteBranchOnMerge() bool { return false }
+func (m *mockListedRepository) GetFullName() string          { return m.owner + "/" + m.name }
+func (m *mockListedRepository) IsArchived() bool             { return m.archived }
+func (m *mockListedRepository) IsFork() bool                 { return m.fork }
+func (m *mockListedRepository) IsTemplate() bool             { return m.template }
+func (m *mockListedRepository) IsPrivate() bool              { return false }
+func (m *mockListedRepository) GetVisibility() string        { return m.visibility }
+func (m *mockListedRepository) GetTopics() []string          { return m.topics }
+func (m *mockListedRepository) GetLanguage() string          { return m.language }
+func (m *mockListedRepository) GetPushedAt() time.Time       { return m.pushedAt }
+func (m *mockListedRepository) GetSettings() interfaces.IRepositorySettings {
+	return nil
+}
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+// acmeRepositories returns a fixed organization listing with one repository of each kind.
+func acmeRepositories() []interfaces.IRepository {
+	return []interfaces.IRepository{
+		&mockListedRepository{owner: "acme", name: "api"},
+		&mockListedRepository{owner: "acme", name: "legacy", archived: true},
+		&mockListedRepository{owner: "acme", name: "upstream-fork", fork: true},
+		&mockListedRepository{owner: "acme", name: "service-template", template: true},
+	}
+}
+
+// enablingConfigService returns a config service whose Configure always enables the feature.
+func enablingConfigService() *mockConfigService {
+	return &mockConfigService{
+		ConfigureFunc: func(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+			return newMockConfigResult(false, !dryRun, "main", owner+"/"+name), nil
+		},
+	}
+}
+
+// =============================================================================
+// Organization Mode Tests
+// =============================================================================
+
+// TestRunOrganizationConfiguresListedRepositories verifies every listed repository is configured.
+func TestRunOrganizationConfiguresListedRepositories(t *testing.T) {
+	// Arrange
+	mockWriter := &mockOutputWriter{}
+	mockConfigSvc := enablingConfigService()
+	mockClient := &mockGitHubClient{
+		ListOrganizationRepositoriesFunc: func(ctx context.Context, org string) ([]interfaces.IRepository, error) {
+			if org != "acme" {
+				t.Errorf("ListOrganizationRepositories org = %q, expected %q", org, "acme")
+			}
+			return acmeRepositories(), nil
+		},
+	}
+	application := app.NewApp(mockWriter, mockConfigSvc, &mockRepoParser{}, app.WithGitHubClient(mockClient))
+
+	// Act
+	err := application.RunOrganization(context.Background(), interfaces.CLIOptions{Organization: "acme"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("RunOrganization() error = %v, expected nil", err)
+	}
+	if len(mockConfigSvc.ConfigureCalls) != 4 {
+		t.Errorf("expected 4 Configure calls without filters, got %d", len(mockConfigSvc.ConfigureCalls))
+	}
+	output := mockWriter.GetAllOutput()
+	for _, want := range []string{
+		"Found 4 repositories in acme (0 skipped)",
+		"4 repositories processed: 4 succeeded, 0 failed",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+// TestRunOrganizationAppliesFilters verifies archived, fork and template filters.
+func TestRunOrganizationAppliesFilters(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          interfaces.CLIOptions
+		expectedNames []string
+		expectedSkip  string
+	}{
+		{
+			name:          "skip archived",
+			opts:          interfaces.CLIOptions{SkipArchived: true},
+			expectedNames: []string{"api", "upstream-fork", "service-template"},
+			expectedSkip:  "Skipping acme/legacy: archived",
+		},
+		{
+			name:          "skip forks",
+			opts:          interfaces.CLIOptions{SkipForks: true},
+			expectedNames: []string{"api", "legacy", "service-template"},
+			expectedSkip:  "Skipping acme/upstream-fork: fork",
+		},
+		{
+			name:          "skip templates",
+			opts:          interfaces.CLIOptions{SkipTemplates: true},
+			expectedNames: []string{"api", "legacy", "upstream-fork"},
+			expectedSkip:  "Skipping acme/service-template: template",
+		},
+		{
+			name:          "all filters",
+			opts:          interfaces.CLIOptions{SkipArchived: true, SkipForks: true, SkipTemplates: true},
+			expectedNames: []string{"api"},
+			expectedSkip:  "Found 4 repositories in acme (3 skipped)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockWriter := &mockOutputWriter{}
+			mockConfigSvc := enablingConfigService()
+			mockClient := &mockGitHubClient{
+				ListOrganizationRepositoriesFunc: func(ctx context.Context, org string) ([]interfaces.IRepository, error) {
+					return acmeRepositories(), nil
+				},
+			}
+			application := app.NewApp(mockWriter, mockConfigSvc, &mockRepoParser{}, app.WithGitHubClient(mockClient))
+			tt.opts.Organization = "acme"
+
+			// Act
+			err := application.RunOrganization(context.Background(), tt.opts)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("RunOrganization() error = %v, expected nil", err)
+			}
+			var names []string
+			for _, call := range mockConfigSvc.ConfigureCalls {
+				names = append(names, call.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expectedNames, ",") {
+				t.Errorf("configured %v, expected %v", names, tt.expectedNames)
+			}
+			if !strings.Contains(mockWriter.GetAllOutput(), tt.expectedSkip) {
+				t.Errorf("output should contain %q, got:\n%s", tt.expectedSkip, mockWriter.GetAllOutput())
+			}
+		})
+	}
+}
+
+// TestRunOrganizationRespectsCheckMode verifies check mode never calls Configure.
+func TestRunOrganizationRespectsCheckMode(t *testing.T) {
+	// Arrange
+	mockConfigSvc := &mockConfigService{
+		CheckStatusFunc: func(ctx context.Context, owner, name string) (interfaces.IConfigResult, error) {
+			return newMockConfigResult(false, false, "main", owner+"/"+name), nil
+		},
+	}
+	mockClient := &mockGitHubClient{
+		ListOrganizationRepositoriesFunc: func(ctx context.Context, org string) ([]interfaces.IRepository, error) {
+			return acmeRepositories(), nil
+		},
+	}
+	application := app.NewApp(&mockOutputWriter{}, mockConfigSvc, &mockRepoParser{}, app.WithGitHubClient(mockClient))
+
+	// Act
+	err := application.RunOrganization(context.Background(), interfaces.CLIOptions{Organization: "acme", CheckOnly: true})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("RunOrganization() error = %v, expected nil", err)
+	}
+	if len(mockConfigSvc.ConfigureCalls) != 0 {
+		t.Errorf("check mode should not call Configure, got %d calls", len(mockConfigSvc.ConfigureCalls))
+	}
+	if len(mockConfigSvc.CheckStatusCalls) != 4 {
+		t.Errorf("expected 4 CheckStatus calls, got %d", len(mockConfigSvc.CheckStatusCalls))
+	}
+}
+
+// TestRunOrganizationPropagatesListingError verifies listing failures keep their exit code.
+func TestRunOrganizationPropagatesListingError(t *testing.T) {
+	// Arrange
+	mockConfigSvc := enablingConfigService()
+	mockClient := &mockGitHubClient{
+		ListOrganizationRepositoriesFunc: func(ctx context.Context, org string) ([]interfaces.IRepository, error) {
+			return nil, apperrors.NewOrganizationNotFoundError(org)
+		},
+	}
+	application := app.NewApp(&mockOutputWriter{}, mockConfigSvc, &mockRepoParser{}, app.WithGitHubClient(mockClient))
+
+	// Act
+	err := application.RunOrganization(context.Background(), interfaces.CLIOptions{Organization: "missing"})
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 5 {
+		t.Errorf("exit code = %d, expected 5 (err: %v)", code, err)
+	}
+	if len(mockConfigSvc.ConfigureCalls) != 0 {
+		t.Errorf("no repository should be configured, got %d calls", len(mockConfigSvc.ConfigureCalls))
+	}
+}
+
+// TestRunOrganizationRequiresClient verifies a missing client is reported as an error.
+func TestRunOrganizationRequiresClient(t *testing.T) {
+	// Arrange
+	application := app.NewApp(&mockOutputWriter{}, enablingConfigService(), &mockRepoParser{})
+
+	// Act
+	err := application.RunOrganization(context.Background(), interfaces.CLIOptions{Organization: "acme"})
+
+	// Assert
+	if err == nil {
+		t.Fatal("RunOrganization() without client should return an error")
+	}
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		t.Errorf("missing client should be a general error, got AppError code %d", appErr.Code)
+	}
+}
@@ -0,0 +1,128 @@
+// Package app provides bounded-concurrency processing of repositories.
+//
+// Batch, organization, user and manifest runs hand their repositories to
+// runConcurrently, which fans them out to a fixed number of workers sharing the
+// App's dependencies. Callers store each result at its input index, so the
+// result table keeps the input order however the workers interleave. Work that
+// reports results runs through runInOrder, which holds each report back until
+// every earlier repository has been reported, so machine-readable output keeps
+// the input order too.
+package app
+
+import (
+	"context"
+	"sync"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// runConcurrently calls work once for each index in [0, count) using at most
+// concurrency goroutines (one if concurrency is less than 2). Once ctx is
+// cancelled, indices that have not started are passed to skip instead.
+// It returns when every call has finished.
+func runConcurrently(ctx context.Context, concurrency, count int, work, skip func(i int)) {
+	if concurrency < 2 {
+		for i := 0; i < count; i++ {
+			runOrSkip(ctx, i, work, skip)
+		}
+		return
+	}
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < count; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				runOrSkip(ctx, i, work, skip)
+			}
+		}()
+	}
+
+	for i := 0; i < count; i++ {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+}
+
+// runInOrder is runConcurrently for work that reports its results. Each index
+// runs on a copy of the App whose reports are released to the result reporter
+// in input order: those of an index as soon as every earlier index has finished.
+func (a *App) runInOrder(ctx context.Context, concurrency, count int, work, skip func(worker *App, i int)) {
+	if a.reporter == nil {
+		runConcurrently(ctx, concurrency, count, func(i int) { work(a, i) }, func(i int) { skip(a, i) })
+		return
+	}
+
+	ordered := &orderedReporter{reporter: a.reporter, pending: make(map[int][]interfaces.RepositoryReport), done: make(map[int]bool)}
+	run := func(i int, fn func(worker *App, i int)) {
+		worker := *a
+		worker.reporter = indexReporter{ordered: ordered, index: i}
+		fn(&worker, i)
+		ordered.finish(i)
+	}
+	runConcurrently(ctx, concurrency, count, func(i int) { run(i, work) }, func(i int) { run(i, skip) })
+}
+
+// orderedReporter releases the reports of concurrently processed indices to
+// reporter in index order.
+type orderedReporter struct {
+	reporter interfaces.IResultReporter
+
+	mu      sync.Mutex
+	next    int
+	pending map[int][]interfaces.RepositoryReport
+	done    map[int]bool
+}
+
+// add holds back a report of index i until finish releases it.
+func (o *orderedReporter) add(i int, report interfaces.RepositoryReport) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.pending[i] = append(o.pending[i], report)
+}
+
+// finish marks index i as finished and releases the reports of every finished
+// index that no unfinished index precedes.
+func (o *orderedReporter) finish(i int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.done[i] = true
+	for o.done[o.next] {
+		for _, report := range o.pending[o.next] {
+			o.reporter.Report(report)
+		}
+		delete(o.pending, o.next)
+		delete(o.done, o.next)
+		o.next++
+	}
+}
+
+// indexReporter is the result reporter of the worker processing one index.
+type indexReporter struct {
+	ordered *orderedReporter
+	index   int
+}
+
+// Report holds the report back until every earlier index has finished.
+func (r indexReporter) Report(report interfaces.RepositoryReport) {
+	r.ordered.add(r.index, report)
+}
+
+// runOrSkip calls work for index i, or skip if ctx has been cancelled.
+func runOrSkip(ctx context.Context, i int, work, skip func(i int)) {
+	if ctx.Err() != nil {
+		skip(i)
+		return
+	}
+	work(i)
+}
+
+// cancelledResult records that a repository was not processed because the run was cancelled.
+func cancelledResult(repository string) RepositoryResult {
+	return RepositoryResult{Repository: repository, Status: statusFailed, Err: apperrors.NewCancelledError()}
+}
@@ -0,0 +1,134 @@
+// Package app_test provides tests for concurrent multi-repository runs.
+//
+// These tests verify that --concurrency processes repositories in parallel,
+// keeps the result table and the machine-readable reports in input order and marks repositories that never
+// started as cancelled when the context is cancelled.
+package app_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/josejulio/ghautodelete/internal/app"
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// TestRunBatchConcurrentKeepsInputOrder verifies repositories run on several
+// workers while the result table stays in input order.
+func TestRunBatchConcurrentKeepsInputOrder(t *testing.T) {
+	// Arrange
+	var running, maxRunning int32
+	mockWriter := &mockOutputWriter{}
+	mockConfigSvc := &mockConfigService{
+		ConfigureFunc: func(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+			now := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if now <= max || atomic.CompareAndSwapInt32(&maxRunning, max, now) {
+					break
+				}
+			}
+			// Later repositories finish first
+			if name == "repo0" {
+				time.Sleep(20 * time.Millisecond)
+			}
+			time.Sleep(5 * time.Millisecond)
+			return newMockConfigResult(false, true, "main", owner+"/"+name), nil
+		},
+	}
+	application := app.NewApp(mockWriter, mockConfigSvc, splitOwnerRepoParser())
+
+	identifiers := make([]string, 8)
+	for i := range identifiers {
+		identifiers[i] = fmt.Sprintf("acme/repo%d", i)
+	}
+
+	// Act
+	err := application.RunBatch(context.Background(), interfaces.CLIOptions{Concurrency: 4}, identifiers)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("RunBatch() error = %v, expected nil", err)
+	}
+	if max := atomic.LoadInt32(&maxRunning); max < 2 || max > 4 {
+		t.Errorf("max concurrent Configure calls = %d, expected between 2 and 4", max)
+	}
+
+	var rows []string
+	for _, line := range mockWriter.InfoCalls {
+		if strings.HasPrefix(line, "acme/") {
+			rows = append(rows, strings.Fields(line)[0])
+		}
+	}
+	if strings.Join(rows, ",") != strings.Join(identifiers, ",") {
+		t.Errorf("result table order = %v, expected %v", rows, identifiers)
+	}
+}
+
+// TestRunBatchConcurrentReportsInInputOrder verifies machine-readable reports
+// follow the input order even when later repositories finish first.
+func TestRunBatchConcurrentReportsInInputOrder(t *testing.T) {
+	// Arrange
+	reporter := &mockResultReporter{}
+	mockConfigSvc := &mockConfigService{
+		ConfigureFunc: func(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+			// Each repository takes longer than the next, so they finish in reverse order
+			var index int
+			_, _ = fmt.Sscanf(name, "repo%d", &index)
+			time.Sleep(time.Duration(8-index) * 5 * time.Millisecond)
+			return newMockConfigResult(false, true, "main", owner+"/"+name), nil
+		},
+	}
+	application := app.NewApp(&mockOutputWriter{}, mockConfigSvc, splitOwnerRepoParser(), app.WithResultReporter(reporter))
+
+	identifiers := make([]string, 8)
+	for i := range identifiers {
+		identifiers[i] = fmt.Sprintf("acme/repo%d", i)
+	}
+
+	// Act
+	err := application.RunBatch(context.Background(), interfaces.CLIOptions{Concurrency: 8}, identifiers)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("RunBatch() error = %v, expected nil", err)
+	}
+	var reported []string
+	for _, report := range reporter.Reports {
+		reported = append(reported, report.Repository)
+	}
+	if strings.Join(reported, ",") != strings.Join(identifiers, ",") {
+		t.Errorf("report order = %v, expected %v", reported, identifiers)
+	}
+}
+
+// TestRunBatchCancelledContextSkipsRepositories verifies a cancelled run
+// reports unprocessed repositories as failed with a cancellation error.
+func TestRunBatchCancelledContextSkipsRepositories(t *testing.T) {
+	// Arrange
+	mockWriter := &mockOutputWriter{}
+	mockConfigSvc := &mockConfigService{}
+	application := app.NewApp(mockWriter, mockConfigSvc, splitOwnerRepoParser())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	err := application.RunBatch(ctx, interfaces.CLIOptions{Concurrency: 2}, []string{"acme/api", "acme/web"})
+
+	// Assert
+	if len(mockConfigSvc.ConfigureCalls) != 0 {
+		t.Errorf("expected no Configure calls after cancellation, got %d", len(mockConfigSvc.ConfigureCalls))
+	}
+	if code := apperrors.GetExitCode(err); code != 1 {
+		t.Errorf("exit code = %d, expected 1 (err: %v)", code, err)
+	}
+	if output := mockWriter.GetAllOutput(); !strings.Contains(output, "acme/web    failed: Cancelled") {
+		t.Errorf("output should mark acme/web as cancelled, got:\n%s", output)
+	}
+}
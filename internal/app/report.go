@@ -0,0 +1,117 @@
+// Package app provides machine-readable result reporting.
+//
+// When a result reporter is supplied via WithResultReporter, every processed
+// repository is reported as an interfaces.RepositoryReport in addition to the
+// text output, so CI tooling does not have to scrape human-readable messages.
+package app
+
+import (
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// Mode and operation values reported in a RepositoryReport.
+const (
+	modeAudit  = "audit"
+	modeCheck  = "check"
+	modeDryRun = "dry-run"
+	modeNormal = "normal"
+
+	operationEnable    = "enable"
+	operationDisable   = "disable"
+	operationReconcile = "reconcile"
+	operationSnapshot  = "snapshot"
+)
+
+// WithResultReporter sets the reporter that receives a machine-readable
+// report for every processed repository.
+func WithResultReporter(reporter interfaces.IResultReporter) Option {
+	return func(a *App) {
+		a.reporter = reporter
+	}
+}
+
+// reportResult reports the outcome of an enable, disable or check operation.
+// configResult may be nil if the operation failed. The result is returned unchanged.
+func (a *App) reportResult(opts interfaces.CLIOptions, result RepositoryResult, configResult interfaces.IConfigResult) RepositoryResult {
+	if a.reporter == nil {
+		return result
+	}
+
+	operation := operationEnable
+	if opts.Disable {
+		operation = operationDisable
+	}
+
+	mode := modeName(opts.CheckOnly, opts.DryRun)
+	if opts.Audit {
+		mode = modeAudit
+	}
+
+	report := newRepositoryReport(result, mode, operation)
+	if configResult != nil {
+		report.DefaultBranch = configResult.GetDefaultBranch()
+		report.PreviousState = stateName(configResult.WasAlreadyEnabled())
+		report.NewState = stateName(configResult.IsNowEnabled())
+	}
+
+	a.reporter.Report(report)
+	return result
+}
+
+// reportReconcile reports the outcome of reconciling a repository with a manifest.
+// The result is returned unchanged.
+func (a *App) reportReconcile(dryRun bool, result RepositoryResult, changes []interfaces.SettingChange) RepositoryResult {
+	if a.reporter == nil {
+		return result
+	}
+
+	report := newRepositoryReport(result, modeName(false, dryRun), operationReconcile)
+	report.Changes = changes
+
+	a.reporter.Report(report)
+	return result
+}
+
+// reportSnapshot reports the outcome of capturing a repository's settings.
+// The result is returned unchanged.
+func (a *App) reportSnapshot(result RepositoryResult) RepositoryResult {
+	if a.reporter != nil {
+		a.reporter.Report(newRepositoryReport(result, modeCheck, operationSnapshot))
+	}
+	return result
+}
+
+// newRepositoryReport creates a report carrying the repository, status and error of result.
+func newRepositoryReport(result RepositoryResult, mode, operation string) interfaces.RepositoryReport {
+	report := interfaces.RepositoryReport{
+		Repository: result.Repository,
+		Mode:       mode,
+		Operation:  operation,
+		Status:     result.Status,
+	}
+	if result.Err != nil {
+		report.Error = result.Err.Error()
+		report.ErrorCode = apperrors.GetExitCode(result.Err)
+	}
+	return report
+}
+
+// modeName returns the operational mode; check mode takes precedence over dry-run mode.
+func modeName(checkOnly, dryRun bool) string {
+	switch {
+	case checkOnly:
+		return modeCheck
+	case dryRun:
+		return modeDryRun
+	}
+	return modeNormal
+}
+
+// stateName describes a delete-branch-on-merge state.
+func stateName(enabled bool) string {
+	if enabled {
+		return statusEnabled
+	}
+	return statusDisabled
+}
@@ -0,0 +1,116 @@
+// Package app_test provides tests for machine-readable result reporting.
+//
+// These tests verify that an App with a result reporter reports every processed
+// repository with its mode, operation, states and error code.
+package app_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/josejulio/ghautodelete/internal/app"
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// mockResultReporter implements IResultReporter for testing.
+type mockResultReporter struct {
+	// Reports captures all reports in order.
+	Reports []interfaces.RepositoryReport
+}
+
+func (m *mockResultReporter) Report(report interfaces.RepositoryReport) {
+	m.Reports = append(m.Reports, report)
+}
+
+// TestRunBatchReportsEveryRepository verifies successes and failures are both reported.
+func TestRunBatchReportsEveryRepository(t *testing.T) {
+	// Arrange
+	reporter := &mockResultReporter{}
+	mockConfigSvc := &mockConfigService{
+		ConfigureFunc: func(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+			if name == "missing" {
+				return nil, apperrors.NewRepositoryNotFoundError(owner, name)
+			}
+			return newMockConfigResult(false, true, "main", owner+"/"+name), nil
+		},
+	}
+	application := app.NewApp(&mockOutputWriter{}, mockConfigSvc, splitOwnerRepoParser(), app.WithResultReporter(reporter))
+
+	// Act
+	_ = application.RunBatch(context.Background(), interfaces.CLIOptions{}, []string{"acme/api", "acme/missing", "invalid"})
+
+	// Assert
+	if len(reporter.Reports) != 3 {
+		t.Fatalf("expected 3 reports, got %d: %+v", len(reporter.Reports), reporter.Reports)
+	}
+
+	expected := interfaces.RepositoryReport{
+		Repository:    "acme/api",
+		DefaultBranch: "main",
+		Mode:          "normal",
+		Operation:     "enable",
+		PreviousState: "disabled",
+		NewState:      "enabled",
+		Status:        "enabled",
+	}
+	if got := reporter.Reports[0]; got.Repository != expected.Repository || got.DefaultBranch != expected.DefaultBranch ||
+		got.Mode != expected.Mode || got.Operation != expected.Operation || got.PreviousState != expected.PreviousState ||
+		got.NewState != expected.NewState || got.Status != expected.Status || got.ErrorCode != 0 {
+		t.Errorf("report 1 = %+v, expected %+v", got, expected)
+	}
+
+	if got := reporter.Reports[1]; got.Status != "failed" || got.ErrorCode != 5 || got.Error == "" {
+		t.Errorf("report 2 = %+v, expected failed with error code 5", got)
+	}
+	if got := reporter.Reports[2]; got.Repository != "invalid" || got.ErrorCode != 2 {
+		t.Errorf("report 3 = %+v, expected unparsed identifier with error code 2", got)
+	}
+}
+
+// TestRunReportsSingleRepository verifies single-repository mode reports the mode and operation.
+func TestRunReportsSingleRepository(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      interfaces.CLIOptions
+		mode      string
+		operation string
+		status    string
+	}{
+		{"check", interfaces.CLIOptions{CheckOnly: true, DryRun: true}, "check", "enable", "enabled"},
+		{"dry-run disable", interfaces.CLIOptions{DryRun: true, Disable: true}, "dry-run", "disable", "would disable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			reporter := &mockResultReporter{}
+			result := newMockConfigResult(true, true, "main", "octocat/hello-world")
+			mockConfigSvc := &mockConfigService{
+				CheckStatusFunc: func(ctx context.Context, owner, name string) (interfaces.IConfigResult, error) {
+					return result, nil
+				},
+				DisableFunc: func(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+					return result, nil
+				},
+			}
+			application := app.NewApp(&mockOutputWriter{}, mockConfigSvc, splitOwnerRepoParser(), app.WithResultReporter(reporter))
+			tt.opts.Repository = "octocat/hello-world"
+
+			// Act
+			err := application.Run(context.Background(), tt.opts)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Run() error = %v, expected nil", err)
+			}
+			if len(reporter.Reports) != 1 {
+				t.Fatalf("expected 1 report, got %d", len(reporter.Reports))
+			}
+			got := reporter.Reports[0]
+			if got.Mode != tt.mode || got.Operation != tt.operation || got.Status != tt.status {
+				t.Errorf("report = %+v, expected mode %q, operation %q, status %q", got, tt.mode, tt.operation, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Package app provides search-based repository selection.
+//
+// Search mode lists the repositories matching a GitHub search query, applies the
+// same selection filters as organization mode and runs the rest through the
+// batch workflow. GitHub returns at most 1,000 results per query; a search that
+// matches more is warned about rather than silently truncated.
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// RunSearch processes every repository matching the search query opts.Search.
+// The mode is selected by opts exactly as in Run; opts.Repository is ignored.
+//
+// Requires a GitHub client supplied via WithGitHubClient.
+// Returns an error if the token is invalid or the search fails, otherwise the aggregate batch result.
+func (a *App) RunSearch(ctx context.Context, opts interfaces.CLIOptions) error {
+	refs, err := a.searchRefs(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	return a.runRepositories(ctx, opts, refs)
+}
+
+// searchRefs validates the token, lists the repositories matching opts.Search
+// and applies the filters from opts.
+func (a *App) searchRefs(ctx context.Context, opts interfaces.CLIOptions) ([]repositoryRef, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("search mode requires a GitHub client")
+	}
+
+	filter, err := newRepositoryFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := a.validateToken(ctx, opts); err != nil {
+		return nil, err
+	}
+
+	a.writer.Verbose(fmt.Sprintf("Searching repositories: %s", opts.Search))
+	repos, total, err := a.client.SearchRepositories(ctx, opts.Search)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search repositories: %w", err)
+	}
+	if total > len(repos) {
+		a.writer.Info(fmt.Sprintf("Warning: the search matched %d repositories, but GitHub only returns the first %d. "+
+			"Narrow the query (e.g., with created: or pushed: date ranges) and run again for the rest", total, len(repos)))
+	}
+
+	refs := a.selectRepositories(repos, filter)
+	a.writer.Info(fmt.Sprintf("Found %d repositories matching %q (%d skipped)",
+		len(repos), opts.Search, len(repos)-len(refs)))
+	return refs, nil
+}
@@ -0,0 +1,70 @@
+// Package app_test provides tests for search mode in the App.
+//
+// These tests verify that App.RunSearch processes the repositories matching the
+// query through the batch workflow, applies the selection filters and warns when
+// GitHub's result cap hides some matches.
+package app_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/josejulio/ghautodelete/internal/app"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// TestRunSearchProcessesMatchingRepositories verifies the query, the filters and the cap warning.
+func TestRunSearchProcessesMatchingRepositories(t *testing.T) {
+	tests := []struct {
+		name        string
+		total       int
+		expectWarn  bool
+		expectedOut string
+	}{
+		{name: "complete", total: 4, expectedOut: `Found 4 repositories matching "org:acme topic:service" (2 skipped)`},
+		{name: "capped", total: 2400, expectWarn: true, expectedOut: "the search matched 2400 repositories, but GitHub only returns the first 4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockWriter := &mockOutputWriter{}
+			mockConfigSvc := enablingConfigService()
+			var query string
+			mockClient := &mockGitHubClient{
+				SearchRepositoriesFunc: func(ctx context.Context, q string) ([]interfaces.IRepository, int, error) {
+					query = q
+					return catalogRepositories(), tt.total, nil
+				},
+			}
+			application := app.NewApp(mockWriter, mockConfigSvc, &mockRepoParser{}, app.WithGitHubClient(mockClient))
+			opts := interfaces.CLIOptions{Search: "org:acme topic:service", Visibility: "public"}
+
+			// Act
+			err := application.RunSearch(context.Background(), opts)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("RunSearch() error = %v, expected nil", err)
+			}
+			if query != opts.Search {
+				t.Errorf("SearchRepositories query = %q, expected %q", query, opts.Search)
+			}
+			var names []string
+			for _, call := range mockConfigSvc.ConfigureCalls {
+				names = append(names, call.Name)
+			}
+			if strings.Join(names, ",") != "web,docs" {
+				t.Errorf("configured %v, expected the public repositories web and docs", names)
+			}
+			output := mockWriter.GetAllOutput()
+			if !strings.Contains(output, tt.expectedOut) {
+				t.Errorf("output should contain %q, got:\n%s", tt.expectedOut, output)
+			}
+			if strings.Contains(output, "Warning:") != tt.expectWarn {
+				t.Errorf("warning expected = %v, got:\n%s", tt.expectWarn, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Package app provides capturing repository settings into a snapshot.
+//
+// Snapshot runs select repositories exactly as check mode does (a list, an
+// organization, the authenticated user's repositories or a search), read each
+// repository's current settings without changing anything, and collect them
+// into a snapshot that can later be restored with RunManifest.
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/josejulio/ghautodelete/internal/manifest"
+	"github.com/josejulio/ghautodelete/internal/snapshot"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// statusCaptured is reported in the result table for a captured repository.
+const statusCaptured = "captured"
+
+// CaptureSnapshot reads the current settings of the selected repositories.
+// Repositories are selected by opts.Organization, opts.User, opts.Search or
+// identifiers, as for RunOrganization, RunUser, RunSearch and RunBatch; the
+// token only needs read access.
+//
+// Requires a GitHub client supplied via WithGitHubClient.
+// Returns the snapshot of every repository that could be read, stamped with
+// opts.Hostname, and the aggregate batch error for those that could not.
+func (a *App) CaptureSnapshot(ctx context.Context, opts interfaces.CLIOptions, identifiers []string) (*snapshot.Snapshot, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("snapshot requires a GitHub client")
+	}
+	opts.CheckOnly = true
+
+	var refs []repositoryRef
+	var err error
+	switch {
+	case opts.Organization != "":
+		refs, err = a.organizationRefs(ctx, opts)
+	case opts.User:
+		refs, err = a.userRefs(ctx, opts)
+	case opts.Search != "":
+		refs, err = a.searchRefs(ctx, opts)
+	default:
+		refs = a.parseRefs(identifiers)
+		_, err = a.validateToken(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a.prefetch(ctx, refs)
+
+	captured := make([]*manifest.Repository, len(refs))
+	results := make([]RepositoryResult, len(refs))
+	a.runInOrder(ctx, opts.Concurrency, len(refs), func(worker *App, i int) {
+		results[i], captured[i] = worker.captureRepository(ctx, refs[i])
+	}, func(worker *App, i int) {
+		results[i] = worker.reportSnapshot(cancelledResult(refs[i].displayName()))
+	})
+
+	a.writeResultTable(results)
+
+	snap := snapshot.New(opts.Hostname, time.Now())
+	for _, repo := range captured {
+		if repo != nil {
+			snap.Repositories = append(snap.Repositories, *repo)
+		}
+	}
+	return snap, batchError(results)
+}
+
+// captureRepository reads a single repository's settings.
+// Errors are captured in the returned result rather than returned.
+func (a *App) captureRepository(ctx context.Context, ref repositoryRef) (RepositoryResult, *manifest.Repository) {
+	if ref.err != nil {
+		return a.reportSnapshot(failedResult(ref.identifier, ref.err)), nil
+	}
+
+	fullName := ref.displayName()
+	a.writer.Verbose(fmt.Sprintf("Capturing %s", fullName))
+
+	repo, err := a.client.GetRepository(ctx, ref.owner, ref.name)
+	if err != nil {
+		return a.reportSnapshot(failedResult(fullName, err)), nil
+	}
+
+	entry := snapshot.Capture(repo)
+	return a.reportSnapshot(RepositoryResult{Repository: entry.Name, Status: statusCaptured}), &entry
+}
@@ -0,0 +1,60 @@
+// Package app_test provides tests for capturing settings snapshots in the App.
+//
+// These tests verify that App.CaptureSnapshot reads every selected repository
+// without changing anything and keeps the repositories that could be read
+// when others fail.
+package app_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/josejulio/ghautodelete/internal/app"
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/github"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// TestCaptureSnapshotKeepsReadableRepositories verifies captured settings and
+// the batch error for a repository that could not be read.
+func TestCaptureSnapshotKeepsReadableRepositories(t *testing.T) {
+	// Arrange
+	mockWriter := &mockOutputWriter{}
+	mockConfigSvc := &mockConfigService{}
+	mockClient := &mockGitHubClient{
+		GetRepositoryFunc: func(ctx context.Context, owner, name string) (interfaces.IRepository, error) {
+			if name == "missing" {
+				return nil, apperrors.NewRepositoryNotFoundError(owner, name)
+			}
+			repo := github.NewRepository(owner, name, "main", name == "api")
+			repo.AllowRebaseMerge = github.Bool(false)
+			return repo, nil
+		},
+	}
+	application := app.NewApp(mockWriter, mockConfigSvc, splitOwnerRepoParser(), app.WithGitHubClient(mockClient))
+	opts := interfaces.CLIOptions{Hostname: "github.example.com"}
+
+	// Act
+	snap, err := application.CaptureSnapshot(context.Background(), opts, []string{"acme/api", "acme/missing", "acme/web"})
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 5 {
+		t.Errorf("exit code = %d, expected 5 for the missing repository (err: %v)", code, err)
+	}
+	if len(mockClient.UpdateRepositoryCalls) != 0 || len(mockConfigSvc.ConfigureCalls) != 0 {
+		t.Error("CaptureSnapshot should not change any repository")
+	}
+	if snap == nil || snap.Hostname != "github.example.com" {
+		t.Fatalf("snapshot = %+v, expected one stamped with the hostname", snap)
+	}
+	if len(snap.Repositories) != 2 || snap.Repositories[0].Name != "acme/api" || snap.Repositories[1].Name != "acme/web" {
+		t.Fatalf("Repositories = %+v, expected acme/api and acme/web in input order", snap.Repositories)
+	}
+	api := snap.Repositories[0].Settings
+	if api.DeleteBranchOnMerge == nil || !*api.DeleteBranchOnMerge {
+		t.Error("acme/api should be captured with delete_branch_on_merge=true")
+	}
+	if api.AllowRebaseMerge == nil || *api.AllowRebaseMerge {
+		t.Error("acme/api should be captured with allow_rebase_merge=false")
+	}
+}
@@ -0,0 +1,66 @@
+// Package app provides processing of the authenticated user's repositories.
+//
+// User mode resolves the login behind the token, lists the repositories matching
+// the requested affiliation, applies the same filters as organization mode and
+// runs the rest through the batch workflow.
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// defaultAffiliation selects only repositories owned by the authenticated user.
+const defaultAffiliation = "owner"
+
+// RunUser processes every repository of the authenticated user matching opts.Affiliation.
+// An empty affiliation selects the repositories the user owns.
+// The mode is selected by opts exactly as in Run; opts.Repository is ignored.
+//
+// Requires a GitHub client supplied via WithGitHubClient.
+// Returns an error if the token is invalid or listing fails, otherwise the aggregate batch result.
+func (a *App) RunUser(ctx context.Context, opts interfaces.CLIOptions) error {
+	refs, err := a.userRefs(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	return a.runRepositories(ctx, opts, refs)
+}
+
+// userRefs validates the token, lists the authenticated user's repositories
+// matching opts.Affiliation and applies the filters from opts.
+func (a *App) userRefs(ctx context.Context, opts interfaces.CLIOptions) ([]repositoryRef, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("user mode requires a GitHub client")
+	}
+
+	filter, err := newRepositoryFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenInfo, err := a.validateToken(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	login := tokenInfo.GetUsername()
+
+	affiliation := opts.Affiliation
+	if affiliation == "" {
+		affiliation = defaultAffiliation
+	}
+
+	a.writer.Verbose(fmt.Sprintf("Listing repositories for %s (affiliation: %s)", login, affiliation))
+	repos, err := a.client.ListUserRepositories(ctx, affiliation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	refs := a.selectRepositories(repos, filter)
+	a.writer.Info(fmt.Sprintf("Found %d repositories for %s (%d skipped)",
+		len(repos), login, len(repos)-len(refs)))
+	return refs, nil
+}
@@ -0,0 +1,147 @@
+// Package app_test provides tests for user mode in the App.
+//
+// These tests verify that App.RunUser resolves the authenticated login, lists
+// the user's repositories with the requested affiliation, applies the filters
+// and runs the remaining repositories through the batch workflow.
+package app_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/josejulio/ghautodelete/internal/app"
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/token"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+// octocatClient returns a client that lists the given repositories and records
+// the requested affiliation.
+func octocatClient(repos []interfaces.IRepository, affiliation *string) *mockGitHubClient {
+	return &mockGitHubClient{
+		ListUserRepositoriesFunc: func(ctx context.Context, aff string) ([]interfaces.IRepository, error) {
+			*affiliation = aff
+			return repos, nil
+		},
+	}
+}
+
+// octocatConfigService returns an enabling config service whose token belongs to "octocat".
+func octocatConfigService() *mockConfigService {
+	configSvc := enablingConfigService()
+	configSvc.ValidateTokenFunc = func(ctx context.Context, write bool) (interfaces.ITokenInfo, error) {
+		return token.NewTokenInfo("octocat", []string{"repo"}), nil
+	}
+	return configSvc
+}
+
+// =============================================================================
+// User Mode Tests
+// =============================================================================
+
+// TestRunUserConfiguresOwnedRepositories verifies the default affiliation and the configure pipeline.
+func TestRunUserConfiguresOwnedRepositories(t *testing.T) {
+	// Arrange
+	var affiliation string
+	mockWriter := &mockOutputWriter{}
+	mockConfigSvc := octocatConfigService()
+	repos := []interfaces.IRepository{
+		&mockListedRepository{owner: "octocat", name: "dotfiles"},
+		&mockListedRepository{owner: "octocat", name: "blog"},
+		&mockListedRepository{owner: "octocat", name: "old-site", archived: true},
+	}
+	application := app.NewApp(mockWriter, mockConfigSvc, &mockRepoParser{},
+		app.WithGitHubClient(octocatClient(repos, &affiliation)))
+
+	// Act
+	err := application.RunUser(context.Background(), interfaces.CLIOptions{User: true, SkipArchived: true, Verbose: true})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("RunUser() error = %v, expected nil", err)
+	}
+	if affiliation != "owner" {
+		t.Errorf("affiliation = %q, expected %q", affiliation, "owner")
+	}
+	if len(mockConfigSvc.ConfigureCalls) != 2 {
+		t.Errorf("expected 2 Configure calls, got %d", len(mockConfigSvc.ConfigureCalls))
+	}
+	output := mockWriter.GetAllOutput()
+	for _, want := range []string{
+		"Skipping octocat/old-site: archived",
+		"Found 3 repositories for octocat (1 skipped)",
+		"2 repositories processed: 2 succeeded, 0 failed",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+// TestRunUserPassesAffiliation verifies an explicit affiliation is forwarded unchanged.
+func TestRunUserPassesAffiliation(t *testing.T) {
+	// Arrange
+	var affiliation string
+	repos := []interfaces.IRepository{&mockListedRepository{owner: "acme", name: "api"}}
+	application := app.NewApp(&mockOutputWriter{}, octocatConfigService(), &mockRepoParser{},
+		app.WithGitHubClient(octocatClient(repos, &affiliation)))
+	opts := interfaces.CLIOptions{User: true, Affiliation: "owner,collaborator,organization_member"}
+
+	// Act
+	err := application.RunUser(context.Background(), opts)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("RunUser() error = %v, expected nil", err)
+	}
+	if affiliation != opts.Affiliation {
+		t.Errorf("affiliation = %q, expected %q", affiliation, opts.Affiliation)
+	}
+}
+
+// TestRunUserInvalidToken verifies token validation failures stop before listing.
+func TestRunUserInvalidToken(t *testing.T) {
+	// Arrange
+	listed := false
+	mockClient := &mockGitHubClient{
+		ListUserRepositoriesFunc: func(ctx context.Context, affiliation string) ([]interfaces.IRepository, error) {
+			listed = true
+			return nil, nil
+		},
+	}
+	mockConfigSvc := enablingConfigService()
+	mockConfigSvc.ValidateTokenFunc = func(ctx context.Context, write bool) (interfaces.ITokenInfo, error) {
+		return nil, apperrors.NewAuthenticationError("Invalid token", nil)
+	}
+	application := app.NewApp(&mockOutputWriter{}, mockConfigSvc, &mockRepoParser{}, app.WithGitHubClient(mockClient))
+
+	// Act
+	err := application.RunUser(context.Background(), interfaces.CLIOptions{User: true})
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 3 {
+		t.Errorf("exit code = %d, expected 3 (err: %v)", code, err)
+	}
+	if listed {
+		t.Error("repositories should not be listed when the token is invalid")
+	}
+}
+
+// TestRunUserRequiresClient verifies a missing client is reported as an error.
+func TestRunUserRequiresClient(t *testing.T) {
+	// Arrange
+	application := app.NewApp(&mockOutputWriter{}, enablingConfigService(), &mockRepoParser{})
+
+	// Act
+	err := application.RunUser(context.Background(), interfaces.CLIOptions{User: true})
+
+	// Assert
+	if err == nil {
+		t.Fatal("RunUser() without client should return an error")
+	}
+}
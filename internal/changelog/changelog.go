@@ -0,0 +1,165 @@
+// Package changelog provides a persistent audit log of repository setting changes.
+//
+// FileLog implements the IChangeLog interface by appending one JSON object per
+// line (JSON Lines) to a local file, so every change ghautodelete makes can be
+// traced to a time, an acting login and a run. Parse reads the file back for
+// the history command, optionally filtered by repository and date range.
+package changelog
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// DefaultFile is the audit log file name inside the user configuration directory.
+const DefaultFile = "audit-log.jsonl"
+
+// FileLog appends change records to a JSON Lines file.
+// It is safe for concurrent use; each record is written whole.
+type FileLog struct {
+	path    string
+	version string
+	runID   string
+	now     func() time.Time
+
+	mu sync.Mutex
+}
+
+// Option configures optional FileLog behavior.
+type Option func(*FileLog)
+
+// WithClock sets the clock used to timestamp records (time.Now by default).
+func WithClock(now func() time.Time) Option {
+	return func(l *FileLog) {
+		l.now = now
+	}
+}
+
+// NewFileLog creates a new FileLog.
+//
+// Parameters:
+//   - path: the audit log file; it and its directory are created on the first record
+//   - version: the ghautodelete version stamped on every record
+//   - runID: the run ID stamped on every record (see NewRunID)
+//   - opts: optional behavior, e.g. WithClock
+//
+// Returns a FileLog that implements the IChangeLog interface.
+func NewFileLog(path, version, runID string, opts ...Option) *FileLog {
+	l := &FileLog{
+		path:    path,
+		version: version,
+		runID:   runID,
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Record appends change to the file, stamped with the time, version and run ID.
+func (l *FileLog) Record(change interfaces.ChangeRecord) error {
+	change.Timestamp = l.now().UTC()
+	change.Version = l.version
+	change.RunID = l.runID
+
+	line, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// NewRunID returns a random identifier for one ghautodelete run.
+func NewRunID() string {
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(id[:])
+}
+
+// Filter selects change records. Zero fields match everything.
+type Filter struct {
+	// Repository matches the full repository name, case-insensitively.
+	Repository string
+
+	// Since and Until bound the record timestamps, inclusive.
+	Since time.Time
+	Until time.Time
+}
+
+// Matches reports whether record is selected by the filter.
+func (f Filter) Matches(record interfaces.ChangeRecord) bool {
+	if f.Repository != "" && !strings.EqualFold(f.Repository, record.Repository) {
+		return false
+	}
+	if !f.Since.IsZero() && record.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && record.Timestamp.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Parse reads the records of an audit log file that match filter, in file order.
+//
+// Blank lines are ignored. A line that is not a record, such as one cut short
+// by an interrupted write, does not hide the records after it: it is skipped and
+// its number is returned in skipped, so the caller can report it.
+func Parse(content []byte, filter Filter) (records []interfaces.ChangeRecord, skipped []int, err error) {
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var record interfaces.ChangeRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			skipped = append(skipped, lineNumber)
+			continue
+		}
+		if filter.Matches(record) {
+			records = append(records, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, apperrors.NewValidationError(fmt.Sprintf("Cannot read audit log: %v", err))
+	}
+
+	return records, skipped, nil
+}
+
+// Ensure FileLog implements IChangeLog interface
+var _ interfaces.IChangeLog = (*FileLog)(nil)
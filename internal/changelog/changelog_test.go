@@ -0,0 +1,81 @@
+// Package changelog_test provides tests for the audit log.
+//
+// These tests verify that FileLog appends stamped JSON Lines records and that
+// Parse reads them back filtered by repository and date range, skipping lines
+// that are not records.
+package changelog_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/josejulio/ghautodelete/internal/changelog"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// TestFileLogAppendsStampedRecords verifies records are appended one per line
+// and stamped with the time, version and run ID, and can be read back filtered.
+func TestFileLogAppendsStampedRecords(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "logs", changelog.DefaultFile)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	log := changelog.NewFileLog(path, "1.2.3", "run1", changelog.WithClock(func() time.Time { return now }))
+
+	// Act
+	err1 := log.Record(interfaces.ChangeRecord{Actor: "octocat", Repository: "acme/api",
+		Setting: "delete_branch_on_merge", Before: "false", After: "true"})
+	now = now.Add(48 * time.Hour)
+	err2 := log.Record(interfaces.ChangeRecord{Actor: "octocat", Repository: "acme/web",
+		Setting: "delete_branch_on_merge", Before: "false", After: "true"})
+
+	// Assert
+	if err1 != nil || err2 != nil {
+		t.Fatalf("Record() errors = %v, %v", err1, err2)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading audit log: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(content)), "\n"); len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", content)
+	}
+
+	all, _, _ := changelog.Parse(content, changelog.Filter{})
+	if len(all) != 2 || all[0].Version != "1.2.3" || all[0].RunID != "run1" || !all[0].Timestamp.Equal(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("records = %+v, expected stamped records", all)
+	}
+	byRepo, _, _ := changelog.Parse(content, changelog.Filter{Repository: "ACME/web"})
+	if len(byRepo) != 1 || byRepo[0].Repository != "acme/web" {
+		t.Errorf("repository filter = %+v, expected acme/web only", byRepo)
+	}
+	until := time.Date(2024, 3, 1, 23, 59, 59, 0, time.UTC)
+	byDate, _, _ := changelog.Parse(content, changelog.Filter{Until: until})
+	if len(byDate) != 1 || byDate[0].Repository != "acme/api" {
+		t.Errorf("date filter = %+v, expected acme/api only", byDate)
+	}
+}
+
+// TestParseSkipsInvalidLines verifies malformed and truncated records are
+// skipped and reported without hiding the records after them.
+func TestParseSkipsInvalidLines(t *testing.T) {
+	// Arrange
+	content := []byte("{\"repository\":\"acme/api\"}\nnot json\n{\"repository\":\"acme/web\"}\n{\"repository\":\"acme/do")
+
+	// Act
+	records, skipped, err := changelog.Parse(content, changelog.Filter{})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(records) != 2 || records[0].Repository != "acme/api" || records[1].Repository != "acme/web" {
+		t.Errorf("records = %+v, expected acme/api and acme/web", records)
+	}
+	if !reflect.DeepEqual(skipped, []int{2, 4}) {
+		t.Errorf("skipped = %v, expected lines 2 and 4", skipped)
+	}
+}
@@ -0,0 +1,317 @@
+// Package checkpoint provides resumable progress for multi-repository runs.
+//
+// File implements the ICheckpoint interface with a JSON Lines file: a header
+// line identifying the format version and the run, followed by one line per
+// completed repository:
+//
+//	{"version":1,"operation":"enable (normal)","selection":"organization acme","hostname":"github.com","created_at":"2024-03-01T12:00:00Z"}
+//	{"timestamp":"2024-03-01T12:00:03Z","repository":"acme/api","status":"enabled"}
+//	{"timestamp":"2024-03-01T12:00:04Z","repository":"acme/web","status":"failed","error":"..."}
+//
+// Outcomes are appended as repositories complete, so a run that dies keeps
+// everything recorded up to that point. Nothing is written before the first
+// outcome, so a run that fails validation or authentication leaves the previous
+// checkpoint at the path untouched. A line cut short by the process dying
+// mid-write is ignored when the file is read back, and the file is only ever
+// replaced as a whole through a temporary file and a rename, so a reader never
+// sees a half-written header.
+package checkpoint
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// CurrentVersion is the checkpoint file format version written by this release.
+const CurrentVersion = 1
+
+// DefaultFileName returns the checkpoint file name, inside the user cache
+// directory, for a run of operation over selection on hostname. Runs over
+// different selections get different files, so one does not replace the
+// checkpoint of another.
+func DefaultFileName(operation, selection, hostname string) string {
+	digest := sha256.Sum256([]byte(operation + "\n" + selection + "\n" + strings.ToLower(hostname)))
+	return fmt.Sprintf("checkpoint-%x.jsonl", digest[:8])
+}
+
+// Header is the first line of a checkpoint file.
+type Header struct {
+	// Version is the file format version (see CurrentVersion).
+	Version int `json:"version"`
+
+	// Operation describes the mode of the run (e.g., "enable (normal)", "disable (dry-run)").
+	// A checkpoint can only be resumed by a run of the same operation.
+	Operation string `json:"operation"`
+
+	// Selection describes the repositories of the run (e.g., "organization acme").
+	// A checkpoint can only be resumed by a run over the same selection.
+	Selection string `json:"selection"`
+
+	// Hostname is the GitHub host the repositories are on.
+	Hostname string `json:"hostname"`
+
+	// CreatedAt is when the run recorded in the checkpoint started.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// File records repository outcomes in a checkpoint file.
+// It is safe for concurrent use; each outcome is written whole.
+type File struct {
+	path     string
+	now      func() time.Time
+	statuses map[string]string
+
+	mu   sync.Mutex
+	file *os.File
+
+	// header and records are written by the first Record; closed is set by Close
+	header  Header
+	records []interfaces.CheckpointRecord
+	closed  bool
+}
+
+// Option configures optional File behavior.
+type Option func(*File)
+
+// WithClock sets the clock used to timestamp the header and outcomes (time.Now by default).
+func WithClock(now func() time.Time) Option {
+	return func(f *File) {
+		f.now = now
+	}
+}
+
+// Create starts a new checkpoint at path for a run of operation over selection
+// on hostname. Any previous checkpoint there is replaced when the first outcome
+// is recorded; the directory is created then if needed.
+func Create(path, operation, selection, hostname string, opts ...Option) (*File, error) {
+	f := newFile(path, opts)
+	f.header = Header{
+		Version:   CurrentVersion,
+		Operation: operation,
+		Selection: selection,
+		Hostname:  hostname,
+		CreatedAt: f.now().UTC(),
+	}
+	return f, nil
+}
+
+// Resume continues the checkpoint at path. It must have been written by a run of
+// the same operation over the same selection on the same host. The status of each repository that
+// completed without an error is reported by Status. When the first new outcome is recorded, the file is
+// compacted to the latest outcome of each repository and new outcomes are appended to it.
+func Resume(path, operation, selection, hostname string, opts ...Option) (*File, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("No checkpoint to resume at %s", path))
+	}
+	if err != nil {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("Cannot read checkpoint %s: %v", path, err))
+	}
+
+	header, records, err := Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case header.Operation != operation:
+		return nil, apperrors.NewValidationError(fmt.Sprintf(
+			"Checkpoint %s records the operation %q and cannot be resumed for %q", path, header.Operation, operation))
+	case header.Selection != selection:
+		return nil, apperrors.NewValidationError(fmt.Sprintf(
+			"Checkpoint %s records a run over %q and cannot be resumed for %q", path, header.Selection, selection))
+	case !strings.EqualFold(header.Hostname, hostname):
+		return nil, apperrors.NewValidationError(fmt.Sprintf(
+			"Checkpoint %s was recorded on %s, not %s", path, header.Hostname, hostname))
+	}
+
+	f := newFile(path, opts)
+	f.header = header
+	f.records = latestOutcomes(records)
+	for _, record := range f.records {
+		if record.Error == "" {
+			f.statuses[strings.ToLower(record.Repository)] = record.Status
+		}
+	}
+	return f, nil
+}
+
+// newFile creates a File with the given options applied.
+func newFile(path string, opts []Option) *File {
+	f := &File{
+		path:     path,
+		now:      time.Now,
+		statuses: make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// open writes the header and the records carried over from a resumed run to a
+// temporary file, renames it over the checkpoint and keeps it open for appending.
+func (f *File) open() error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	if err := encoder.Encode(f.header); err != nil {
+		return err
+	}
+	for _, record := range f.records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+
+	dir := filepath.Dir(f.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return apperrors.NewValidationError(fmt.Sprintf("Cannot write checkpoint %s: %v", f.path, err))
+	}
+	tmp, err := os.CreateTemp(dir, ".checkpoint-*")
+	if err != nil {
+		return apperrors.NewValidationError(fmt.Sprintf("Cannot write checkpoint %s: %v", f.path, err))
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return apperrors.NewValidationError(fmt.Sprintf("Cannot write checkpoint %s: %v", f.path, err))
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return apperrors.NewValidationError(fmt.Sprintf("Cannot write checkpoint %s: %v", f.path, err))
+	}
+
+	// The renamed file is still open; appending to it appends to the checkpoint
+	f.file = tmp
+	f.records = nil
+	return nil
+}
+
+// Status returns the status the repository completed with in the run being
+// resumed, or an empty string if it failed or was not recorded.
+// Always empty for a checkpoint started with Create.
+func (f *File) Status(repository string) string {
+	return f.statuses[strings.ToLower(repository)]
+}
+
+// Record appends the outcome of one repository, stamped with the time.
+func (f *File) Record(outcome interfaces.CheckpointRecord) error {
+	outcome.Timestamp = f.now().UTC()
+
+	line, err := json.Marshal(outcome)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return fmt.Errorf("checkpoint %s is closed", f.path)
+	}
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return err
+		}
+	}
+	_, err = f.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the checkpoint file. Outcomes recorded afterwards are errors.
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// Parse reads the header and the outcomes of a checkpoint file, in file order.
+//
+// A last line without its newline was cut short by an interrupted write and is
+// ignored. Any other line that cannot be read, a missing header and a newer
+// format version are reported as validation errors.
+func Parse(content []byte) (Header, []interfaces.CheckpointRecord, error) {
+	content = content[:bytes.LastIndexByte(content, '\n')+1]
+
+	var header Header
+	var records []interfaces.CheckpointRecord
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		if header.Version == 0 {
+			if err := json.Unmarshal(line, &header); err != nil {
+				return Header{}, nil, apperrors.NewValidationError(fmt.Sprintf("Invalid checkpoint header on line %d: %v", lineNumber, err))
+			}
+			if header.Version == 0 {
+				return Header{}, nil, apperrors.NewValidationError("Invalid checkpoint: no version")
+			}
+			continue
+		}
+
+		var record interfaces.CheckpointRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return Header{}, nil, apperrors.NewValidationError(fmt.Sprintf("Invalid checkpoint record on line %d: %v", lineNumber, err))
+		}
+		if record.Repository == "" {
+			return Header{}, nil, apperrors.NewValidationError(fmt.Sprintf("Invalid checkpoint record on line %d: no repository", lineNumber))
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return Header{}, nil, apperrors.NewValidationError(fmt.Sprintf("Cannot read checkpoint: %v", err))
+	}
+
+	switch {
+	case header.Version == 0:
+		return Header{}, nil, apperrors.NewValidationError("Invalid checkpoint: no header")
+	case header.Version > CurrentVersion:
+		return Header{}, nil, apperrors.NewValidationError(fmt.Sprintf(
+			"Checkpoint version %d is not supported by this release (up to %d). Upgrade ghautodelete", header.Version, CurrentVersion))
+	}
+
+	return header, records, nil
+}
+
+// latestOutcomes keeps the last outcome recorded for each repository, in the
+// order the repositories were first recorded.
+func latestOutcomes(records []interfaces.CheckpointRecord) []interfaces.CheckpointRecord {
+	index := make(map[string]int, len(records))
+	var latest []interfaces.CheckpointRecord
+	for _, record := range records {
+		key := strings.ToLower(record.Repository)
+		if i, ok := index[key]; ok {
+			latest[i] = record
+			continue
+		}
+		index[key] = len(latest)
+		latest = append(latest, record)
+	}
+	return latest
+}
+
+// Ensure File implements ICheckpoint interface
+var _ interfaces.ICheckpoint = (*File)(nil)
@@ -0,0 +1,208 @@
+// Package checkpoint_test provides tests for resumable run checkpoints.
+//
+// These tests verify that File records outcomes as they complete, that Resume
+// reports the status of the repositories that completed and compacts the file,
+// that a line cut short by an interrupted write is ignored, that a previous
+// checkpoint is only replaced once an outcome is recorded, and that checkpoints
+// of another operation, selection, host or newer format are rejected with
+// validation errors.
+package checkpoint_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/josejulio/ghautodelete/internal/checkpoint"
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// TestResumeReportsCompletedRepositories verifies a resumed checkpoint reports
+// the latest outcome of each repository, survives a torn last line and is
+// compacted before new outcomes are appended.
+func TestResumeReportsCompletedRepositories(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "state", "checkpoint.jsonl")
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := checkpoint.WithClock(func() time.Time { return now })
+
+	first, err := checkpoint.Create(path, "enable (normal)", "organization acme", "github.com", clock)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	_ = first.Record(interfaces.CheckpointRecord{Repository: "acme/api", Status: "enabled"})
+	_ = first.Record(interfaces.CheckpointRecord{Repository: "acme/web", Status: "failed", Error: "rate limited"})
+	_ = first.Record(interfaces.CheckpointRecord{Repository: "acme/web", Status: "enabled"})
+	_ = first.Record(interfaces.CheckpointRecord{Repository: "acme/docs", Status: "failed", Error: "not found"})
+	_ = first.Close()
+
+	// The process died while writing the next outcome
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	_, _ = file.WriteString(`{"timestamp":"2024-03-01T12:00:00Z","repository":"acme/ca`)
+	_ = file.Close()
+
+	// Act
+	resumed, err := checkpoint.Resume(path, "enable (normal)", "organization acme", "GitHub.com", clock)
+	if err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	recordErr := resumed.Record(interfaces.CheckpointRecord{Repository: "acme/docs", Status: "enabled"})
+	_ = resumed.Close()
+
+	// Assert
+	if recordErr != nil {
+		t.Fatalf("Record() error = %v", recordErr)
+	}
+	if resumed.Status("ACME/api") != "enabled" || resumed.Status("acme/web") != "enabled" {
+		t.Error("acme/api and acme/web were enabled and should report it")
+	}
+	if resumed.Status("acme/docs") != "" || resumed.Status("acme/cache") != "" {
+		t.Error("acme/docs failed and acme/cache was cut short; neither should report a status")
+	}
+
+	content, _ := os.ReadFile(path)
+	header, records, err := checkpoint.Parse(content)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if header.Version != checkpoint.CurrentVersion || header.Operation != "enable (normal)" || header.Selection != "organization acme" || !header.CreatedAt.Equal(now) {
+		t.Errorf("header = %+v, expected the original run", header)
+	}
+	var names []string
+	for _, record := range records {
+		names = append(names, record.Repository+"="+record.Status)
+	}
+	if got := strings.Join(names, ","); got != "acme/api=enabled,acme/web=enabled,acme/docs=failed,acme/docs=enabled" {
+		t.Errorf("records = %s, expected the compacted outcomes followed by the new one", got)
+	}
+}
+
+// TestResumeRejectsMismatchedCheckpoints verifies checkpoints that cannot be
+// resumed are validation errors.
+func TestResumeRejectsMismatchedCheckpoints(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		operation string
+		want      string
+	}{
+		{
+			name:      "missing file",
+			operation: "enable (normal)",
+			want:      "No checkpoint to resume",
+		},
+		{
+			name:      "other operation",
+			content:   `{"version":1,"operation":"enable (dry-run)","selection":"organization acme","hostname":"github.com"}` + "\n",
+			operation: "enable (normal)",
+			want:      `records the operation "enable (dry-run)"`,
+		},
+		{
+			name:      "other selection",
+			content:   `{"version":1,"operation":"enable (normal)","selection":"search topic:go","hostname":"github.com"}` + "\n",
+			operation: "enable (normal)",
+			want:      `records a run over "search topic:go"`,
+		},
+		{
+			name:      "other host",
+			content:   `{"version":1,"operation":"enable (normal)","selection":"organization acme","hostname":"git.corp.example"}` + "\n",
+			operation: "enable (normal)",
+			want:      "was recorded on git.corp.example, not github.com",
+		},
+		{
+			name:      "newer version",
+			content:   `{"version":99,"operation":"enable (normal)","selection":"organization acme","hostname":"github.com"}` + "\n",
+			operation: "enable (normal)",
+			want:      "version 99 is not supported",
+		},
+		{
+			name:      "corrupt record",
+			content:   `{"version":1,"operation":"enable (normal)","selection":"organization acme","hostname":"github.com"}` + "\nnot json\n{}\n",
+			operation: "enable (normal)",
+			want:      "line 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			path := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+			if tt.content != "" {
+				if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Act
+			_, err := checkpoint.Resume(path, tt.operation, "organization acme", "github.com")
+
+			// Assert
+			if code := apperrors.GetExitCode(err); code != 2 {
+				t.Fatalf("Resume() error = %v (code %d), expected exit code 2", err, code)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, should contain %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+// TestCreateKeepsPreviousCheckpointUntilFirstOutcome verifies a new checkpoint
+// only replaces the previous one once an outcome is recorded, so a run that
+// stops before processing any repository cannot lose resumable progress.
+func TestCreateKeepsPreviousCheckpointUntilFirstOutcome(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+	previous, _ := checkpoint.Create(path, "enable (normal)", "organization acme", "github.com")
+	_ = previous.Record(interfaces.CheckpointRecord{Repository: "acme/api", Status: "enabled"})
+	_ = previous.Close()
+	recorded, _ := os.ReadFile(path)
+
+	// Act
+	abandoned, err := checkpoint.Create(path, "enable (normal)", "organization acme", "github.com")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	_ = abandoned.Close()
+	untouched, _ := os.ReadFile(path)
+
+	replacement, _ := checkpoint.Create(path, "enable (normal)", "organization acme", "github.com")
+	recordErr := replacement.Record(interfaces.CheckpointRecord{Repository: "acme/web", Status: "enabled"})
+	_ = replacement.Close()
+	replaced, _ := os.ReadFile(path)
+
+	// Assert
+	if string(untouched) != string(recorded) {
+		t.Errorf("checkpoint = %q, expected the previous run to be kept until an outcome is recorded", untouched)
+	}
+	if recordErr != nil {
+		t.Fatalf("Record() error = %v", recordErr)
+	}
+	if strings.Contains(string(replaced), "acme/api") || !strings.Contains(string(replaced), "acme/web") {
+		t.Errorf("checkpoint = %q, expected only the outcome of the new run", replaced)
+	}
+}
+
+// TestDefaultFileName verifies each operation, selection and host has its own
+// default checkpoint file.
+func TestDefaultFileName(t *testing.T) {
+	// Act
+	acme := checkpoint.DefaultFileName("enable (normal)", "organization acme", "github.com")
+	sameHost := checkpoint.DefaultFileName("enable (normal)", "organization acme", "GitHub.com")
+	other := checkpoint.DefaultFileName("enable (normal)", "organization other", "github.com")
+	disable := checkpoint.DefaultFileName("disable (normal)", "organization acme", "github.com")
+
+	// Assert
+	if acme != sameHost {
+		t.Errorf("file names = %q, %q, expected the host to be case-insensitive", acme, sameHost)
+	}
+	if acme == other || acme == disable {
+		t.Errorf("file name %q is shared with another selection or operation", acme)
+	}
+	if !strings.HasPrefix(acme, "checkpoint-") || filepath.Ext(acme) != ".jsonl" {
+		t.Errorf("file name = %q, expected checkpoint-<digest>.jsonl", acme)
+	}
+}
@@ -0,0 +1,114 @@
+// Package config_test provides tests for recording applied changes.
+//
+// These tests verify that ConfigService records every setting it changes, with
+// the login the token authenticated as, and records nothing in dry-run mode.
+package config_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/josejulio/ghautodelete/internal/config"
+	"github.com/josejulio/ghautodelete/internal/github"
+	"github.com/josejulio/ghautodelete/internal/token"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// mockChangeLog implements IChangeLog for testing.
+type mockChangeLog struct {
+	records []interfaces.ChangeRecord
+	err     error
+}
+
+func (m *mockChangeLog) Record(change interfaces.ChangeRecord) error {
+	m.records = append(m.records, change)
+	return m.err
+}
+
+// TestConfigureRecordsChange verifies an enabled setting is recorded with the
+// acting login, and that a dry run records nothing.
+func TestConfigureRecordsChange(t *testing.T) {
+	for _, dryRun := range []bool{false, true} {
+		// Arrange
+		mockClient := reconcileClient(false, nil)
+		mockClient.ValidateTokenFunc = func(ctx context.Context) (interfaces.ITokenInfo, error) {
+			return token.NewTokenInfo("octocat", []string{"repo"}), nil
+		}
+		changeLog := &mockChangeLog{}
+		service := config.NewConfigService(mockClient, &mockOutputWriter{}, config.WithChangeLog(changeLog))
+		if _, err := service.ValidateToken(context.Background(), true); err != nil {
+			t.Fatalf("ValidateToken() error = %v", err)
+		}
+
+		// Act
+		_, err := service.Configure(context.Background(), "octocat", "hello-world", dryRun)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Configure() error = %v, expected nil", err)
+		}
+		var expected []interfaces.ChangeRecord
+		if !dryRun {
+			expected = []interfaces.ChangeRecord{{
+				Actor: "octocat", Repository: "octocat/hello-world",
+				Setting: "delete_branch_on_merge", Before: "false", After: "true",
+			}}
+		}
+		if !reflect.DeepEqual(changeLog.records, expected) {
+			t.Errorf("dryRun=%v: records = %+v, expected %+v", dryRun, changeLog.records, expected)
+		}
+	}
+}
+
+// TestReconcileRecordsEveryChange verifies one record per changed setting.
+func TestReconcileRecordsEveryChange(t *testing.T) {
+	// Arrange
+	mockClient := reconcileClient(true, &github.RepositorySettings{AllowRebaseMerge: github.Bool(true)})
+	changeLog := &mockChangeLog{}
+	service := config.NewConfigService(mockClient, &mockOutputWriter{}, config.WithChangeLog(changeLog))
+	desired := &github.RepositorySettings{
+		DeleteBranchOnMerge: github.Bool(true),
+		AllowRebaseMerge:    github.Bool(false),
+		AllowSquashMerge:    github.Bool(true),
+	}
+
+	// Act
+	_, err := service.Reconcile(context.Background(), "octocat", "hello-world", desired, false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v, expected nil", err)
+	}
+	if len(changeLog.records) != 2 {
+		t.Fatalf("expected 2 records, got %+v", changeLog.records)
+	}
+	if r := changeLog.records[1]; r.Setting != "allow_rebase_merge" || r.Before != "true" || r.After != "false" {
+		t.Errorf("record = %+v, expected allow_rebase_merge true -> false", r)
+	}
+}
+
+// TestConfigureWarnsWhenChangeCannotBeRecorded verifies a change missing from
+// the audit log is reported as a warning without failing the repository.
+func TestConfigureWarnsWhenChangeCannotBeRecorded(t *testing.T) {
+	// Arrange
+	changeLog := &mockChangeLog{err: errors.New("disk full")}
+	writer := &mockOutputWriter{}
+	service := config.NewConfigService(reconcileClient(false, nil), writer, config.WithChangeLog(changeLog))
+
+	// Act
+	result, err := service.Configure(context.Background(), "octocat", "hello-world", false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Configure() error = %v, expected nil since the setting was changed", err)
+	}
+	if !result.IsNowEnabled() {
+		t.Error("IsNowEnabled() = false, expected the change to be applied")
+	}
+	if len(writer.InfoCalls) != 1 || !strings.Contains(writer.InfoCalls[0], "could not be recorded in the audit log: disk full") {
+		t.Errorf("InfoCalls = %v, expected a warning about the audit log", writer.InfoCalls)
+	}
+}
@@ -0,0 +1,205 @@
+// Package config provides reconciliation of repository settings.
+//
+// Reconcile compares a repository's current settings against a desired set,
+// updates only the settings that differ and verifies the result, so repeated
+// runs against an up-to-date repository make no changes.
+package config
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/github"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// unknownValue is reported as the current value of a setting the API did not return.
+const unknownValue = "unknown"
+
+// settingField describes one reconcilable setting.
+type settingField struct {
+	// name is the API name of the setting.
+	name string
+
+	// value returns the formatted value of the setting, or nil if it is not set.
+	value func(settings interfaces.IRepositorySettings) *string
+
+	// copy sets the setting on dst from src.
+	copy func(dst *github.RepositorySettings, src interfaces.IRepositorySettings)
+}
+
+// settingFields lists every reconcilable setting in display order.
+var settingFields = []settingField{
+	{
+		name: "delete_branch_on_merge",
+		value: func(s interfaces.IRepositorySettings) *string {
+			if !s.HasDeleteBranchOnMerge() {
+				return nil
+			}
+			return formatBool(github.Bool(s.GetDeleteBranchOnMerge()))
+		},
+		copy: func(dst *github.RepositorySettings, src interfaces.IRepositorySettings) {
+			dst.DeleteBranchOnMerge = github.Bool(src.GetDeleteBranchOnMerge())
+		},
+	},
+	{
+		name:  "allow_squash_merge",
+		value: func(s interfaces.IRepositorySettings) *string { return formatBool(s.GetAllowSquashMerge()) },
+		copy: func(dst *github.RepositorySettings, src interfaces.IRepositorySettings) {
+			dst.AllowSquashMerge = src.GetAllowSquashMerge()
+		},
+	},
+	{
+		name:  "allow_merge_commit",
+		value: func(s interfaces.IRepositorySettings) *string { return formatBool(s.GetAllowMergeCommit()) },
+		copy: func(dst *github.RepositorySettings, src interfaces.IRepositorySettings) {
+			dst.AllowMergeCommit = src.GetAllowMergeCommit()
+		},
+	},
+	{
+		name:  "allow_rebase_merge",
+		value: func(s interfaces.IRepositorySettings) *string { return formatBool(s.GetAllowRebaseMerge()) },
+		copy: func(dst *github.RepositorySettings, src interfaces.IRepositorySettings) {
+			dst.AllowRebaseMerge = src.GetAllowRebaseMerge()
+		},
+	},
+	{
+		name:  "allow_auto_merge",
+		value: func(s interfaces.IRepositorySettings) *string { return formatBool(s.GetAllowAutoMerge()) },
+		copy: func(dst *github.RepositorySettings, src interfaces.IRepositorySettings) {
+			dst.AllowAutoMerge = src.GetAllowAutoMerge()
+		},
+	},
+	{
+		name:  "allow_update_branch",
+		value: func(s interfaces.IRepositorySettings) *string { return formatBool(s.GetAllowUpdateBranch()) },
+		copy: func(dst *github.RepositorySettings, src interfaces.IRepositorySettings) {
+			dst.AllowUpdateBranch = src.GetAllowUpdateBranch()
+		},
+	},
+	{
+		name:  "squash_merge_commit_title",
+		value: func(s interfaces.IRepositorySettings) *string { return s.GetSquashMergeCommitTitle() },
+		copy: func(dst *github.RepositorySettings, src interfaces.IRepositorySettings) {
+			dst.SquashMergeCommitTitle = src.GetSquashMergeCommitTitle()
+		},
+	},
+	{
+		name:  "squash_merge_commit_message",
+		value: func(s interfaces.IRepositorySettings) *string { return s.GetSquashMergeCommitMessage() },
+		copy: func(dst *github.RepositorySettings, src interfaces.IRepositorySettings) {
+			dst.SquashMergeCommitMessage = src.GetSquashMergeCommitMessage()
+		},
+	},
+}
+
+// Reconcile brings a repository's settings in line with the desired settings.
+// It follows this workflow:
+//  1. Fetch current repository state
+//  2. Diff every desired setting against the current value
+//  3. If nothing differs or dryRun: return the differences without updating
+//  4. Otherwise: update only the differing settings, verify, return the differences
+//
+// Parameters:
+//   - ctx: the context for cancellation and deadlines
+//   - owner: the repository owner (user or organization)
+//   - name: the repository name
+//   - desired: the settings the repository should have; unset settings are ignored
+//   - dryRun: if true, don't actually update the repository
+//
+// Returns:
+//   - []interfaces.SettingChange: the settings that differed before the call
+//   - error: any error that occurred, including settings still differing after the update
+func (s *ConfigService) Reconcile(ctx context.Context, owner, name string, desired interfaces.IRepositorySettings, dryRun bool) ([]interfaces.SettingChange, error) {
+	// Step 1: Fetch current repository state
+	s.writer.Verbose("Fetching repository information")
+	repo, err := s.client.GetRepository(ctx, owner, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Diff desired against current settings
+	changes := diffSettings(desired, repo.GetSettings())
+
+	// Step 3: If nothing to change, or dry run, return without updating
+	if len(changes) == 0 || dryRun {
+		return changes, nil
+	}
+
+	// Step 4: Update only the differing settings
+	if err := s.checkWriteAccess(repo); err != nil {
+		return nil, err
+	}
+	s.writer.Verbose("Updating repository settings")
+	err = s.client.UpdateRepository(ctx, owner, name, patchSettings(desired, changes))
+	if err != nil {
+		return nil, err
+	}
+	s.recordChanges(repo.GetFullName(), changes)
+
+	// Step 5: Verify settings were applied
+	s.writer.Verbose("Verifying settings applied")
+	verifiedRepo, err := s.client.GetRepository(ctx, owner, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if remaining := diffSettings(desired, verifiedRepo.GetSettings()); len(remaining) > 0 {
+		names := make([]string, 0, len(remaining))
+		for _, change := range remaining {
+			names = append(names, change.Setting)
+		}
+		return nil, apperrors.NewVerificationError(verifiedRepo.GetFullName(),
+			fmt.Sprintf("settings were not applied (%s)", strings.Join(names, ", ")))
+	}
+
+	return changes, nil
+}
+
+// diffSettings returns every desired setting whose current value differs, in display order.
+func diffSettings(desired, current interfaces.IRepositorySettings) []interfaces.SettingChange {
+	var changes []interfaces.SettingChange
+	for _, field := range settingFields {
+		want := field.value(desired)
+		if want == nil {
+			continue
+		}
+
+		have := unknownValue
+		if current != nil {
+			if value := field.value(current); value != nil {
+				have = *value
+			}
+		}
+
+		if have != *want {
+			changes = append(changes, interfaces.SettingChange{Setting: field.name, Current: have, Desired: *want})
+		}
+	}
+	return changes
+}
+
+// patchSettings builds settings that contain only the changed fields from desired.
+func patchSettings(desired interfaces.IRepositorySettings, changes []interfaces.SettingChange) *github.RepositorySettings {
+	patch := &github.RepositorySettings{}
+	for _, change := range changes {
+		for _, field := range settingFields {
+			if field.name == change.Setting {
+				field.copy(patch, desired)
+			}
+		}
+	}
+	return patch
+}
+
+// formatBool formats an optional bool for display, or returns nil if it is not set.
+func formatBool(value *bool) *string {
+	if value == nil {
+		return nil
+	}
+	formatted := strconv.FormatBool(*value)
+	return &formatted
+}
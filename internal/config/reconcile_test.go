@@ -0,0 +1,169 @@
+// Package config_test provides tests for settings reconciliation.
+//
+// These tests verify that ConfigService.Reconcile diffs desired against current
+// settings, updates only the differing settings, honors dry-run mode and verifies
+// the update.
+package config_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/josejulio/ghautodelete/internal/config"
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/github"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+// reconcileClient returns a client whose repository starts with the given settings
+// and takes on every setting sent through UpdateRepository.
+func reconcileClient(deleteBranchOnMerge bool, settings *github.RepositorySettings) *mockGitHubClient {
+	repo := &mockRepository{
+		owner:               "octocat",
+		name:                "hello-world",
+		defaultBranch:       "main",
+		deleteBranchOnMerge: deleteBranchOnMerge,
+		settings:            settings,
+	}
+	return &mockGitHubClient{
+		GetRepositoryFunc: func(ctx context.Context, owner, name string) (interfaces.IRepository, error) {
+			return repo, nil
+		},
+		UpdateRepositoryFunc: func(ctx context.Context, owner, name string, update interfaces.IRepositorySettings) error {
+			if update.HasDeleteBranchOnMerge() {
+				repo.deleteBranchOnMerge = update.GetDeleteBranchOnMerge()
+			}
+			if repo.settings == nil {
+				repo.settings = &github.RepositorySettings{}
+			}
+			if v := update.GetAllowSquashMerge(); v != nil {
+				repo.settings.AllowSquashMerge = v
+			}
+			if v := update.GetAllowRebaseMerge(); v != nil {
+				repo.settings.AllowRebaseMerge = v
+			}
+			if v := update.GetSquashMergeCommitTitle(); v != nil {
+				repo.settings.SquashMergeCommitTitle = v
+			}
+			return nil
+		},
+	}
+}
+
+// =============================================================================
+// Reconcile Tests
+// =============================================================================
+
+// TestReconcileUpdatesOnlyDifferingSettings verifies the diff and the minimal update.
+func TestReconcileUpdatesOnlyDifferingSettings(t *testing.T) {
+	// Arrange
+	mockClient := reconcileClient(true, &github.RepositorySettings{
+		AllowSquashMerge: github.Bool(true),
+		AllowRebaseMerge: github.Bool(true),
+	})
+	service := config.NewConfigService(mockClient, &mockOutputWriter{})
+	desired := &github.RepositorySettings{
+		DeleteBranchOnMerge:    github.Bool(true),
+		AllowSquashMerge:       github.Bool(true),
+		AllowRebaseMerge:       github.Bool(false),
+		SquashMergeCommitTitle: github.String(github.SquashMergeCommitTitlePRTitle),
+	}
+
+	// Act
+	changes, err := service.Reconcile(context.Background(), "octocat", "hello-world", desired, false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v, expected nil", err)
+	}
+	expected := []interfaces.SettingChange{
+		{Setting: "allow_rebase_merge", Current: "true", Desired: "false"},
+		{Setting: "squash_merge_commit_title", Current: "unknown", Desired: "PR_TITLE"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Reconcile() changes = %+v, expected %+v", changes, expected)
+	}
+	if len(mockClient.UpdateRepositoryCalls) != 1 {
+		t.Fatalf("UpdateRepository called %d times, expected 1", len(mockClient.UpdateRepositoryCalls))
+	}
+	update := mockClient.UpdateRepositoryCalls[0].Settings
+	if update.HasDeleteBranchOnMerge() || update.GetAllowSquashMerge() != nil {
+		t.Error("update should not include settings that already match")
+	}
+	if update.GetAllowRebaseMerge() == nil || *update.GetAllowRebaseMerge() {
+		t.Error("update should set allow_rebase_merge=false")
+	}
+}
+
+// TestReconcileNoChanges verifies an up-to-date repository is not updated.
+func TestReconcileNoChanges(t *testing.T) {
+	// Arrange
+	mockClient := reconcileClient(true, nil)
+	service := config.NewConfigService(mockClient, &mockOutputWriter{})
+
+	// Act
+	changes, err := service.Reconcile(context.Background(), "octocat", "hello-world", github.NewRepositorySettings(true), false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v, expected nil", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Reconcile() changes = %+v, expected none", changes)
+	}
+	if len(mockClient.UpdateRepositoryCalls) != 0 {
+		t.Errorf("UpdateRepository called %d times, expected 0", len(mockClient.UpdateRepositoryCalls))
+	}
+}
+
+// TestReconcileDryRunDoesNotUpdate verifies dry-run reports differences without updating.
+func TestReconcileDryRunDoesNotUpdate(t *testing.T) {
+	// Arrange
+	mockClient := reconcileClient(false, nil)
+	service := config.NewConfigService(mockClient, &mockOutputWriter{})
+
+	// Act
+	changes, err := service.Reconcile(context.Background(), "octocat", "hello-world", github.NewRepositorySettings(true), true)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v, expected nil", err)
+	}
+	expected := []interfaces.SettingChange{{Setting: "delete_branch_on_merge", Current: "false", Desired: "true"}}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Reconcile() changes = %+v, expected %+v", changes, expected)
+	}
+	if len(mockClient.UpdateRepositoryCalls) != 0 {
+		t.Errorf("UpdateRepository called %d times, expected 0", len(mockClient.UpdateRepositoryCalls))
+	}
+}
+
+// TestReconcileReportsUnappliedSettings verifies verification fails when the update did not stick.
+func TestReconcileReportsUnappliedSettings(t *testing.T) {
+	// Arrange
+	mockClient := reconcileClient(true, nil)
+	mockClient.UpdateRepositoryFunc = func(ctx context.Context, owner, name string, settings interfaces.IRepositorySettings) error {
+		return nil // accepted but ignored
+	}
+	service := config.NewConfigService(mockClient, &mockOutputWriter{})
+	desired := &github.RepositorySettings{AllowAutoMerge: github.Bool(true)}
+
+	// Act
+	_, err := service.Reconcile(context.Background(), "octocat", "hello-world", desired, false)
+
+	// Assert
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) || appErr.Code != apperrors.ErrGeneral {
+		t.Fatalf("Reconcile() error = %v, expected a verification AppError", err)
+	}
+	if !strings.Contains(err.Error(), "Verification failed for octocat/hello-world: settings were not applied (allow_auto_merge)") {
+		t.Errorf("Reconcile() error = %v, expected the unapplied settings", err)
+	}
+}
@@ -6,6 +6,8 @@ package config
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	"github.com/josejulio/ghautodelete/internal/github"
 	"github.com/josejulio/ghautodelete/pkg/interfaces"
@@ -16,17 +18,38 @@ import (
 type ConfigService struct {
 	client interfaces.IGitHubClient
 	writer interfaces.IOutputWriter
+
+	// tokenInfo is set by ValidateToken; nil until the token has been validated.
+	tokenInfo interfaces.ITokenInfo
+
+	// changeLog records every applied change; nil disables recording.
+	changeLog interfaces.IChangeLog
+}
+
+// ServiceOption configures optional ConfigService behavior.
+type ServiceOption func(*ConfigService)
+
+// WithChangeLog records every setting change the service applies in log.
+func WithChangeLog(log interfaces.IChangeLog) ServiceOption {
+	return func(s *ConfigService) {
+		s.changeLog = log
+	}
 }
 
 // NewConfigService creates a new ConfigService instance.
 // Parameters:
 //   - client: the GitHub client for API operations
 //   - writer: the output writer for logging messages
-func NewConfigService(client interfaces.IGitHubClient, writer interfaces.IOutputWriter) *ConfigService {
-	return &ConfigService{
+//   - opts: optional behavior, e.g. WithChangeLog
+func NewConfigService(client interfaces.IGitHubClient, writer interfaces.IOutputWriter, opts ...ServiceOption) *ConfigService {
+	s := &ConfigService{
 		client: client,
 		writer: writer,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // Configure applies the delete-branch-on-merge setting to a repository.
@@ -46,6 +69,35 @@ func NewConfigService(client interfaces.IGitHubClient, writer interfaces.IOutput
 //   - IConfigResult: the outcome of the configuration operation
 //   - error: any error that occurred during the operation
 func (s *ConfigService) Configure(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+	return s.apply(ctx, owner, name, true, dryRun)
+}
+
+// Disable turns the delete-branch-on-merge setting off for a repository.
+// It mirrors Configure with the desired state inverted:
+//  1. Fetch current repository state
+//  2. If already disabled: return AlreadyEnabled=false, NowEnabled=false
+//  3. If dryRun: return AlreadyEnabled=true, NowEnabled=true
+//  4. Otherwise: update settings, verify, return AlreadyEnabled=true, NowEnabled=false
+//
+// Parameters:
+//   - ctx: the context for cancellation and deadlines
+//   - owner: the repository owner (user or organization)
+//   - name: the repository name
+//   - dryRun: if true, don't actually update the repository
+//
+// Returns:
+//   - IConfigResult: the outcome of the operation; IsNowEnabled reports the verified state
+//   - error: any error that occurred during the operation
+func (s *ConfigService) Disable(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+	return s.apply(ctx, owner, name, false, dryRun)
+}
+
+// apply moves the delete-branch-on-merge setting of a repository to the desired state.
+// The returned result reports the state before the call in WasAlreadyEnabled and the
+// verified state afterwards in IsNowEnabled; neither changes when the repository is
+// already in the desired state or dryRun is set.
+// An applied change is recorded in the change log, if one is set.
+func (s *ConfigService) apply(ctx context.Context, owner, name string, desired, dryRun bool) (interfaces.IConfigResult, error) {
 	// Step 1: Fetch current repository state
 	s.writer.Verbose("Fetching repository information")
 	repo, err := s.client.GetRepository(ctx, owner, name)
@@ -53,47 +105,44 @@ func (s *ConfigService) Configure(ctx context.Context, owner, name string, dryRu
 		return nil, err
 	}
 
-	// Check if already enabled
-	alreadyEnabled := repo.GetDeleteBranchOnMerge()
+	current := repo.GetDeleteBranchOnMerge()
 
-	// Step 2: If already enabled, return early
-	if alreadyEnabled {
+	// Step 2: If already in the desired state, or dry run, return without updating
+	if current == desired || dryRun {
 		return NewConfigResult(
-			true,  // wasAlreadyEnabled
-			true,  // isNowEnabled
+			current, // wasAlreadyEnabled
+			current, // isNowEnabled
 			repo.GetDefaultBranch(),
 			repo.GetFullName(),
 		), nil
 	}
 
-	// Step 3: If dry run, return without updating
-	if dryRun {
-		return NewConfigResult(
-			false, // wasAlreadyEnabled
-			false, // isNowEnabled
-			repo.GetDefaultBranch(),
-			repo.GetFullName(),
-		), nil
+	// Step 3: Update repository settings
+	if err := s.checkWriteAccess(repo); err != nil {
+		return nil, err
 	}
-
-	// Step 4: Update repository settings
 	s.writer.Verbose("Updating repository settings")
-	settings := github.NewRepositorySettings(true)
+	settings := github.NewRepositorySettings(desired)
 	err = s.client.UpdateRepository(ctx, owner, name, settings)
 	if err != nil {
 		return nil, err
 	}
+	s.recordChanges(repo.GetFullName(), []interfaces.SettingChange{{
+		Setting: "delete_branch_on_merge",
+		Current: strconv.FormatBool(current),
+		Desired: strconv.FormatBool(desired),
+	}})
 
-	// Step 5: Verify settings were applied
+	// Step 4: Verify settings were applied
 	s.writer.Verbose("Verifying settings applied")
 	verifiedRepo, err := s.client.GetRepository(ctx, owner, name)
 	if err != nil {
 		return nil, err
 	}
 
-	// Step 6: Return result
+	// Step 5: Return result
 	return NewConfigResult(
-		false, // wasAlreadyEnabled
+		current,                               // wasAlreadyEnabled
 		verifiedRepo.GetDeleteBranchOnMerge(), // isNowEnabled
 		verifiedRepo.GetDefaultBranch(),
 		verifiedRepo.GetFullName(),
@@ -128,3 +177,30 @@ func (s *ConfigService) CheckStatus(ctx context.Context, owner, name string) (in
 		repo.GetFullName(),
 	), nil
 }
+
+// recordChanges appends applied changes to the change log, if one is set.
+// A change that cannot be recorded is reported as a warning: the setting was
+// changed all the same, so the repository does not fail.
+func (s *ConfigService) recordChanges(fullName string, changes []interfaces.SettingChange) {
+	if s.changeLog == nil {
+		return
+	}
+
+	actor := ""
+	if s.tokenInfo != nil {
+		actor = s.tokenInfo.GetUsername()
+	}
+	for _, change := range changes {
+		err := s.changeLog.Record(interfaces.ChangeRecord{
+			Actor:      actor,
+			Repository: fullName,
+			Setting:    change.Setting,
+			Before:     change.Current,
+			After:      change.Desired,
+		})
+		if err != nil {
+			s.writer.Info(fmt.Sprintf("Warning: %s was changed but the change could not be recorded in the audit log: %v", fullName, err))
+			return
+		}
+	}
+}
@@ -24,8 +24,10 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/josejulio/ghautodelete/internal/config"
+	"github.com/josejulio/ghautodelete/internal/github"
 	"github.com/josejulio/ghautodelete/pkg/interfaces"
 )
 
@@ -90,6 +92,22 @@ func (m *mockGitHubClient) ValidateToken(ctx context.Context) (interfaces.IToken
 	return nil, errors.New("ValidateTokenFunc not set")
 }
 
+func (m *mockGitHubClient) ListOrganizationRepositories(ctx context.Context, org string) ([]interfaces.IRepository, error) {
+	return nil, errors.New("ListOrganizationRepositories not expected")
+}
+
+func (m *mockGitHubClient) ListUserRepositories(ctx context.Context, affiliation string) ([]interfaces.IRepository, error) {
+	return nil, errors.New("ListUserRepositories not expected")
+}
+
+func (m *mockGitHubClient) SearchRepositories(ctx context.Context, query string) ([]interfaces.IRepository, int, error) {
+	return nil, 0, errors.New("SearchRepositories not expected")
+}
+
+func (m *mockGitHubClient) ListEnterpriseOrganizations(ctx context.Context, slug string) ([]string, error) {
+	return nil, errors.New("ListEnterpriseOrganizations not expected")
+}
+
 // mockOutputWriter implements IOutputWriter for testing.
 type mockOutputWriter struct {
 	// VerboseCalls tracks all Verbose messages.
@@ -124,6 +142,9 @@ type mockRepository struct {
 	name                string
 	defaultBranch       string
 	deleteBranchOnMerge bool
+	private             bool
+	// settings holds the merge settings beyond delete-branch-on-merge; nil means none reported.
+	settings *github.RepositorySettings
 }
 
 func (m *mockRepository) GetOwner() string {
@@ -146,6 +167,47 @@ func (m *mockRepository) GetFullName() string {
 	return m.owner + "/" + m.name
 }
 
+func (m *mockRepository) IsArchived() bool {
+	return false
+}
+
+func (m *mockRepository) IsFork() bool {
+	return false
+}
+
+func (m *mockRepository) IsTemplate() bool {
+	return false
+}
+
+func (m *mockRepository) IsPrivate() bool {
+	return m.private
+}
+
+func (m *mockRepository) GetVisibility() string {
+	return ""
+}
+
+func (m *mockRepository) GetTopics() []string {
+	return nil
+}
+
+func (m *mockRepository) GetLanguage() string {
+	return ""
+}
+
+func (m *mockRepository) GetPushedAt() time.Time {
+	return time.Time{}
+}
+
+func (m *mockRepository) GetSettings() interfaces.IRepositorySettings {
+	settings := &github.RepositorySettings{}
+	if m.settings != nil {
+		*settings = *m.settings
+	}
+	settings.DeleteBranchOnMerge = github.Bool(m.deleteBranchOnMerge)
+	return settings
+}
+
 // =============================================================================
 // Interface Satisfaction Tests
 // =============================================================================
@@ -1388,3 +1450,152 @@ func TestCheckStatusPassesContextToClient(t *testing.T) {
 		t.Error("Context value was not preserved")
 	}
 }
+
+// =============================================================================
+// Disable Tests
+// =============================================================================
+
+// TestDisableScenarios tests turning delete-branch-on-merge off.
+//
+// The implementation should:
+// - Skip the update when the setting is already off
+// - Skip the update in dry-run mode
+// - Otherwise update with delete_branch_on_merge=false and verify
+func TestDisableScenarios(t *testing.T) {
+	tests := []struct {
+		name                 string
+		initialEnabled       bool
+		dryRun               bool
+		updateError          error
+		expectedWasEnabled   bool
+		expectedNowEnabled   bool
+		expectedUpdateCalls  int
+		expectedGetRepoCalls int
+		expectError          bool
+	}{
+		{
+			name:                 "enabled -> disabled",
+			initialEnabled:       true,
+			expectedWasEnabled:   true,
+			expectedNowEnabled:   false,
+			expectedUpdateCalls:  1,
+			expectedGetRepoCalls: 2, // fetch + verify
+		},
+		{
+			name:                 "already disabled",
+			initialEnabled:       false,
+			expectedWasEnabled:   false,
+			expectedNowEnabled:   false,
+			expectedUpdateCalls:  0,
+			expectedGetRepoCalls: 1, // fetch only
+		},
+		{
+			name:                 "dry run when enabled",
+			initialEnabled:       true,
+			dryRun:               true,
+			expectedWasEnabled:   true,
+			expectedNowEnabled:   true,
+			expectedUpdateCalls:  0,
+			expectedGetRepoCalls: 1, // fetch only
+		},
+		{
+			name:                 "update fails",
+			initialEnabled:       true,
+			updateError:          errors.New("update failed"),
+			expectedUpdateCalls:  1,
+			expectedGetRepoCalls: 1, // fetch only (no verify after error)
+			expectError:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			fetchCount := 0
+			mockClient := &mockGitHubClient{
+				GetRepositoryFunc: func(ctx context.Context, owner, name string) (interfaces.IRepository, error) {
+					fetchCount++
+					enabled := tt.initialEnabled
+					if fetchCount > 1 {
+						enabled = false // After successful update
+					}
+					return &mockRepository{
+						owner:               owner,
+						name:                name,
+						defaultBranch:       "main",
+						deleteBranchOnMerge: enabled,
+					}, nil
+				},
+				UpdateRepositoryFunc: func(ctx context.Context, owner, name string, settings interfaces.IRepositorySettings) error {
+					return tt.updateError
+				},
+			}
+			service := config.NewConfigService(mockClient, &mockOutputWriter{})
+
+			// Act
+			result, err := service.Disable(context.Background(), "owner", "repo", tt.dryRun)
+
+			// Assert - call counts
+			if len(mockClient.UpdateRepositoryCalls) != tt.expectedUpdateCalls {
+				t.Errorf("UpdateRepository called %d times, expected %d", len(mockClient.UpdateRepositoryCalls), tt.expectedUpdateCalls)
+			}
+			if len(mockClient.GetRepositoryCalls) != tt.expectedGetRepoCalls {
+				t.Errorf("GetRepository called %d times, expected %d", len(mockClient.GetRepositoryCalls), tt.expectedGetRepoCalls)
+			}
+
+			// Assert - error
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			// Assert - result
+			if result.WasAlreadyEnabled() != tt.expectedWasEnabled {
+				t.Errorf("WasAlreadyEnabled() = %v, expected %v", result.WasAlreadyEnabled(), tt.expectedWasEnabled)
+			}
+			if result.IsNowEnabled() != tt.expectedNowEnabled {
+				t.Errorf("IsNowEnabled() = %v, expected %v", result.IsNowEnabled(), tt.expectedNowEnabled)
+			}
+		})
+	}
+}
+
+// TestDisableSendsFalseSetting verifies UpdateRepository receives delete_branch_on_merge=false.
+func TestDisableSendsFalseSetting(t *testing.T) {
+	// Arrange
+	fetchCount := 0
+	mockClient := &mockGitHubClient{
+		GetRepositoryFunc: func(ctx context.Context, owner, name string) (interfaces.IRepository, error) {
+			fetchCount++
+			return &mockRepository{
+				owner:               owner,
+				name:                name,
+				defaultBranch:       "main",
+				deleteBranchOnMerge: fetchCount == 1,
+			}, nil
+		},
+		UpdateRepositoryFunc: func(ctx context.Context, owner, name string, settings interfaces.IRepositorySettings) error {
+			return nil
+		},
+	}
+	service := config.NewConfigService(mockClient, &mockOutputWriter{})
+
+	// Act
+	_, err := service.Disable(context.Background(), "octocat", "hello-world", false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Disable() error = %v", err)
+	}
+	if len(mockClient.UpdateRepositoryCalls) != 1 {
+		t.Fatalf("UpdateRepository called %d times, expected 1", len(mockClient.UpdateRepositoryCalls))
+	}
+	if mockClient.UpdateRepositoryCalls[0].Settings.GetDeleteBranchOnMerge() {
+		t.Error("UpdateRepository settings should have delete_branch_on_merge=false")
+	}
+}
@@ -0,0 +1,77 @@
+// Package config provides pre-flight token validation.
+//
+// ValidateToken checks the token once before any repository is processed. For
+// classic personal access tokens, which report their OAuth scopes, it confirms
+// the scopes allow changing repository settings, so a run fails with a precise
+// message instead of a 403 on the first update. Tokens that report no scopes
+// (fine-grained and GitHub App tokens) are left to GitHub to authorize.
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// OAuth scopes that allow changing repository settings.
+const (
+	scopeRepo       = "repo"
+	scopePublicRepo = "public_repo"
+)
+
+// ValidateToken validates the API token and records its scopes for later updates.
+//
+// Parameters:
+//   - ctx: the context for cancellation and deadlines
+//   - write: if true, confirm the token may change repository settings
+//
+// Returns:
+//   - ITokenInfo: the token's user and scopes
+//   - error: an authentication error if the token is invalid, or an authorization
+//     error if write is set and a classic token has neither "repo" nor "public_repo"
+func (s *ConfigService) ValidateToken(ctx context.Context, write bool) (interfaces.ITokenInfo, error) {
+	s.writer.Verbose("Validating token")
+	info, err := s.client.ValidateToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.writer.Verbose(fmt.Sprintf("Authenticated as %s", info.GetUsername()))
+	scopes := info.GetScopes()
+	if len(scopes) == 0 {
+		s.writer.Verbose("Token reports no OAuth scopes; skipping scope check")
+		s.tokenInfo = info
+		return info, nil
+	}
+	s.writer.Verbose(fmt.Sprintf("Token scopes: %s", strings.Join(scopes, ", ")))
+
+	if write && !info.HasScope(scopeRepo) {
+		if !info.HasScope(scopePublicRepo) {
+			return nil, apperrors.NewAuthorizationError(fmt.Sprintf(
+				"Token lacks the 'repo' scope required to change repository settings (granted: %s). "+
+					"Create a token with the 'repo' scope, or 'public_repo' for public repositories only",
+				strings.Join(scopes, ", ")))
+		}
+		s.writer.Verbose("Token has the 'public_repo' scope only; private repositories cannot be changed")
+	}
+
+	s.tokenInfo = info
+	return info, nil
+}
+
+// checkWriteAccess confirms the validated token may change the repository's settings.
+// Returns nil if the token was not validated or reports no scopes.
+func (s *ConfigService) checkWriteAccess(repo interfaces.IRepository) error {
+	if s.tokenInfo == nil || len(s.tokenInfo.GetScopes()) == 0 || s.tokenInfo.HasScope(scopeRepo) {
+		return nil
+	}
+
+	if repo.IsPrivate() {
+		return apperrors.NewAuthorizationError(fmt.Sprintf(
+			"Token lacks the 'repo' scope required to change private repository %s", repo.GetFullName()))
+	}
+	return nil
+}
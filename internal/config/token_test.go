@@ -0,0 +1,108 @@
+// Package config_test provides tests for pre-flight token validation.
+//
+// These tests verify that ConfigService.ValidateToken rejects classic tokens
+// without a write scope before any update, and that a "public_repo" token is
+// refused for private repositories before the PATCH request.
+package config_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/josejulio/ghautodelete/internal/config"
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/token"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// clientWithScopes returns a client whose token belongs to octocat with the given scopes.
+func clientWithScopes(scopes ...string) *mockGitHubClient {
+	return &mockGitHubClient{
+		ValidateTokenFunc: func(ctx context.Context) (interfaces.ITokenInfo, error) {
+			return token.NewTokenInfo("octocat", scopes), nil
+		},
+	}
+}
+
+// TestValidateTokenChecksWriteScopes verifies the scope check for each kind of token.
+func TestValidateTokenChecksWriteScopes(t *testing.T) {
+	tests := []struct {
+		name         string
+		scopes       []string
+		write        bool
+		expectedCode int
+	}{
+		{"repo scope", []string{"repo", "read:org"}, true, 0},
+		{"public_repo scope", []string{"public_repo"}, true, 0},
+		{"no write scope", []string{"read:org", "gist"}, true, 4},
+		{"no write scope when reading", []string{"read:org"}, false, 0},
+		{"no scopes reported", nil, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := config.NewConfigService(clientWithScopes(tt.scopes...), &mockOutputWriter{})
+
+			// Act
+			info, err := service.ValidateToken(context.Background(), tt.write)
+
+			// Assert
+			if code := apperrors.GetExitCode(err); code != tt.expectedCode {
+				t.Fatalf("exit code = %d, expected %d (err: %v)", code, tt.expectedCode, err)
+			}
+			if err == nil && info.GetUsername() != "octocat" {
+				t.Errorf("GetUsername() = %q, expected %q", info.GetUsername(), "octocat")
+			}
+			if err != nil && !strings.Contains(err.Error(), "'repo' scope") {
+				t.Errorf("error should name the missing scope, got %q", err.Error())
+			}
+		})
+	}
+}
+
+// TestValidateTokenReportsScopesVerbosely verifies the validation result is shown under --verbose.
+func TestValidateTokenReportsScopesVerbosely(t *testing.T) {
+	// Arrange
+	mockWriter := &mockOutputWriter{}
+	service := config.NewConfigService(clientWithScopes("repo", "read:org"), mockWriter)
+
+	// Act
+	_, err := service.ValidateToken(context.Background(), true)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v, expected nil", err)
+	}
+	output := strings.Join(mockWriter.VerboseCalls, "\n")
+	for _, want := range []string{"Authenticated as octocat", "Token scopes: repo, read:org"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("verbose output should contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+// TestPublicRepoScopeRejectsPrivateRepository verifies no PATCH is sent for a private repository.
+func TestPublicRepoScopeRejectsPrivateRepository(t *testing.T) {
+	// Arrange
+	mockClient := clientWithScopes("public_repo")
+	mockClient.GetRepositoryFunc = func(ctx context.Context, owner, name string) (interfaces.IRepository, error) {
+		return &mockRepository{owner: owner, name: name, defaultBranch: "main", private: true}, nil
+	}
+	service := config.NewConfigService(mockClient, &mockOutputWriter{})
+	if _, err := service.ValidateToken(context.Background(), true); err != nil {
+		t.Fatalf("ValidateToken() error = %v, expected nil", err)
+	}
+
+	// Act
+	_, err := service.Configure(context.Background(), "octocat", "secret", false)
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 4 {
+		t.Errorf("exit code = %d, expected 4 (err: %v)", code, err)
+	}
+	if len(mockClient.UpdateRepositoryCalls) != 0 {
+		t.Errorf("expected no UpdateRepository calls, got %d", len(mockClient.UpdateRepositoryCalls))
+	}
+}
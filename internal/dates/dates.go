@@ -0,0 +1,29 @@
+// Package dates parses the dates given on the command line, such as
+// --pushed-since and the --since and --until flags of history.
+package dates
+
+import (
+	"fmt"
+	"time"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+)
+
+// Parse parses a date flag value: an RFC 3339 timestamp, or a YYYY-MM-DD date
+// in UTC. With endOfDay, a date stands for its last instant, so an --until
+// date includes the whole day.
+func Parse(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, apperrors.NewValidationError(fmt.Sprintf(
+			"Invalid date %q. Expected YYYY-MM-DD or an RFC 3339 timestamp", value))
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
@@ -0,0 +1,53 @@
+// Package dates_test provides tests for date flag parsing.
+//
+// These tests verify that Parse accepts dates and RFC 3339 timestamps, extends
+// a date to the end of its day on request and rejects anything else.
+package dates_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/josejulio/ghautodelete/internal/dates"
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+)
+
+// TestParse verifies the accepted formats and the end-of-day adjustment.
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		endOfDay bool
+		expected time.Time
+	}{
+		{name: "date", value: "2024-03-01", expected: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "end of day", value: "2024-03-01", endOfDay: true, expected: time.Date(2024, 3, 1, 23, 59, 59, 999999999, time.UTC)},
+		{name: "timestamp", value: "2024-03-01T12:30:00Z", endOfDay: true, expected: time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result, err := dates.Parse(tt.value, tt.endOfDay)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("Parse() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestParseRejectsInvalidInput verifies malformed dates are validation errors.
+func TestParseRejectsInvalidInput(t *testing.T) {
+	// Act
+	_, err := dates.Parse("March 1st", false)
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 2 {
+		t.Errorf("Parse() exit code = %d, expected 2", code)
+	}
+}
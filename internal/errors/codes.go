@@ -8,9 +8,13 @@
 // - Network connection failure -> code 1
 // - GitHub API server error -> code 1
 // - Invalid command line arguments -> code 2
+// - Audit found non-compliant repositories -> code 7
 package errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // ErrorCode represents the type of error that occurred.
 type ErrorCode int
@@ -33,8 +37,66 @@ const (
 
 	// ErrAPIRateLimited represents API rate limit exceeded errors (exit code 6).
 	ErrAPIRateLimited ErrorCode = 6
+
+	// ErrDriftDetected represents an audit that found non-compliant repositories (exit code 7).
+	ErrDriftDetected ErrorCode = 7
 )
 
+// severity orders the error codes from most to least severe. Failures that
+// affect every repository of a run (credentials, permissions, rate limits)
+// outrank unexpected failures, which outrank failures of a single repository.
+var severity = []ErrorCode{
+	ErrAuthenticationFailed,
+	ErrInsufficientPerms,
+	ErrAPIRateLimited,
+	ErrGeneral,
+	ErrRepositoryNotFound,
+	ErrInvalidArguments,
+	ErrDriftDetected,
+}
+
+// String returns the failure category of the code (e.g., "repository not found").
+func (c ErrorCode) String() string {
+	switch c {
+	case ErrGeneral:
+		return "general error"
+	case ErrInvalidArguments:
+		return "invalid arguments"
+	case ErrAuthenticationFailed:
+		return "authentication failed"
+	case ErrInsufficientPerms:
+		return "insufficient permissions"
+	case ErrRepositoryNotFound:
+		return "repository not found"
+	case ErrAPIRateLimited:
+		return "API rate limited"
+	case ErrDriftDetected:
+		return "drift detected"
+	}
+	return fmt.Sprintf("error code %d", int(c))
+}
+
+// Severity ranks the code: higher is more severe. Unknown codes rank lowest.
+func (c ErrorCode) Severity() int {
+	for i, code := range severity {
+		if code == c {
+			return len(severity) - i
+		}
+	}
+	return 0
+}
+
+// MostSevere returns the most severe of codes, or 0 if there are none.
+func MostSevere(codes ...ErrorCode) ErrorCode {
+	var most ErrorCode
+	for _, code := range codes {
+		if most == 0 || code.Severity() > most.Severity() {
+			most = code
+		}
+	}
+	return most
+}
+
 // GetExitCode maps an error to its corresponding exit code.
 //
 // If the error is an AppError, it returns the error code as an int.
@@ -56,3 +118,23 @@ func GetExitCode(err error) int {
 	// For any other error type, return 1 (general error)
 	return 1
 }
+
+// AsAppError returns err as an AppError so it can be reported by its code.
+//
+// An AppError is returned unchanged. Any other error becomes the cause of an
+// AppError with the same message, which takes the code of the first AppError in
+// its chain, or ErrGeneral if there is none. Returns nil if err is nil.
+func AsAppError(err error) *AppError {
+	if err == nil {
+		return nil
+	}
+
+	if appErr, ok := err.(*AppError); ok {
+		return appErr
+	}
+
+	return &AppError{
+		Code:  ErrorCode(GetExitCode(err)),
+		Cause: err,
+	}
+}
@@ -79,6 +79,12 @@ func TestErrorCodeConstantsExist(t *testing.T) {
 			expectedInt: 6,
 			description: "API rate limit exceeded",
 		},
+		{
+			name:        "ErrDriftDetected is 7",
+			code:        apperrors.ErrDriftDetected,
+			expectedInt: 7,
+			description: "Audit found non-compliant repositories",
+		},
 	}
 
 	for _, tt := range tests {
@@ -121,6 +127,7 @@ func TestErrorCodeValuesAreUnique(t *testing.T) {
 		apperrors.ErrInsufficientPerms,
 		apperrors.ErrRepositoryNotFound,
 		apperrors.ErrAPIRateLimited,
+		apperrors.ErrDriftDetected,
 	}
 
 	// Act - build map to check for duplicates
@@ -132,9 +139,9 @@ func TestErrorCodeValuesAreUnique(t *testing.T) {
 		seen[code] = true
 	}
 
-	// Assert - verify we have 6 unique codes
-	if len(seen) != 6 {
-		t.Errorf("Expected 6 unique error codes, got %d", len(seen))
+	// Assert - verify we have 7 unique codes
+	if len(seen) != 7 {
+		t.Errorf("Expected 7 unique error codes, got %d", len(seen))
 	}
 }
 
@@ -428,6 +435,79 @@ func TestAllErrorCodesMappedToExitCodes(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// Severity and Category Tests
+// =============================================================================
+
+// TestMostSevere verifies how failure codes rank against each other.
+func TestMostSevere(t *testing.T) {
+	tests := []struct {
+		name     string
+		codes    []apperrors.ErrorCode
+		expected apperrors.ErrorCode
+	}{
+		{name: "none", codes: nil, expected: 0},
+		{name: "single", codes: []apperrors.ErrorCode{apperrors.ErrRepositoryNotFound}, expected: apperrors.ErrRepositoryNotFound},
+		{name: "permissions over not found", codes: []apperrors.ErrorCode{apperrors.ErrRepositoryNotFound, apperrors.ErrInsufficientPerms}, expected: apperrors.ErrInsufficientPerms},
+		{name: "rate limit over general", codes: []apperrors.ErrorCode{apperrors.ErrGeneral, apperrors.ErrAPIRateLimited}, expected: apperrors.ErrAPIRateLimited},
+		{name: "general over not found", codes: []apperrors.ErrorCode{apperrors.ErrRepositoryNotFound, apperrors.ErrGeneral}, expected: apperrors.ErrGeneral},
+		{name: "authentication over all", codes: []apperrors.ErrorCode{apperrors.ErrInsufficientPerms, apperrors.ErrAuthenticationFailed, apperrors.ErrAPIRateLimited}, expected: apperrors.ErrAuthenticationFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			code := apperrors.MostSevere(tt.codes...)
+
+			// Assert
+			if code != tt.expected {
+				t.Errorf("MostSevere(%v) = %d, expected %d", tt.codes, code, tt.expected)
+			}
+		})
+	}
+}
+
+// TestErrorCodeString verifies each code names its failure category.
+func TestErrorCodeString(t *testing.T) {
+	// Act & Assert
+	if got := apperrors.ErrRepositoryNotFound.String(); got != "repository not found" {
+		t.Errorf("ErrRepositoryNotFound.String() = %q, expected %q", got, "repository not found")
+	}
+	if got := apperrors.ErrorCode(999).String(); got != "error code 999" {
+		t.Errorf("ErrorCode(999).String() = %q, expected %q", got, "error code 999")
+	}
+}
+
+// TestAsAppError verifies errors are converted to AppErrors keeping their code,
+// message and error chain.
+func TestAsAppError(t *testing.T) {
+	// Arrange
+	notFound := apperrors.NewRepositoryNotFoundError("acme", "api")
+	wrapped := wrapError(notFound, "failed to list repositories")
+
+	// Act
+	same := apperrors.AsAppError(notFound)
+	fromWrapped := apperrors.AsAppError(wrapped)
+	fromPlain := apperrors.AsAppError(errors.New("connection reset"))
+
+	// Assert
+	if same != notFound {
+		t.Error("an AppError should be returned unchanged")
+	}
+	if fromWrapped.Code != apperrors.ErrRepositoryNotFound || fromWrapped.Error() != wrapped.Error() {
+		t.Errorf("AsAppError(wrapped) = %d %q, expected code 5 and the wrapped message", fromWrapped.Code, fromWrapped.Error())
+	}
+	if !errors.Is(fromWrapped, wrapped) || !errors.Is(fromWrapped, notFound) {
+		t.Error("AsAppError(wrapped) should keep the original error chain")
+	}
+	if fromPlain.Code != apperrors.ErrGeneral || fromPlain.Error() != "connection reset" {
+		t.Errorf("AsAppError(plain) = %d %q, expected code 1 and the message", fromPlain.Code, fromPlain.Error())
+	}
+	if apperrors.AsAppError(nil) != nil {
+		t.Error("AsAppError(nil) should be nil")
+	}
+}
+
 // =============================================================================
 // Edge Cases Tests
 // =============================================================================
@@ -73,6 +73,39 @@ func NewRepositoryNotFoundError(owner, repo string) *AppError {
 	}
 }
 
+// NewOrganizationNotFoundError creates an AppError for organization not found.
+//
+// This error type is used when an organization doesn't exist or cannot be accessed.
+// Maps to exit code 5 (ErrRepositoryNotFound), as no repositories can be resolved.
+//
+// Example: NewOrganizationNotFoundError("acme")
+func NewOrganizationNotFoundError(org string) *AppError {
+	message := fmt.Sprintf("Organization not found: %s. Ensure the organization exists and you are a member of it", org)
+
+	return &AppError{
+		Code:    ErrRepositoryNotFound,
+		Message: message,
+		Cause:   nil,
+	}
+}
+
+// NewEnterpriseNotFoundError creates an AppError for enterprise not found.
+//
+// This error type is used when an enterprise doesn't exist or its organizations
+// cannot be listed with the token. Maps to exit code 5 (ErrRepositoryNotFound),
+// as no repositories can be resolved.
+//
+// Example: NewEnterpriseNotFoundError("acme-corp")
+func NewEnterpriseNotFoundError(slug string) *AppError {
+	message := fmt.Sprintf("Enterprise not found: %s. Ensure the slug is correct and the token has the read:enterprise scope", slug)
+
+	return &AppError{
+		Code:    ErrRepositoryNotFound,
+		Message: message,
+		Cause:   nil,
+	}
+}
+
 // NewRateLimitError creates an AppError for API rate limit exceeded.
 //
 // This error type is used when the GitHub API rate limit is exceeded.
@@ -91,6 +124,26 @@ func NewRateLimitError(resetTime time.Time) *AppError {
 	}
 }
 
+// NewSecondaryRateLimitError creates an AppError for GitHub's secondary (abuse) rate limit.
+//
+// This error type is used when GitHub throttles bursts of requests regardless of
+// the remaining budget. retryAfter is the wait GitHub asked for, or zero if unknown.
+// Maps to exit code 6 (ErrAPIRateLimited).
+//
+// Example: NewSecondaryRateLimitError(60 * time.Second)
+func NewSecondaryRateLimitError(retryAfter time.Duration) *AppError {
+	message := "GitHub secondary rate limit exceeded. Reduce --concurrency or try again later"
+	if retryAfter > 0 {
+		message = fmt.Sprintf("GitHub secondary rate limit exceeded; retry after %s. Reduce --concurrency or try again later", retryAfter)
+	}
+
+	return &AppError{
+		Code:    ErrAPIRateLimited,
+		Message: message,
+		Cause:   nil,
+	}
+}
+
 // NewNetworkError creates an AppError for network connection failures.
 //
 // This error type is used for network-related errors (connection refused, timeout, etc.).
@@ -122,3 +175,82 @@ func NewAPIError(message string, cause error) *AppError {
 		Cause:   cause,
 	}
 }
+
+// NewVerificationError creates an AppError for a setting that did not hold its
+// requested value when read back after an update.
+//
+// Maps to exit code 1 (ErrGeneral).
+//
+// Example: NewVerificationError("acme/api", "feature was not enabled")
+func NewVerificationError(fullName, message string) *AppError {
+	return &AppError{
+		Code:    ErrGeneral,
+		Message: fmt.Sprintf("Verification failed for %s: %s", fullName, message),
+		Cause:   nil,
+	}
+}
+
+// NewBatchError creates an AppError summarizing failures in a multi-repository run.
+//
+// This error type is returned after every repository has been processed.
+// It carries the given code, the most severe among the failures, so the process
+// exit code reflects them.
+//
+// Example: NewBatchError(2, 150, ErrRepositoryNotFound)
+func NewBatchError(failed, total int, code ErrorCode) *AppError {
+	message := fmt.Sprintf("%d of %d repositories failed", failed, total)
+
+	return &AppError{
+		Code:    code,
+		Message: message,
+		Cause:   nil,
+	}
+}
+
+// NewListingError creates an AppError summarizing the organizations of an
+// enterprise run whose repositories could not be listed.
+//
+// The error carries the given code, the most severe among the listing failures
+// and the cause, so the process exit code reflects them. The cause is the
+// error of the repositories that were listed, or nil if they all succeeded.
+//
+// Example: NewListingError(1, 12, ErrInsufficientPerms, nil)
+func NewListingError(failed, total int, code ErrorCode, cause error) *AppError {
+	message := fmt.Sprintf("%d of %d organizations could not be listed", failed, total)
+
+	return &AppError{
+		Code:    code,
+		Message: message,
+		Cause:   cause,
+	}
+}
+
+// NewDriftError creates an AppError for an audit that found non-compliant repositories.
+//
+// This error type is returned after every repository has been checked, so a
+// pipeline can fail on drift. Maps to exit code 7 (ErrDriftDetected).
+//
+// Example: NewDriftError(3, 150)
+func NewDriftError(nonCompliant, total int) *AppError {
+	message := fmt.Sprintf("%d of %d repositories are non-compliant", nonCompliant, total)
+
+	return &AppError{
+		Code:    ErrDriftDetected,
+		Message: message,
+		Cause:   nil,
+	}
+}
+
+// NewCancelledError creates an AppError for a repository that was not processed
+// because the run was cancelled (e.g., by Ctrl-C).
+//
+// Maps to exit code 1 (ErrGeneral).
+//
+// Example: NewCancelledError()
+func NewCancelledError() *AppError {
+	return &AppError{
+		Code:    ErrGeneral,
+		Message: "Cancelled before the repository was processed",
+		Cause:   nil,
+	}
+}
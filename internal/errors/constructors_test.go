@@ -444,6 +444,151 @@ func TestNewAPIError(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// NewOrganizationNotFoundError Tests
+// =============================================================================
+
+// TestNewOrganizationNotFoundError verifies the organization error maps to exit code 5.
+func TestNewOrganizationNotFoundError(t *testing.T) {
+	// Act
+	err := apperrors.NewOrganizationNotFoundError("acme")
+
+	// Assert
+	if err.Code != apperrors.ErrRepositoryNotFound {
+		t.Errorf("Code = %v, expected %v", err.Code, apperrors.ErrRepositoryNotFound)
+	}
+	if !strings.Contains(err.Message, "Organization not found: acme") {
+		t.Errorf("Message = %q, should name the organization", err.Message)
+	}
+}
+
+// =============================================================================
+// NewBatchError Tests
+// =============================================================================
+
+// TestNewBatchError verifies NewBatchError summarizes failures with the given code.
+func TestNewBatchError(t *testing.T) {
+	tests := []struct {
+		name            string
+		failed          int
+		total           int
+		code            apperrors.ErrorCode
+		expectedMessage string
+	}{
+		{
+			name:            "single failure keeps its code",
+			failed:          1,
+			total:           3,
+			code:            apperrors.ErrRepositoryNotFound,
+			expectedMessage: "1 of 3 repositories failed",
+		},
+		{
+			name:            "mixed failures use general code",
+			failed:          2,
+			total:           150,
+			code:            apperrors.ErrGeneral,
+			expectedMessage: "2 of 150 repositories failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := apperrors.NewBatchError(tt.failed, tt.total, tt.code)
+
+			// Assert
+			if err.Code != tt.code {
+				t.Errorf("Code = %v, expected %v", err.Code, tt.code)
+			}
+			if err.Message != tt.expectedMessage {
+				t.Errorf("Message = %q, expected %q", err.Message, tt.expectedMessage)
+			}
+			if apperrors.GetExitCode(err) != int(tt.code) {
+				t.Errorf("GetExitCode() = %d, expected %d", apperrors.GetExitCode(err), tt.code)
+			}
+		})
+	}
+}
+
+// TestNewListingError verifies NewListingError counts the organizations that
+// could not be listed and keeps the repository error as its cause.
+func TestNewListingError(t *testing.T) {
+	// Arrange
+	cause := apperrors.NewBatchError(2, 40, apperrors.ErrRepositoryNotFound)
+
+	// Act
+	alone := apperrors.NewListingError(1, 3, apperrors.ErrInsufficientPerms, nil)
+	withCause := apperrors.NewListingError(1, 3, apperrors.ErrInsufficientPerms, cause)
+
+	// Assert
+	if apperrors.GetExitCode(alone) != 4 || alone.Error() != "1 of 3 organizations could not be listed" {
+		t.Errorf("error = %q (exit code %d), expected the organization count with exit code 4", alone.Error(), apperrors.GetExitCode(alone))
+	}
+	if withCause.Error() != "1 of 3 organizations could not be listed: 2 of 40 repositories failed" {
+		t.Errorf("Error() = %q, expected the repository failures after the organization count", withCause.Error())
+	}
+}
+
+// TestNewDriftError verifies NewDriftError maps to the dedicated drift exit code.
+func TestNewDriftError(t *testing.T) {
+	// Act
+	err := apperrors.NewDriftError(3, 150)
+
+	// Assert
+	if err.Code != apperrors.ErrDriftDetected {
+		t.Errorf("Code = %v, expected %v", err.Code, apperrors.ErrDriftDetected)
+	}
+	if err.Message != "3 of 150 repositories are non-compliant" {
+		t.Errorf("Message = %q, expected %q", err.Message, "3 of 150 repositories are non-compliant")
+	}
+	if apperrors.GetExitCode(err) != 7 {
+		t.Errorf("GetExitCode() = %d, expected 7", apperrors.GetExitCode(err))
+	}
+}
+
+// TestNewSecondaryRateLimitError verifies secondary rate limits map to exit code 6
+// with a message distinct from permission errors.
+func TestNewSecondaryRateLimitError(t *testing.T) {
+	// Act
+	err := apperrors.NewSecondaryRateLimitError(30 * time.Second)
+
+	// Assert
+	if apperrors.GetExitCode(err) != 6 {
+		t.Errorf("GetExitCode() = %d, expected 6", apperrors.GetExitCode(err))
+	}
+	if !strings.Contains(err.Message, "secondary rate limit") || !strings.Contains(err.Message, "30s") {
+		t.Errorf("Message = %q, expected it to name the secondary rate limit and the delay", err.Message)
+	}
+}
+
+// TestNewCancelledError verifies cancelled repositories map to exit code 1.
+func TestNewCancelledError(t *testing.T) {
+	// Act
+	err := apperrors.NewCancelledError()
+
+	// Assert
+	if err.Code != apperrors.ErrGeneral {
+		t.Errorf("Code = %v, expected %v", err.Code, apperrors.ErrGeneral)
+	}
+	if apperrors.GetExitCode(err) != 1 {
+		t.Errorf("GetExitCode() = %d, expected 1", apperrors.GetExitCode(err))
+	}
+}
+
+// TestNewVerificationError verifies a setting that did not hold maps to exit code 1.
+func TestNewVerificationError(t *testing.T) {
+	// Act
+	err := apperrors.NewVerificationError("acme/api", "feature was not enabled")
+
+	// Assert
+	if err.Code != apperrors.ErrGeneral {
+		t.Errorf("Code = %v, expected %v", err.Code, apperrors.ErrGeneral)
+	}
+	if err.Error() != "Verification failed for acme/api: feature was not enabled" {
+		t.Errorf("Error() = %q, expected the repository and the mismatch", err.Error())
+	}
+}
+
 // =============================================================================
 // Error Message Quality Tests
 // =============================================================================
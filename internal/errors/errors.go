@@ -20,13 +20,18 @@ type AppError struct {
 
 // Error implements the error interface and returns a formatted error message.
 //
-// If there is a cause, it includes the cause in the error message.
+// If there is a cause, it includes the cause in the error message, or returns
+// just the cause's message when there is no message of its own.
 // Otherwise, it returns just the message.
 func (e *AppError) Error() string {
-	if e.Cause != nil {
+	switch {
+	case e.Cause == nil:
+		return e.Message
+	case e.Message == "":
+		return e.Cause.Error()
+	default:
 		return fmt.Sprintf("%s: %v", e.Message, e.Cause)
 	}
-	return e.Message
 }
 
 // Unwrap returns the underlying cause error, enabling Go's error unwrapping.
@@ -0,0 +1,89 @@
+// Package github provides conditional requests backed by a response cache.
+//
+// When a response cache is set with WithResponseCache, successful GET responses
+// are stored with their ETag and Last-Modified headers. Later GETs of the same
+// URL send If-None-Match / If-Modified-Since, and a 304 Not Modified answer is
+// served from the cache. GitHub does not count 304 responses against the
+// primary rate limit, so repeated sweeps cost far less quota.
+package github
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// WithResponseCache enables conditional GET requests backed by cache.
+func WithResponseCache(cache interfaces.IResponseCache) ClientOption {
+	return func(c *GitHubClient) {
+		c.cache = cache
+	}
+}
+
+// CacheHits returns the number of responses served from the response cache
+// after a 304 Not Modified answer.
+func (c *GitHubClient) CacheHits() int64 {
+	return atomic.LoadInt64(&c.cacheHits)
+}
+
+// conditionalRequest makes req conditional if a cached response exists for it.
+// Only GET requests are cached. Returns the cache key ("" if the request is not
+// cacheable) and the cached response, if any.
+func (c *GitHubClient) conditionalRequest(req *http.Request, apiToken string) (string, interfaces.CachedResponse, bool) {
+	if c.cache == nil || req.Method != http.MethodGet {
+		return "", interfaces.CachedResponse{}, false
+	}
+
+	key := responseCacheKey(apiToken, req.URL.String())
+	cached, ok := c.cache.Get(key)
+	if !ok || cached.URL != req.URL.String() {
+		return key, interfaces.CachedResponse{}, false
+	}
+
+	if cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+	if cached.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cached.LastModified)
+	}
+	return key, cached, true
+}
+
+// restoreCachedResponse prepares a 304 response for the response handlers by
+// adding the cached headers it does not carry itself (e.g., Link).
+func (c *GitHubClient) restoreCachedResponse(resp *http.Response, cached interfaces.CachedResponse) {
+	atomic.AddInt64(&c.cacheHits, 1)
+	for name, values := range cached.Header {
+		if resp.Header.Get(name) == "" {
+			resp.Header[name] = values
+		}
+	}
+}
+
+// storeResponse caches a successful response that can be revalidated.
+// Cache write failures are ignored; the next request is simply unconditional.
+func (c *GitHubClient) storeResponse(key string, resp *http.Response, body []byte) {
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return
+	}
+
+	_ = c.cache.Set(key, interfaces.CachedResponse{
+		URL:          resp.Request.URL.String(),
+		ETag:         etag,
+		LastModified: lastModified,
+		Header:       resp.Header,
+		Body:         body,
+	})
+}
+
+// responseCacheKey keys a cached response by URL and by a hash of the token, so
+// responses are never shared between accounts and the token is never stored.
+func responseCacheKey(apiToken, url string) string {
+	sum := sha256.Sum256([]byte(apiToken))
+	return hex.EncodeToString(sum[:]) + " " + url
+}
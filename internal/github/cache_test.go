@@ -0,0 +1,145 @@
+// Package github_test provides tests for conditional requests.
+//
+// These tests verify that, with a response cache, the client revalidates GET
+// requests with their ETag, serves 304 Not Modified answers from the cache
+// (including headers such as Link), keeps responses of different tokens apart
+// and does not charge 304 answers to the rate-limit budget.
+package github_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/josejulio/ghautodelete/internal/github"
+	"github.com/josejulio/ghautodelete/internal/httpcache"
+)
+
+// newConditionalServer returns a server answering two pages of repositories
+// with an ETag per page, and 304 when the request carries the matching ETag.
+func newConditionalServer(notModified *int32) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"page1"`
+		body := `[{"name":"api","full_name":"acme/api","owner":{"login":"acme"}}]`
+		if r.URL.Query().Get("page") == "2" {
+			etag = `"page2"`
+			body = `[{"name":"web","full_name":"acme/web","owner":{"login":"acme"}}]`
+		} else {
+			w.Header().Set("Link", `<`+server.URL+`/orgs/acme/repos?page=2>; rel="next"`)
+		}
+
+		if r.Header.Get("If-None-Match") == etag {
+			atomic.AddInt32(notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(body))
+	}))
+	return server
+}
+
+// TestClientServesNotModifiedFromCache verifies a repeated listing revalidates
+// every page and decodes the 304 answers from the cache, following Link headers.
+func TestClientServesNotModifiedFromCache(t *testing.T) {
+	// Arrange
+	var notModified int32
+	server := newConditionalServer(&notModified)
+	defer server.Close()
+	cache := httpcache.NewDiskCache(t.TempDir())
+	client := github.NewGitHubClient(server.Client(), server.URL, "test-token", github.WithResponseCache(cache))
+
+	// Act
+	first, err1 := client.ListOrganizationRepositories(context.Background(), "acme")
+	second, err2 := client.ListOrganizationRepositories(context.Background(), "acme")
+
+	// Assert
+	if err1 != nil || err2 != nil {
+		t.Fatalf("ListOrganizationRepositories() errors = %v, %v", err1, err2)
+	}
+	if len(first) != 2 || len(second) != 2 {
+		t.Fatalf("expected 2 repositories on both runs, got %d and %d", len(first), len(second))
+	}
+	if second[1].GetFullName() != "acme/web" {
+		t.Errorf("second run page 2 = %q, expected acme/web", second[1].GetFullName())
+	}
+	if n := atomic.LoadInt32(&notModified); n != 2 {
+		t.Errorf("expected 2 not-modified answers, got %d", n)
+	}
+	if hits := client.CacheHits(); hits != 2 {
+		t.Errorf("CacheHits() = %d, expected 2", hits)
+	}
+}
+
+// TestClientCacheIsKeyedByToken verifies a response cached for one token is not
+// revalidated with another token.
+func TestClientCacheIsKeyedByToken(t *testing.T) {
+	// Arrange
+	var notModified int32
+	server := newConditionalServer(&notModified)
+	defer server.Close()
+	cache := httpcache.NewDiskCache(t.TempDir())
+	first := github.NewGitHubClient(server.Client(), server.URL, "token-a", github.WithResponseCache(cache))
+	second := github.NewGitHubClient(server.Client(), server.URL, "token-b", github.WithResponseCache(cache))
+
+	// Act
+	_, err1 := first.ListOrganizationRepositories(context.Background(), "acme")
+	repos, err2 := second.ListOrganizationRepositories(context.Background(), "acme")
+
+	// Assert
+	if err1 != nil || err2 != nil {
+		t.Fatalf("ListOrganizationRepositories() errors = %v, %v", err1, err2)
+	}
+	if len(repos) != 2 {
+		t.Errorf("expected 2 repositories, got %d", len(repos))
+	}
+	if n := atomic.LoadInt32(&notModified); n != 0 || second.CacheHits() != 0 {
+		t.Errorf("expected no cache hits across tokens, got %d not-modified answers", n)
+	}
+}
+
+// TestClientNotModifiedDoesNotUseRateLimitBudget verifies revalidations answered
+// with 304, which GitHub does not count, leave the local budget at what GitHub
+// reports instead of running it down to a rate-limit error.
+func TestClientNotModifiedDoesNotUseRateLimitBudget(t *testing.T) {
+	// Arrange
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "15")
+		w.Header().Set("X-RateLimit-Reset", reset)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`[{"name":"api","full_name":"acme/api","owner":{"login":"acme"}}]`))
+	}))
+	defer server.Close()
+	noSleep := func(ctx context.Context, d time.Duration) error { return nil }
+	limiter := github.NewRateLimiter(false, github.WithRateLimitClock(time.Now, noSleep))
+	client := github.NewGitHubClient(server.Client(), server.URL, "test-token",
+		github.WithResponseCache(httpcache.NewDiskCache(t.TempDir())), github.WithRateLimiter(limiter))
+
+	// Act
+	var err error
+	for i := 0; i < 50 && err == nil; i++ {
+		_, err = client.ListOrganizationRepositories(context.Background(), "acme")
+	}
+
+	// Assert
+	if err != nil {
+		t.Fatalf("ListOrganizationRepositories() error = %v, expected cached revalidations to stay within budget", err)
+	}
+	if budget, _ := limiter.Budget("core"); budget.Remaining != 15 {
+		t.Errorf("local remaining = %d, expected the 15 GitHub reports", budget.Remaining)
+	}
+	if hits := client.CacheHits(); hits != 49 {
+		t.Errorf("CacheHits() = %d, expected 49", hits)
+	}
+}
@@ -3,18 +3,21 @@
 // This package implements the IGitHubClient interface and handles:
 // - Repository retrieval and updates
 // - Token validation
-// - Error mapping (401->3, 403->4/6, 404->5, 5xx->1)
+// - Error mapping (401->3, 403->4/6, 404->5, 429->6, 5xx->1)
 // - Retry logic for 5xx errors
-// - Rate limit handling
+// - Rate limit handling and pacing (see RateLimiter)
 package github
 
 import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -27,26 +30,113 @@ import (
 const (
 	maxRetries     = 3
 	retryBaseDelay = 500 * time.Millisecond
+
+	// listPageSize is the number of items requested per page for list endpoints (GitHub's maximum).
+	listPageSize = 100
 )
 
+// DefaultHostname is the hostname of github.com.
+const DefaultHostname = "github.com"
+
+// linkNextPattern matches the rel="next" entry of a Link response header.
+var linkNextPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// installationTokenPrefix marks GitHub App installation access tokens.
+const installationTokenPrefix = "ghs_"
+
+// installationUsername is reported by ValidateToken for installation tokens, which have no user.
+const installationUsername = "GitHub App installation"
+
 // GitHubClient implements the IGitHubClient interface for GitHub API operations.
 type GitHubClient struct {
 	httpClient *http.Client
 	baseURL    string
-	token      string
+	tokens     interfaces.ITokenProvider
+	limiter    interfaces.IRateLimiter
+	cache      interfaces.IResponseCache
+	cacheHits  int64
+}
+
+// ClientOption configures optional GitHubClient behavior.
+type ClientOption func(*GitHubClient)
+
+// WithRateLimiter sets the rate limiter that paces requests. By default a
+// RateLimiter that fails fast on an exhausted budget is used.
+func WithRateLimiter(limiter interfaces.IRateLimiter) ClientOption {
+	return func(c *GitHubClient) {
+		c.limiter = limiter
+	}
+}
+
+// staticToken is an ITokenProvider that always returns the same token.
+type staticToken string
+
+// GetToken returns the token.
+func (t staticToken) GetToken() (string, error) {
+	return string(t), nil
+}
+
+// token returns the API token, passing ctx to providers that make a request for it.
+func (c *GitHubClient) token(ctx context.Context) (string, error) {
+	if provider, ok := c.tokens.(interfaces.IContextTokenProvider); ok {
+		return provider.GetTokenContext(ctx)
+	}
+	return c.tokens.GetToken()
 }
 
 // NewGitHubClient creates a new GitHubClient instance.
 // Parameters:
 //   - httpClient: the HTTP client to use for API requests
-//   - baseURL: the base URL for the GitHub API (e.g., "https://api.github.com")
+//   - baseURL: the base URL for the GitHub API (e.g., "https://api.github.com", see BaseURLForHostname)
 //   - token: the GitHub API token for authentication
-func NewGitHubClient(httpClient *http.Client, baseURL string, token string) *GitHubClient {
-	return &GitHubClient{
+//   - opts: optional behavior, e.g. WithRateLimiter
+func NewGitHubClient(httpClient *http.Client, baseURL string, token string, opts ...ClientOption) *GitHubClient {
+	return NewGitHubClientWithTokenProvider(httpClient, baseURL, staticToken(token), opts...)
+}
+
+// NewGitHubClientWithTokenProvider creates a new GitHubClient instance that asks
+// tokens for the token before every request, so expiring tokens (e.g., GitHub App
+// installation tokens) are refreshed transparently.
+// Parameters are as for NewGitHubClient.
+func NewGitHubClientWithTokenProvider(
+	httpClient *http.Client,
+	baseURL string,
+	tokens interfaces.ITokenProvider,
+	opts ...ClientOption,
+) *GitHubClient {
+	c := &GitHubClient{
 		httpClient: httpClient,
 		baseURL:    baseURL,
-		token:      token,
+		tokens:     tokens,
+		limiter:    NewRateLimiter(false),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// RateLimit returns the most recently reported budget of the core REST API
+// rate limit, and whether any response has reported one yet.
+func (c *GitHubClient) RateLimit() (interfaces.RateLimitBudget, bool) {
+	return c.limiter.Budget(resourceCore)
+}
+
+// GraphQLRateLimit returns the most recently reported budget of the GraphQL API
+// rate limit, and whether any response has reported one yet.
+func (c *GitHubClient) GraphQLRateLimit() (interfaces.RateLimitBudget, bool) {
+	return c.limiter.Budget(resourceGraphQL)
+}
+
+// BaseURLForHostname returns the REST API base URL for a GitHub hostname.
+// github.com is served from https://api.github.com; GitHub Enterprise Server
+// serves the API under https://{hostname}/api/v3.
+func BaseURLForHostname(hostname string) string {
+	hostname = strings.ToLower(hostname)
+	if hostname == DefaultHostname {
+		return "https://api.github.com"
+	}
+	return fmt.Sprintf("https://%s/api/v3", hostname)
 }
 
 // GetRepository retrieves repository information from GitHub.
@@ -68,16 +158,61 @@ func (c *GitHubClient) GetRepository(ctx context.Context, owner, name string) (i
 func (c *GitHubClient) UpdateRepository(ctx context.Context, owner, name string, settings interfaces.IRepositorySettings) error {
 	url := fmt.Sprintf("%s/repos/%s/%s", c.baseURL, owner, name)
 
-	body := map[string]interface{}{
-		"delete_branch_on_merge": settings.GetDeleteBranchOnMerge(),
+	return c.doRequestWithRetry(ctx, http.MethodPatch, url, settingsBody(settings), nil)
+}
+
+// settingsBody builds the PATCH /repos/{owner}/{repo} body.
+// Only settings that are explicitly set are included, so unset fields keep their current value.
+func settingsBody(settings interfaces.IRepositorySettings) map[string]interface{} {
+	body := map[string]interface{}{}
+	if settings.HasDeleteBranchOnMerge() {
+		body["delete_branch_on_merge"] = settings.GetDeleteBranchOnMerge()
 	}
 
-	return c.doRequestWithRetry(ctx, http.MethodPatch, url, body, nil)
+	optionalBools := map[string]*bool{
+		"allow_squash_merge":  settings.GetAllowSquashMerge(),
+		"allow_merge_commit":  settings.GetAllowMergeCommit(),
+		"allow_rebase_merge":  settings.GetAllowRebaseMerge(),
+		"allow_auto_merge":    settings.GetAllowAutoMerge(),
+		"allow_update_branch": settings.GetAllowUpdateBranch(),
+	}
+	for key, value := range optionalBools {
+		if value != nil {
+			body[key] = *value
+		}
+	}
+
+	optionalStrings := map[string]*string{
+		"squash_merge_commit_title":   settings.GetSquashMergeCommitTitle(),
+		"squash_merge_commit_message": settings.GetSquashMergeCommitMessage(),
+	}
+	for key, value := range optionalStrings {
+		if value != nil {
+			body[key] = *value
+		}
+	}
+
+	return body
 }
 
 // ValidateToken validates the GitHub API token and returns token information.
 // Returns ITokenInfo containing scopes and user details.
+//
+// GitHub App installation tokens have no user, so they are validated against
+// GET /installation/repositories and reported with no scopes.
 func (c *GitHubClient) ValidateToken(ctx context.Context) (interfaces.ITokenInfo, error) {
+	apiToken, err := c.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(apiToken, installationTokenPrefix) {
+		url := fmt.Sprintf("%s/installation/repositories?per_page=1", c.baseURL)
+		if err := c.doRequestWithRetry(ctx, http.MethodGet, url, nil, nil); err != nil {
+			return nil, err
+		}
+		return token.NewTokenInfo(installationUsername, nil), nil
+	}
+
 	url := fmt.Sprintf("%s/user", c.baseURL)
 
 	var response struct {
@@ -85,7 +220,7 @@ func (c *GitHubClient) ValidateToken(ctx context.Context) (interfaces.ITokenInfo
 	}
 
 	var scopes []string
-	err := c.doRequestWithRetry(ctx, http.MethodGet, url, nil, &response, func(resp *http.Response) {
+	err = c.doRequestWithRetry(ctx, http.MethodGet, url, nil, &response, func(resp *http.Response) {
 		// Parse scopes from X-OAuth-Scopes header
 		scopesHeader := resp.Header.Get("X-OAuth-Scopes")
 		if scopesHeader != "" {
@@ -107,8 +242,97 @@ func (c *GitHubClient) ValidateToken(ctx context.Context) (interfaces.ITokenInfo
 	return tokenInfo, nil
 }
 
-// doRequestWithRetry executes an HTTP request with retry logic for 5xx errors.
-// It handles error mapping and response parsing.
+// ListOrganizationRepositories lists every repository of an organization.
+// It requests GET /orgs/{org}/repos and follows Link rel="next" headers until the last page.
+func (c *GitHubClient) ListOrganizationRepositories(ctx context.Context, org string) ([]interfaces.IRepository, error) {
+	url := fmt.Sprintf("%s/orgs/%s/repos?type=all&per_page=%d", c.baseURL, org, listPageSize)
+	return c.listRepositories(ctx, url)
+}
+
+// ListUserRepositories lists the repositories of the authenticated user.
+// It requests GET /user/repos filtered by affiliation and follows Link rel="next" headers.
+func (c *GitHubClient) ListUserRepositories(ctx context.Context, affiliation string) ([]interfaces.IRepository, error) {
+	url := fmt.Sprintf("%s/user/repos?affiliation=%s&per_page=%d", c.baseURL, affiliation, listPageSize)
+	return c.listRepositories(ctx, url)
+}
+
+// SearchRepositories lists the repositories matching a search query.
+// It requests GET /search/repositories and follows Link rel="next" headers;
+// GitHub stops paginating after 1,000 results, while total reports every match.
+func (c *GitHubClient) SearchRepositories(ctx context.Context, query string) ([]interfaces.IRepository, int, error) {
+	var repos []interfaces.IRepository
+	var total int
+	seen := make(map[string]bool)
+
+	pageURL := fmt.Sprintf("%s/search/repositories?q=%s&per_page=%d", c.baseURL, url.QueryEscape(query), listPageSize)
+	for pageURL != "" {
+		var page struct {
+			TotalCount int           `json:"total_count"`
+			Items      []*Repository `json:"items"`
+		}
+		var next string
+		err := c.doRequestWithRetry(ctx, http.MethodGet, pageURL, nil, &page, func(resp *http.Response) {
+			next = parseNextLink(resp.Header.Get("Link"))
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+
+		// Results can shift between pages while paginating; keep each repository once
+		total = page.TotalCount
+		for _, repo := range page.Items {
+			if !seen[repo.GetFullName()] {
+				seen[repo.GetFullName()] = true
+				repos = append(repos, repo)
+			}
+		}
+		pageURL = next
+	}
+
+	return repos, total, nil
+}
+
+// ListEnterpriseOrganizations lists the member organizations of an enterprise.
+// The REST API has no such listing, so the GraphQL API is used.
+func (c *GitHubClient) ListEnterpriseOrganizations(ctx context.Context, slug string) ([]string, error) {
+	return NewGraphQLClient(c).ListEnterpriseOrganizations(ctx, slug)
+}
+
+// listRepositories retrieves a paginated repository list starting at url.
+func (c *GitHubClient) listRepositories(ctx context.Context, url string) ([]interfaces.IRepository, error) {
+	var repos []interfaces.IRepository
+
+	for url != "" {
+		var page []*Repository
+		var next string
+		err := c.doRequestWithRetry(ctx, http.MethodGet, url, nil, &page, func(resp *http.Response) {
+			next = parseNextLink(resp.Header.Get("Link"))
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, repo := range page {
+			repos = append(repos, repo)
+		}
+		url = next
+	}
+
+	return repos, nil
+}
+
+// parseNextLink extracts the rel="next" URL from a Link header.
+// Returns an empty string when there is no next page.
+func parseNextLink(header string) string {
+	matches := linkNextPattern.FindStringSubmatch(header)
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
+
+// doRequestWithRetry executes an HTTP request with retry logic for 5xx errors
+// and secondary rate limits. It handles error mapping and response parsing.
 func (c *GitHubClient) doRequestWithRetry(
 	ctx context.Context,
 	method, url string,
@@ -117,6 +341,7 @@ func (c *GitHubClient) doRequestWithRetry(
 	responseHandlers ...func(*http.Response),
 ) error {
 	var lastErr error
+	secondaryRetries := 0
 
 	for attempt := 0; attempt < maxRetries; attempt++ {
 		if attempt > 0 {
@@ -136,6 +361,16 @@ func (c *GitHubClient) doRequestWithRetry(
 
 		lastErr = err
 
+		// A secondary rate limit pauses every request for the delay GitHub asked
+		// for, then retries without using up an attempt
+		var secondary *secondaryRateLimitError
+		if errors.As(err, &secondary) && secondaryRetries < maxSecondaryRetries {
+			c.limiter.Pause(secondaryRetryDelay(secondary, secondaryRetries))
+			secondaryRetries++
+			attempt--
+			continue
+		}
+
 		// Check if error is retryable (5xx)
 		// We retry on server errors (ErrGeneral with Code 1) but not on other errors
 		// Network errors and server errors both map to ErrGeneral
@@ -144,6 +379,11 @@ func (c *GitHubClient) doRequestWithRetry(
 			continue
 		}
 
+		// An exhausted primary rate limit is retried only if the limiter waited for the reset
+		if apperrors.GetExitCode(err) == 6 && secondary == nil && c.limiter.Wait(ctx, rateLimitResource(url)) == nil {
+			continue
+		}
+
 		// Don't retry on 4xx errors
 		return lastErr
 	}
@@ -173,13 +413,24 @@ func (c *GitHubClient) doRequest(
 		return apperrors.NewNetworkError(err)
 	}
 
+	apiToken, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	resource := rateLimitResource(url)
+	if err := c.limiter.Wait(ctx, resource); err != nil {
+		return err
+	}
+
 	// Set headers
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiToken))
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 	req.Header.Set("User-Agent", "ghautodelete")
 	if bodyData != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	cacheKey, cached, revalidating := c.conditionalRequest(req, apiToken)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -187,6 +438,20 @@ func (c *GitHubClient) doRequest(
 	}
 	defer resp.Body.Close()
 
+	if budget, ok := parseRateLimit(resp, resource); ok {
+		c.limiter.Update(budget)
+	}
+
+	if revalidating && resp.StatusCode == http.StatusNotModified {
+		// GitHub does not count a 304 against the rate limit
+		c.limiter.Release(resource)
+		c.restoreCachedResponse(resp, cached)
+		for _, handler := range responseHandlers {
+			handler(resp)
+		}
+		return decodeResponse(cached.Body, result)
+	}
+
 	// Execute response handlers (for extracting headers, etc.)
 	for _, handler := range responseHandlers {
 		handler(resp)
@@ -194,12 +459,21 @@ func (c *GitHubClient) doRequest(
 
 	// Handle HTTP status codes
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		if result != nil {
-			if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-				return apperrors.NewAPIError("failed to decode response", err)
+		if cacheKey == "" {
+			if result != nil {
+				if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+					return apperrors.NewAPIError("failed to decode response", err)
+				}
 			}
+			return nil
 		}
-		return nil
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return apperrors.NewNetworkError(err)
+		}
+		c.storeResponse(cacheKey, resp, body)
+		return decodeResponse(body, result)
 	}
 
 	// Read response body for error messages
@@ -210,27 +484,43 @@ func (c *GitHubClient) doRequest(
 	case http.StatusUnauthorized:
 		return apperrors.NewAuthenticationError("authentication failed", nil)
 
-	case http.StatusForbidden:
+	case http.StatusForbidden, http.StatusTooManyRequests:
 		// Check if it's a rate limit error
 		if c.isRateLimited(resp) {
 			resetTime := c.parseResetTime(resp)
 			return apperrors.NewRateLimitError(resetTime)
 		}
+		if resp.StatusCode == http.StatusTooManyRequests || isSecondaryRateLimit(resp, bodyBytes) {
+			return newSecondaryRateLimitError(resp)
+		}
 		// Otherwise it's a permissions error
 		return apperrors.NewAuthorizationError("insufficient permissions")
 
+	case http.StatusUnprocessableEntity:
+		// An invalid search query will not succeed on retry
+		if resource == resourceSearch {
+			return apperrors.NewValidationError(fmt.Sprintf("Invalid search query: %s", validationMessage(bodyBytes)))
+		}
+		return apperrors.NewAPIError(
+			fmt.Sprintf("GitHub API error: %d %s", resp.StatusCode, string(bodyBytes)),
+			nil,
+		)
+
 	case http.StatusNotFound:
-		// Extract owner/repo from URL if possible
-		// URL format: /repos/{owner}/{repo}
+		// Extract owner/repo (or org) from URL if possible
+		// URL formats: /repos/{owner}/{repo}, /orgs/{org}/repos
 		owner := ""
 		repo := ""
-		parts := strings.Split(url, "/")
+		parts := strings.Split(strings.SplitN(url, "?", 2)[0], "/")
 		for i, part := range parts {
 			if part == "repos" && i+2 < len(parts) {
 				owner = parts[i+1]
 				repo = parts[i+2]
 				break
 			}
+			if part == "orgs" && i+1 < len(parts) {
+				return apperrors.NewOrganizationNotFoundError(parts[i+1])
+			}
 		}
 		return apperrors.NewRepositoryNotFoundError(owner, repo)
 
@@ -253,6 +543,37 @@ func (c *GitHubClient) doRequest(
 	}
 }
 
+// validationMessage extracts the most specific message of a 422 response body,
+// e.g., why a search query was rejected.
+func validationMessage(body []byte) string {
+	var response struct {
+		Message string `json:"message"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return strings.TrimSpace(string(body))
+	}
+	for _, e := range response.Errors {
+		if e.Message != "" {
+			return e.Message
+		}
+	}
+	return response.Message
+}
+
+// decodeResponse decodes a JSON response body into result, if result is non-nil.
+func decodeResponse(body []byte, result interface{}) error {
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return apperrors.NewAPIError("failed to decode response", err)
+	}
+	return nil
+}
+
 // isRateLimited checks if the response indicates a rate limit error.
 func (c *GitHubClient) isRateLimited(resp *http.Response) bool {
 	remaining := resp.Header.Get("X-RateLimit-Remaining")
@@ -269,24 +590,10 @@ func (c *GitHubClient) isRateLimited(resp *http.Response) bool {
 }
 
 // parseResetTime extracts the rate limit reset time from response headers.
+// Falls back to one hour from now if the header is missing or invalid.
 func (c *GitHubClient) parseResetTime(resp *http.Response) time.Time {
-	resetHeader := resp.Header.Get("X-RateLimit-Reset")
-	if resetHeader == "" {
-		return time.Now().Add(1 * time.Hour)
-	}
-
-	// Try to parse as Unix timestamp
-	resetUnix, err := strconv.ParseInt(resetHeader, 10, 64)
-	if err == nil {
-		return time.Unix(resetUnix, 0)
-	}
-
-	// Try to parse as RFC3339 timestamp
-	resetTime, err := time.Parse(time.RFC3339, resetHeader)
-	if err == nil {
+	if resetTime, ok := parseResetHeader(resp.Header.Get("X-RateLimit-Reset")); ok {
 		return resetTime
 	}
-
-	// Fallback: 1 hour from now
 	return time.Now().Add(1 * time.Hour)
 }
@@ -16,9 +16,12 @@ package github_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -70,6 +73,21 @@ func TestNewGitHubClientReturnsNonNil(t *testing.T) {
 	}
 }
 
+// TestBaseURLForHostname verifies github.com and GitHub Enterprise Server API endpoints.
+func TestBaseURLForHostname(t *testing.T) {
+	tests := map[string]string{
+		"github.com":       "https://api.github.com",
+		"GitHub.com":       "https://api.github.com",
+		"git.corp.example": "https://git.corp.example/api/v3",
+	}
+
+	for hostname, expected := range tests {
+		if got := github.BaseURLForHostname(hostname); got != expected {
+			t.Errorf("BaseURLForHostname(%q) = %q, expected %q", hostname, got, expected)
+		}
+	}
+}
+
 // =============================================================================
 // GetRepository Success Tests
 // =============================================================================
@@ -825,6 +843,80 @@ func TestValidateTokenSuccess(t *testing.T) {
 	}
 }
 
+// TestValidateTokenInstallationToken verifies GitHub App installation tokens,
+// which cannot call GET /user, are validated against the installation endpoint.
+func TestValidateTokenInstallationToken(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/installation/repositories" {
+			t.Errorf("Expected path /installation/repositories, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"total_count": 1, "repositories": []}`))
+	}))
+	defer server.Close()
+
+	client := github.NewGitHubClient(server.Client(), server.URL, "ghs_installation")
+
+	// Act
+	tokenInfo, err := client.ValidateToken(context.Background())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v, expected nil", err)
+	}
+	if len(tokenInfo.GetScopes()) != 0 {
+		t.Errorf("Scopes = %v, expected none for an installation token", tokenInfo.GetScopes())
+	}
+}
+
+// contextTokenProvider is an IContextTokenProvider that records the contexts it is asked with.
+type contextTokenProvider struct {
+	contexts []context.Context
+}
+
+func (p *contextTokenProvider) GetToken() (string, error) {
+	return p.GetTokenContext(context.Background())
+}
+
+func (p *contextTokenProvider) GetTokenContext(ctx context.Context) (string, error) {
+	p.contexts = append(p.contexts, ctx)
+	return "ghs_installation", nil
+}
+
+// TestClientPassesContextToTokenProvider verifies a token provider that makes
+// requests, such as a GitHub App, is asked for the token with the API call's context.
+func TestClientPassesContextToTokenProvider(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_count": 1, "repositories": []}`))
+	}))
+	defer server.Close()
+
+	type contextKey struct{}
+	ctx := context.WithValue(context.Background(), contextKey{}, "run")
+	tokens := &contextTokenProvider{}
+	client := github.NewGitHubClientWithTokenProvider(server.Client(), server.URL, tokens)
+
+	// Act
+	_, err := client.ValidateToken(ctx)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v, expected nil", err)
+	}
+	if len(tokens.contexts) == 0 {
+		t.Fatal("the token provider was not asked for a token")
+	}
+	for _, asked := range tokens.contexts {
+		if asked.Value(contextKey{}) != "run" {
+			t.Error("the token provider should be asked with the caller's context")
+		}
+	}
+}
+
 // TestValidateTokenAuthenticationFailed verifies 401 error handling.
 //
 // The implementation should:
@@ -1322,3 +1414,223 @@ func TestRetryBehaviorTable(t *testing.T) {
 		})
 	}
 }
+
+// =============================================================================
+// ListOrganizationRepositories Tests
+// =============================================================================
+
+// TestListOrganizationRepositoriesFollowsLinkHeader verifies pagination via Link headers.
+//
+// The implementation should:
+// - Send GET request to /orgs/{org}/repos with per_page=100
+// - Follow rel="next" links until the last page
+// - Return repositories from every page in order
+func TestListOrganizationRepositoriesFollowsLinkHeader(t *testing.T) {
+	// Arrange
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/orgs/acme/repos" {
+			t.Errorf("Expected path /orgs/acme/repos, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("per_page") != "100" {
+			t.Errorf("Expected per_page=100, got %q", r.URL.Query().Get("per_page"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			w.Header().Set("Link", `<`+server.URL+`/orgs/acme/repos?per_page=100&page=1>; rel="prev", <`+server.URL+`/orgs/acme/repos?per_page=100&page=1>; rel="first"`)
+			_, _ = w.Write([]byte(`[{"owner":{"login":"acme"},"name":"web","archived":true,"fork":true,"is_template":true}]`))
+			return
+		}
+		w.Header().Set("Link", `<`+server.URL+`/orgs/acme/repos?per_page=100&page=2>; rel="next", <`+server.URL+`/orgs/acme/repos?per_page=100&page=2>; rel="last"`)
+		_, _ = w.Write([]byte(`[{"owner":{"login":"acme"},"name":"api","default_branch":"main","delete_branch_on_merge":true},{"owner":{"login":"acme"},"name":"worker"}]`))
+	}))
+	defer server.Close()
+
+	client := github.NewGitHubClient(server.Client(), server.URL, "test-token")
+
+	// Act
+	repos, err := client.ListOrganizationRepositories(context.Background(), "acme")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("ListOrganizationRepositories() error = %v, expected nil", err)
+	}
+	if len(repos) != 3 {
+		t.Fatalf("expected 3 repositories across 2 pages, got %d", len(repos))
+	}
+	names := []string{repos[0].GetFullName(), repos[1].GetFullName(), repos[2].GetFullName()}
+	expected := []string{"acme/api", "acme/worker", "acme/web"}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("repository %d = %q, expected %q", i, names[i], expected[i])
+		}
+	}
+	if !repos[0].GetDeleteBranchOnMerge() {
+		t.Error("acme/api should have delete_branch_on_merge enabled")
+	}
+	if !repos[2].IsArchived() || !repos[2].IsFork() || !repos[2].IsTemplate() {
+		t.Error("acme/web should be reported as archived, fork and template")
+	}
+}
+
+// TestListOrganizationRepositoriesEmptyOrganization verifies an empty list is not an error.
+func TestListOrganizationRepositoriesEmptyOrganization(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := github.NewGitHubClient(server.Client(), server.URL, "test-token")
+
+	// Act
+	repos, err := client.ListOrganizationRepositories(context.Background(), "empty")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("ListOrganizationRepositories() error = %v, expected nil", err)
+	}
+	if len(repos) != 0 {
+		t.Errorf("expected no repositories, got %d", len(repos))
+	}
+}
+
+// TestListOrganizationRepositoriesNotFound verifies a 404 maps to an organization-not-found error.
+func TestListOrganizationRepositoriesNotFound(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+	}))
+	defer server.Close()
+
+	client := github.NewGitHubClient(server.Client(), server.URL, "test-token")
+
+	// Act
+	_, err := client.ListOrganizationRepositories(context.Background(), "missing-org")
+
+	// Assert
+	if apperrors.GetExitCode(err) != 5 {
+		t.Errorf("GetExitCode() = %d, expected 5 (err: %v)", apperrors.GetExitCode(err), err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "Organization not found: missing-org") {
+		t.Errorf("error should name the organization, got %v", err)
+	}
+}
+
+// =============================================================================
+// ListUserRepositories Tests
+// =============================================================================
+
+// TestListUserRepositoriesRequestsAffiliation verifies the /user/repos request and pagination.
+func TestListUserRepositoriesRequestsAffiliation(t *testing.T) {
+	// Arrange
+	var requests []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", `<`+server.URL+`/user/repos?affiliation=owner&per_page=100&page=2>; rel="next"`)
+			_, _ = w.Write([]byte(`[{"owner":{"login":"octocat"},"name":"dotfiles"}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"owner":{"login":"octocat"},"name":"blog","fork":true}]`))
+	}))
+	defer server.Close()
+
+	client := github.NewGitHubClient(server.Client(), server.URL, "test-token")
+
+	// Act
+	repos, err := client.ListUserRepositories(context.Background(), "owner")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("ListUserRepositories() error = %v, expected nil", err)
+	}
+	if len(requests) != 2 || requests[0] != "/user/repos?affiliation=owner&per_page=100" {
+		t.Errorf("unexpected requests: %v", requests)
+	}
+	if len(repos) != 2 || repos[0].GetFullName() != "octocat/dotfiles" || !repos[1].IsFork() {
+		t.Errorf("unexpected repositories: %+v", repos)
+	}
+}
+
+// =============================================================================
+// UpdateRepository Body Tests
+// =============================================================================
+
+// TestUpdateRepositorySendsOnlySetFields verifies the PATCH body contains only explicitly set settings.
+func TestUpdateRepositorySendsOnlySetFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings *github.RepositorySettings
+		expected map[string]interface{}
+	}{
+		{
+			name:     "delete branch on merge only",
+			settings: github.NewRepositorySettings(false),
+			expected: map[string]interface{}{"delete_branch_on_merge": false},
+		},
+		{
+			name: "full merge policy",
+			settings: &github.RepositorySettings{
+				DeleteBranchOnMerge:      github.Bool(true),
+				AllowSquashMerge:         github.Bool(true),
+				AllowMergeCommit:         github.Bool(false),
+				AllowRebaseMerge:         github.Bool(false),
+				AllowAutoMerge:           github.Bool(true),
+				AllowUpdateBranch:        github.Bool(true),
+				SquashMergeCommitTitle:   github.String(github.SquashMergeCommitTitlePRTitle),
+				SquashMergeCommitMessage: github.String(github.SquashMergeCommitMessagePRBody),
+			},
+			expected: map[string]interface{}{
+				"delete_branch_on_merge":      true,
+				"allow_squash_merge":          true,
+				"allow_merge_commit":          false,
+				"allow_rebase_merge":          false,
+				"allow_auto_merge":            true,
+				"allow_update_branch":         true,
+				"squash_merge_commit_title":   "PR_TITLE",
+				"squash_merge_commit_message": "PR_BODY",
+			},
+		},
+		{
+			name: "omitted delete branch on merge",
+			settings: &github.RepositorySettings{
+				AllowRebaseMerge: github.Bool(false),
+			},
+			expected: map[string]interface{}{"allow_rebase_merge": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var body map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("failed to decode body: %v", err)
+				}
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			client := github.NewGitHubClient(server.Client(), server.URL, "test-token")
+
+			// Act
+			err := client.UpdateRepository(context.Background(), "octocat", "hello-world", tt.settings)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("UpdateRepository() error = %v, expected nil", err)
+			}
+			if !reflect.DeepEqual(body, tt.expected) {
+				t.Errorf("PATCH body = %v, expected %v", body, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,462 @@
+// Package github provides a GraphQL backend for bulk repository reads.
+//
+// GraphQLClient implements the IGitHubClient interface on top of GitHubClient,
+// sharing its authentication, retries, rate limiter and error mapping. Reads use
+// the GraphQL API: repositories are fetched up to 100 per query, and listings
+// return every setting the workflow needs, so processing an organization costs
+// one request per 100 repositories instead of one per repository.
+//
+// Writes use the REST API, because the GraphQL updateRepository mutation cannot
+// change delete-branch-on-merge or the merge settings.
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// API backends selectable for repository reads.
+const (
+	BackendREST    = "rest"
+	BackendGraphQL = "graphql"
+)
+
+// graphqlBatchSize is the number of repositories fetched per GraphQL query.
+const graphqlBatchSize = 100
+
+// graphqlRepositoryFields selects the repository fields mapped onto Repository.
+const graphqlRepositoryFields = `
+	owner { login }
+	name
+	defaultBranchRef { name }
+	deleteBranchOnMerge
+	isArchived
+	isFork
+	isTemplate
+	isPrivate
+	visibility
+	primaryLanguage { name }
+	pushedAt
+	repositoryTopics(first: 20) { nodes { topic { name } } }
+	squashMergeAllowed
+	mergeCommitAllowed
+	rebaseMergeAllowed
+	autoMergeAllowed
+	allowUpdateBranch
+	squashMergeCommitTitle
+	squashMergeCommitMessage`
+
+// GraphQLClient implements the IGitHubClient interface using the GraphQL API for reads.
+//
+// Repositories read in bulk (by listing or PrefetchRepositories) are kept until
+// the next GetRepository call for them, which consumes the entry; later reads,
+// such as the verification read after an update, query GitHub again.
+type GraphQLClient struct {
+	rest *GitHubClient
+	url  string
+
+	mu         sync.Mutex
+	prefetched map[string]*Repository
+}
+
+// NewGraphQLClient creates a new GraphQLClient instance.
+//
+// Parameters:
+//   - rest: the REST client whose transport is shared and which performs writes
+//     and token validation
+//
+// Returns a GraphQLClient that implements the IGitHubClient and
+// IRepositoryPrefetcher interfaces.
+func NewGraphQLClient(rest *GitHubClient) *GraphQLClient {
+	return &GraphQLClient{
+		rest:       rest,
+		url:        graphqlURL(rest.baseURL),
+		prefetched: map[string]*Repository{},
+	}
+}
+
+// graphqlURL returns the GraphQL endpoint for a REST API base URL.
+// GitHub Enterprise Server serves GraphQL at /api/graphql next to /api/v3.
+func graphqlURL(baseURL string) string {
+	if strings.HasSuffix(baseURL, "/api/v3") {
+		return strings.TrimSuffix(baseURL, "/v3") + "/graphql"
+	}
+	return baseURL + "/graphql"
+}
+
+// GetRepository retrieves repository information, from a bulk read if one is pending.
+func (c *GraphQLClient) GetRepository(ctx context.Context, owner, name string) (interfaces.IRepository, error) {
+	key := repositoryKey(owner, name)
+	c.mu.Lock()
+	repo, ok := c.prefetched[key]
+	delete(c.prefetched, key)
+	c.mu.Unlock()
+	if ok {
+		return repo, nil
+	}
+
+	repos, err := c.queryRepositories(ctx, [][2]string{{owner, name}})
+	if err != nil {
+		return nil, err
+	}
+	if repos[0] == nil {
+		return nil, apperrors.NewRepositoryNotFoundError(owner, name)
+	}
+	return repos[0], nil
+}
+
+// UpdateRepository updates repository settings with the REST API.
+func (c *GraphQLClient) UpdateRepository(ctx context.Context, owner, name string, settings interfaces.IRepositorySettings) error {
+	c.mu.Lock()
+	delete(c.prefetched, repositoryKey(owner, name))
+	c.mu.Unlock()
+
+	return c.rest.UpdateRepository(ctx, owner, name, settings)
+}
+
+// ValidateToken validates the token with the REST API, which reports its OAuth scopes.
+func (c *GraphQLClient) ValidateToken(ctx context.Context) (interfaces.ITokenInfo, error) {
+	return c.rest.ValidateToken(ctx)
+}
+
+// ListOrganizationRepositories lists every repository of an organization,
+// 100 per query, keeping them for the GetRepository calls that follow.
+func (c *GraphQLClient) ListOrganizationRepositories(ctx context.Context, org string) ([]interfaces.IRepository, error) {
+	query := `query($login: String!, $after: String) {
+	organization(login: $login) {
+		repositories(first: 100, after: $after) {
+			pageInfo { hasNextPage endCursor }
+			nodes {` + graphqlRepositoryFields + ` }
+		}
+	}
+}`
+	return c.listRepositories(ctx, query, map[string]interface{}{"login": org}, "organization", func() error {
+		return apperrors.NewOrganizationNotFoundError(org)
+	})
+}
+
+// ListUserRepositories lists the repositories of the authenticated user with the
+// given affiliations, 100 per query, keeping them for the GetRepository calls that follow.
+func (c *GraphQLClient) ListUserRepositories(ctx context.Context, affiliation string) ([]interfaces.IRepository, error) {
+	var affiliations []string
+	for _, value := range strings.Split(affiliation, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			affiliations = append(affiliations, strings.ToUpper(value))
+		}
+	}
+
+	query := `query($affiliations: [RepositoryAffiliation], $after: String) {
+	viewer {
+		repositories(first: 100, after: $after, affiliations: $affiliations, ownerAffiliations: $affiliations) {
+			pageInfo { hasNextPage endCursor }
+			nodes {` + graphqlRepositoryFields + ` }
+		}
+	}
+}`
+	return c.listRepositories(ctx, query, map[string]interface{}{"affiliations": affiliations}, "viewer", nil)
+}
+
+// SearchRepositories searches with the REST API, whose search syntax and
+// pagination the GraphQL search shares. The matching repositories are read in
+// bulk by PrefetchRepositories before they are processed.
+func (c *GraphQLClient) SearchRepositories(ctx context.Context, query string) ([]interfaces.IRepository, int, error) {
+	return c.rest.SearchRepositories(ctx, query)
+}
+
+// ListEnterpriseOrganizations lists the logins of an enterprise's member
+// organizations, 100 per query, ordered by login.
+func (c *GraphQLClient) ListEnterpriseOrganizations(ctx context.Context, slug string) ([]string, error) {
+	query := `query($slug: String!, $after: String) {
+	enterprise(slug: $slug) {
+		organizations(first: 100, after: $after, orderBy: {field: LOGIN, direction: ASC}) {
+			pageInfo { hasNextPage endCursor }
+			nodes { login }
+		}
+	}
+}`
+	var logins []string
+
+	variables := map[string]interface{}{"slug": slug}
+	for {
+		var data struct {
+			Enterprise *struct {
+				Organizations struct {
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+					Nodes []*struct {
+						Login string `json:"login"`
+					} `json:"nodes"`
+				} `json:"organizations"`
+			} `json:"enterprise"`
+		}
+		if err := c.do(ctx, query, variables, &data); err != nil {
+			return nil, err
+		}
+		if data.Enterprise == nil {
+			return nil, apperrors.NewEnterpriseNotFoundError(slug)
+		}
+
+		connection := data.Enterprise.Organizations
+		for _, node := range connection.Nodes {
+			if node != nil {
+				logins = append(logins, node.Login)
+			}
+		}
+
+		if !connection.PageInfo.HasNextPage {
+			return logins, nil
+		}
+		variables["after"] = connection.PageInfo.EndCursor
+	}
+}
+
+// PrefetchRepositories reads the given "owner/name" repositories in bulk, 100
+// per query, and keeps them for the GetRepository calls that follow.
+// Repositories that are already pending or cannot be read are skipped; reading
+// them again reports the error.
+func (c *GraphQLClient) PrefetchRepositories(ctx context.Context, fullNames []string) error {
+	var refs [][2]string
+	c.mu.Lock()
+	for _, fullName := range fullNames {
+		owner, name, ok := strings.Cut(fullName, "/")
+		if !ok {
+			continue
+		}
+		if _, pending := c.prefetched[repositoryKey(owner, name)]; !pending {
+			refs = append(refs, [2]string{owner, name})
+		}
+	}
+	c.mu.Unlock()
+
+	for start := 0; start < len(refs); start += graphqlBatchSize {
+		end := start + graphqlBatchSize
+		if end > len(refs) {
+			end = len(refs)
+		}
+		repos, err := c.queryRepositories(ctx, refs[start:end])
+		if err != nil {
+			return err
+		}
+		c.keep(repos)
+	}
+	return nil
+}
+
+// queryRepositories reads repositories with one aliased query.
+// The result has one entry per ref, nil for repositories that were not found.
+func (c *GraphQLClient) queryRepositories(ctx context.Context, refs [][2]string) ([]*Repository, error) {
+	var query strings.Builder
+	variables := map[string]interface{}{}
+	params := make([]string, 0, len(refs)*2)
+	for i, ref := range refs {
+		params = append(params, fmt.Sprintf("$o%d: String!, $n%d: String!", i, i))
+		fmt.Fprintf(&query, "\tr%d: repository(owner: $o%d, name: $n%d) {%s }\n", i, i, i, graphqlRepositoryFields)
+		variables[fmt.Sprintf("o%d", i)] = ref[0]
+		variables[fmt.Sprintf("n%d", i)] = ref[1]
+	}
+
+	var data map[string]*graphqlRepository
+	err := c.do(ctx, fmt.Sprintf("query(%s) {\n%s}", strings.Join(params, ", "), query.String()), variables, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	repos := make([]*Repository, len(refs))
+	for i := range refs {
+		if node := data[fmt.Sprintf("r%d", i)]; node != nil {
+			repos[i] = node.repository()
+		}
+	}
+	return repos, nil
+}
+
+// listRepositories follows the pages of a repository connection under the
+// top-level field root. notFound builds the error for a null root, if any.
+func (c *GraphQLClient) listRepositories(
+	ctx context.Context,
+	query string,
+	variables map[string]interface{},
+	root string,
+	notFound func() error,
+) ([]interfaces.IRepository, error) {
+	var repos []interfaces.IRepository
+
+	var after *string
+	for {
+		variables["after"] = after
+		var data map[string]*struct {
+			Repositories struct {
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []*graphqlRepository `json:"nodes"`
+			} `json:"repositories"`
+		}
+		if err := c.do(ctx, query, variables, &data); err != nil {
+			return nil, err
+		}
+		if data[root] == nil {
+			if notFound != nil {
+				return nil, notFound()
+			}
+			return nil, apperrors.NewAPIError(fmt.Sprintf("GraphQL response has no %s", root), nil)
+		}
+
+		connection := data[root].Repositories
+		page := make([]*Repository, 0, len(connection.Nodes))
+		for _, node := range connection.Nodes {
+			if node != nil {
+				page = append(page, node.repository())
+			}
+		}
+		c.keep(page)
+		for _, repo := range page {
+			repos = append(repos, repo)
+		}
+
+		if !connection.PageInfo.HasNextPage {
+			return repos, nil
+		}
+		cursor := connection.PageInfo.EndCursor
+		after = &cursor
+	}
+}
+
+// keep stores bulk-read repositories for the GetRepository calls that follow.
+func (c *GraphQLClient) keep(repos []*Repository) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, repo := range repos {
+		if repo != nil {
+			c.prefetched[repositoryKey(repo.Owner.Login, repo.Name)] = repo
+		}
+	}
+}
+
+// graphqlError is one entry of a GraphQL response's errors array.
+type graphqlError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// do sends a GraphQL query and decodes its data into result.
+//
+// Errors for missing repositories (type NOT_FOUND on an aliased field) are left
+// for the caller, which sees the field as null. Other errors are mapped onto
+// the same AppError codes as the REST API.
+func (c *GraphQLClient) do(ctx context.Context, query string, variables map[string]interface{}, result interface{}) error {
+	var response struct {
+		Data   interface{}    `json:"data"`
+		Errors []graphqlError `json:"errors"`
+	}
+	response.Data = result
+
+	body := map[string]interface{}{"query": query, "variables": variables}
+	if err := c.rest.doRequestWithRetry(ctx, http.MethodPost, c.url, body, &response); err != nil {
+		return err
+	}
+
+	for _, gqlErr := range response.Errors {
+		switch gqlErr.Type {
+		case "NOT_FOUND":
+			continue
+		case "FORBIDDEN":
+			return apperrors.NewAuthorizationError(gqlErr.Message)
+		case "RATE_LIMITED":
+			budget, _ := c.rest.limiter.Budget(resourceGraphQL)
+			return apperrors.NewRateLimitError(budget.Reset)
+		default:
+			return apperrors.NewAPIError(fmt.Sprintf("GraphQL query failed: %s", gqlErr.Message), nil)
+		}
+	}
+	return nil
+}
+
+// graphqlRepository is a repository as selected by graphqlRepositoryFields.
+type graphqlRepository struct {
+	Owner struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+	Name             string `json:"name"`
+	DefaultBranchRef *struct {
+		Name string `json:"name"`
+	} `json:"defaultBranchRef"`
+	DeleteBranchOnMerge      bool      `json:"deleteBranchOnMerge"`
+	IsArchived               bool      `json:"isArchived"`
+	IsFork                   bool      `json:"isFork"`
+	IsTemplate               bool      `json:"isTemplate"`
+	IsPrivate                bool      `json:"isPrivate"`
+	Visibility               string    `json:"visibility"`
+	SquashMergeAllowed       *bool     `json:"squashMergeAllowed"`
+	MergeCommitAllowed       *bool     `json:"mergeCommitAllowed"`
+	RebaseMergeAllowed       *bool     `json:"rebaseMergeAllowed"`
+	AutoMergeAllowed         *bool     `json:"autoMergeAllowed"`
+	AllowUpdateBranch        *bool     `json:"allowUpdateBranch"`
+	SquashMergeCommitTitle   *string   `json:"squashMergeCommitTitle"`
+	SquashMergeCommitMessage *string   `json:"squashMergeCommitMessage"`
+	PushedAt                 time.Time `json:"pushedAt"`
+	PrimaryLanguage          *struct {
+		Name string `json:"name"`
+	} `json:"primaryLanguage"`
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string `json:"name"`
+			} `json:"topic"`
+		} `json:"nodes"`
+	} `json:"repositoryTopics"`
+}
+
+// repository converts the GraphQL repository to the REST model.
+func (r *graphqlRepository) repository() *Repository {
+	repo := &Repository{
+		Name:                     r.Name,
+		DeleteBranchOnMerge:      r.DeleteBranchOnMerge,
+		Archived:                 r.IsArchived,
+		Fork:                     r.IsFork,
+		IsTemplateRepository:     r.IsTemplate,
+		Private:                  r.IsPrivate,
+		Visibility:               strings.ToLower(r.Visibility),
+		PushedAt:                 r.PushedAt,
+		AllowSquashMerge:         r.SquashMergeAllowed,
+		AllowMergeCommit:         r.MergeCommitAllowed,
+		AllowRebaseMerge:         r.RebaseMergeAllowed,
+		AllowAutoMerge:           r.AutoMergeAllowed,
+		AllowUpdateBranch:        r.AllowUpdateBranch,
+		SquashMergeCommitTitle:   r.SquashMergeCommitTitle,
+		SquashMergeCommitMessage: r.SquashMergeCommitMessage,
+	}
+	repo.Owner.Login = r.Owner.Login
+	if r.DefaultBranchRef != nil {
+		repo.DefaultBranch = r.DefaultBranchRef.Name
+	}
+	if r.PrimaryLanguage != nil {
+		repo.Language = r.PrimaryLanguage.Name
+	}
+	for _, node := range r.RepositoryTopics.Nodes {
+		repo.Topics = append(repo.Topics, node.Topic.Name)
+	}
+	return repo
+}
+
+// repositoryKey identifies a repository case-insensitively, as GitHub does.
+func repositoryKey(owner, name string) string {
+	return strings.ToLower(owner + "/" + name)
+}
+
+// Ensure GraphQLClient implements the IGitHubClient and IRepositoryPrefetcher interfaces
+var (
+	_ interfaces.IGitHubClient         = (*GraphQLClient)(nil)
+	_ interfaces.IRepositoryPrefetcher = (*GraphQLClient)(nil)
+)
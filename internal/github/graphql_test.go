@@ -0,0 +1,249 @@
+// Package github_test provides tests for the GraphQLClient.
+//
+// These tests verify that GraphQLClient reads repositories in bulk with one
+// query per 100 repositories, serves the following reads from the bulk result
+// once, follows pagination when listing repositories and enterprise
+// organizations, and writes through the REST API.
+package github_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/github"
+)
+
+// graphqlRequest is the body of a GraphQL request.
+type graphqlRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// graphqlRepositoryJSON returns a repository node as GitHub's GraphQL API reports it.
+func graphqlRepositoryJSON(owner, name string, deleteBranchOnMerge bool) string {
+	return fmt.Sprintf(`{"owner":{"login":%q},"name":%q,"defaultBranchRef":{"name":"main"},`+
+		`"deleteBranchOnMerge":%t,"isArchived":false,"isFork":false,"isTemplate":false,"isPrivate":true,`+
+		`"visibility":"INTERNAL","primaryLanguage":{"name":"Go"},"pushedAt":"2024-05-01T00:00:00Z",`+
+		`"repositoryTopics":{"nodes":[{"topic":{"name":"service"}}]},`+
+		`"squashMergeAllowed":true,"mergeCommitAllowed":false,"rebaseMergeAllowed":true,"autoMergeAllowed":false,`+
+		`"allowUpdateBranch":true,"squashMergeCommitTitle":"PR_TITLE","squashMergeCommitMessage":"PR_BODY"}`,
+		owner, name, deleteBranchOnMerge)
+}
+
+// newRepositoryQueryServer answers aliased repository queries, reporting every
+// repository except "missing" with delete-branch-on-merge enabled.
+func newRepositoryQueryServer(t *testing.T, queries *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/graphql" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		atomic.AddInt32(queries, 1)
+
+		var req graphqlRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+
+		var fields, errs []string
+		for i := 0; ; i++ {
+			owner, ok := req.Variables[fmt.Sprintf("o%d", i)].(string)
+			if !ok {
+				break
+			}
+			name := req.Variables[fmt.Sprintf("n%d", i)].(string)
+			if name == "missing" {
+				fields = append(fields, fmt.Sprintf(`"r%d":null`, i))
+				errs = append(errs, fmt.Sprintf(`{"type":"NOT_FOUND","path":["r%d"],"message":"Could not resolve to a Repository"}`, i))
+				continue
+			}
+			fields = append(fields, fmt.Sprintf(`"r%d":%s`, i, graphqlRepositoryJSON(owner, name, true)))
+		}
+
+		body := `{"data":{` + strings.Join(fields, ",") + `}`
+		if len(errs) > 0 {
+			body += `,"errors":[` + strings.Join(errs, ",") + `]`
+		}
+		_, _ = w.Write([]byte(body + "}"))
+	}))
+}
+
+// TestGraphQLClientPrefetchesRepositoriesInBulk verifies 150 repositories are
+// read with two queries and each prefetched repository is served once.
+func TestGraphQLClientPrefetchesRepositoriesInBulk(t *testing.T) {
+	// Arrange
+	var queries int32
+	server := newRepositoryQueryServer(t, &queries)
+	defer server.Close()
+	client := github.NewGraphQLClient(github.NewGitHubClient(server.Client(), server.URL, "test-token"))
+
+	fullNames := make([]string, 150)
+	for i := range fullNames {
+		fullNames[i] = fmt.Sprintf("acme/repo%d", i)
+	}
+
+	// Act
+	err := client.PrefetchRepositories(context.Background(), fullNames)
+	repo, getErr := client.GetRepository(context.Background(), "acme", "repo149")
+	queriesAfterPrefetch := atomic.LoadInt32(&queries)
+	_, againErr := client.GetRepository(context.Background(), "acme", "repo149")
+
+	// Assert
+	if err != nil || getErr != nil || againErr != nil {
+		t.Fatalf("errors = %v, %v, %v", err, getErr, againErr)
+	}
+	if queriesAfterPrefetch != 2 {
+		t.Errorf("expected 2 queries for 150 repositories, got %d", queriesAfterPrefetch)
+	}
+	if n := atomic.LoadInt32(&queries); n != 3 {
+		t.Errorf("expected the second read to query again, got %d queries", n)
+	}
+	if repo.GetFullName() != "acme/repo149" || !repo.GetDeleteBranchOnMerge() || repo.GetDefaultBranch() != "main" || !repo.IsPrivate() {
+		t.Errorf("GetRepository() = %+v, expected acme/repo149 with settings mapped", repo)
+	}
+	if repo.GetVisibility() != "internal" || repo.GetLanguage() != "Go" ||
+		strings.Join(repo.GetTopics(), ",") != "service" || repo.GetPushedAt().Year() != 2024 {
+		t.Errorf("GetRepository() = %+v, expected visibility, language, topics and push time mapped", repo)
+	}
+	settings := repo.GetSettings()
+	if settings.GetAllowMergeCommit() == nil || *settings.GetAllowMergeCommit() ||
+		settings.GetSquashMergeCommitTitle() == nil || *settings.GetSquashMergeCommitTitle() != github.SquashMergeCommitTitlePRTitle {
+		t.Errorf("merge settings not mapped: %+v", settings)
+	}
+}
+
+// TestGraphQLClientRepositoryNotFound verifies a missing repository is reported
+// with exit code 5, and does not fail the bulk read of the others.
+func TestGraphQLClientRepositoryNotFound(t *testing.T) {
+	// Arrange
+	var queries int32
+	server := newRepositoryQueryServer(t, &queries)
+	defer server.Close()
+	client := github.NewGraphQLClient(github.NewGitHubClient(server.Client(), server.URL, "test-token"))
+
+	// Act
+	prefetchErr := client.PrefetchRepositories(context.Background(), []string{"acme/api", "acme/missing"})
+	_, apiErr := client.GetRepository(context.Background(), "acme", "api")
+	_, missingErr := client.GetRepository(context.Background(), "acme", "missing")
+
+	// Assert
+	if prefetchErr != nil || apiErr != nil {
+		t.Fatalf("errors = %v, %v, expected nil", prefetchErr, apiErr)
+	}
+	if code := apperrors.GetExitCode(missingErr); code != 5 {
+		t.Errorf("exit code = %d, expected 5 (err: %v)", code, missingErr)
+	}
+	if n := atomic.LoadInt32(&queries); n != 2 {
+		t.Errorf("expected the bulk query plus one for the missing repository, got %d", n)
+	}
+}
+
+// TestGraphQLClientListsOrganizationAndWritesWithREST verifies listing follows
+// pagination, the listed repositories are read without further queries, and
+// updates are sent to the REST API.
+func TestGraphQLClientListsOrganizationAndWritesWithREST(t *testing.T) {
+	// Arrange
+	var queries, patches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch && r.URL.Path == "/repos/acme/web" {
+			atomic.AddInt32(&patches, 1)
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		atomic.AddInt32(&queries, 1)
+		var req graphqlRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Variables["login"] != "acme" {
+			t.Errorf("login = %v, expected acme", req.Variables["login"])
+		}
+		if req.Variables["after"] == nil {
+			_, _ = w.Write([]byte(`{"data":{"organization":{"repositories":{"pageInfo":{"hasNextPage":true,"endCursor":"c1"},"nodes":[` +
+				graphqlRepositoryJSON("acme", "api", true) + `]}}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"organization":{"repositories":{"pageInfo":{"hasNextPage":false,"endCursor":"c2"},"nodes":[` +
+			graphqlRepositoryJSON("acme", "web", false) + `]}}}}`))
+	}))
+	defer server.Close()
+	client := github.NewGraphQLClient(github.NewGitHubClient(server.Client(), server.URL, "test-token"))
+
+	// Act
+	repos, listErr := client.ListOrganizationRepositories(context.Background(), "acme")
+	web, getErr := client.GetRepository(context.Background(), "acme", "web")
+	updateErr := client.UpdateRepository(context.Background(), "acme", "web", github.NewRepositorySettings(true))
+
+	// Assert
+	if listErr != nil || getErr != nil || updateErr != nil {
+		t.Fatalf("errors = %v, %v, %v", listErr, getErr, updateErr)
+	}
+	if len(repos) != 2 || repos[1].GetFullName() != "acme/web" {
+		t.Errorf("ListOrganizationRepositories() = %v, expected acme/api and acme/web", repos)
+	}
+	if web.GetDeleteBranchOnMerge() {
+		t.Error("expected acme/web with delete-branch-on-merge disabled")
+	}
+	if atomic.LoadInt32(&queries) != 2 || atomic.LoadInt32(&patches) != 1 {
+		t.Errorf("queries = %d, patches = %d, expected 2 and 1", queries, patches)
+	}
+}
+
+// TestGraphQLClientOrganizationNotFound verifies a null organization maps to exit code 5.
+func TestGraphQLClientOrganizationNotFound(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"organization":null},"errors":[{"type":"NOT_FOUND","path":["organization"],"message":"Could not resolve to an Organization"}]}`))
+	}))
+	defer server.Close()
+	client := github.NewGraphQLClient(github.NewGitHubClient(server.Client(), server.URL, "test-token"))
+
+	// Act
+	_, err := client.ListOrganizationRepositories(context.Background(), "ghost")
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 5 {
+		t.Errorf("exit code = %d, expected 5 (err: %v)", code, err)
+	}
+}
+
+// TestGraphQLClientListsEnterpriseOrganizations verifies listing follows pagination
+// and an unknown enterprise maps to exit code 5.
+func TestGraphQLClientListsEnterpriseOrganizations(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req graphqlRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch {
+		case req.Variables["slug"] != "acme-corp":
+			_, _ = w.Write([]byte(`{"data":{"enterprise":null},"errors":[{"type":"NOT_FOUND","path":["enterprise"],"message":"Could not resolve to an Enterprise"}]}`))
+		case req.Variables["after"] == nil:
+			_, _ = w.Write([]byte(`{"data":{"enterprise":{"organizations":{"pageInfo":{"hasNextPage":true,"endCursor":"c1"},"nodes":[{"login":"acme"}]}}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":{"enterprise":{"organizations":{"pageInfo":{"hasNextPage":false,"endCursor":"c2"},"nodes":[{"login":"acme-payments"}]}}}}`))
+		}
+	}))
+	defer server.Close()
+	client := github.NewGraphQLClient(github.NewGitHubClient(server.Client(), server.URL, "test-token"))
+
+	// Act
+	orgs, err := client.ListEnterpriseOrganizations(context.Background(), "acme-corp")
+	_, notFoundErr := client.ListEnterpriseOrganizations(context.Background(), "ghost")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("ListEnterpriseOrganizations() error = %v, expected nil", err)
+	}
+	if strings.Join(orgs, ",") != "acme,acme-payments" {
+		t.Errorf("ListEnterpriseOrganizations() = %v, expected acme and acme-payments", orgs)
+	}
+	if code := apperrors.GetExitCode(notFoundErr); code != 5 {
+		t.Errorf("exit code = %d, expected 5 (err: %v)", code, notFoundErr)
+	}
+}
@@ -4,7 +4,12 @@
 // interfaces and data structures.
 package github
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
 
 // Repository represents a GitHub repository with its key properties.
 // It implements the IRepository interface.
@@ -22,6 +27,53 @@ type Repository struct {
 
 	// DeleteBranchOnMerge indicates whether automatic branch deletion is enabled.
 	DeleteBranchOnMerge bool `json:"delete_branch_on_merge"`
+
+	// Archived indicates whether the repository is archived (read-only).
+	Archived bool `json:"archived"`
+
+	// Fork indicates whether the repository is a fork.
+	Fork bool `json:"fork"`
+
+	// IsTemplateRepository indicates whether the repository is a template repository.
+	IsTemplateRepository bool `json:"is_template"`
+
+	// Private indicates whether the repository is private (or internal).
+	Private bool `json:"private"`
+
+	// Visibility is "public", "private" or "internal"; empty if not reported.
+	Visibility string `json:"visibility"`
+
+	// Topics lists the repository topics.
+	Topics []string `json:"topics"`
+
+	// Language is the primary language; empty if GitHub detected none.
+	Language string `json:"language"`
+
+	// PushedAt is when the repository was last pushed to.
+	PushedAt time.Time `json:"pushed_at"`
+
+	// The merge settings below are only reported to users with admin access; nil when absent.
+
+	// AllowSquashMerge indicates whether squash merging is allowed.
+	AllowSquashMerge *bool `json:"allow_squash_merge,omitempty"`
+
+	// AllowMergeCommit indicates whether merge commits are allowed.
+	AllowMergeCommit *bool `json:"allow_merge_commit,omitempty"`
+
+	// AllowRebaseMerge indicates whether rebase merging is allowed.
+	AllowRebaseMerge *bool `json:"allow_rebase_merge,omitempty"`
+
+	// AllowAutoMerge indicates whether auto-merge is allowed.
+	AllowAutoMerge *bool `json:"allow_auto_merge,omitempty"`
+
+	// AllowUpdateBranch indicates whether pull request branches may be updated from the base branch.
+	AllowUpdateBranch *bool `json:"allow_update_branch,omitempty"`
+
+	// SquashMergeCommitTitle is the default squash commit title source.
+	SquashMergeCommitTitle *string `json:"squash_merge_commit_title,omitempty"`
+
+	// SquashMergeCommitMessage is the default squash commit message source.
+	SquashMergeCommitMessage *string `json:"squash_merge_commit_message,omitempty"`
 }
 
 // GetOwner returns the repository owner.
@@ -49,6 +101,53 @@ func (r *Repository) GetFullName() string {
 	return fmt.Sprintf("%s/%s", r.Owner.Login, r.Name)
 }
 
+// IsArchived returns whether the repository is archived.
+func (r *Repository) IsArchived() bool {
+	return r.Archived
+}
+
+// IsFork returns whether the repository is a fork.
+func (r *Repository) IsFork() bool {
+	return r.Fork
+}
+
+// IsTemplate returns whether the repository is a template repository.
+func (r *Repository) IsTemplate() bool {
+	return r.IsTemplateRepository
+}
+
+// IsPrivate returns whether the repository is private (or internal).
+func (r *Repository) IsPrivate() bool {
+	return r.Private
+}
+
+// GetVisibility returns the repository visibility.
+// It falls back to the private flag when GitHub did not report a visibility.
+func (r *Repository) GetVisibility() string {
+	switch {
+	case r.Visibility != "":
+		return r.Visibility
+	case r.Private:
+		return "private"
+	}
+	return "public"
+}
+
+// GetTopics returns the repository topics.
+func (r *Repository) GetTopics() []string {
+	return r.Topics
+}
+
+// GetLanguage returns the primary language.
+func (r *Repository) GetLanguage() string {
+	return r.Language
+}
+
+// GetPushedAt returns when the repository was last pushed to.
+func (r *Repository) GetPushedAt() time.Time {
+	return r.PushedAt
+}
+
 // NewRepository creates a new Repository instance.
 // Parameters:
 //   - owner: the repository owner (user or organization)
@@ -65,16 +164,118 @@ func NewRepository(owner, name, defaultBranch string, deleteBranchOnMerge bool)
 	return repo
 }
 
+// Allowed values for RepositorySettings.SquashMergeCommitTitle.
+const (
+	SquashMergeCommitTitlePRTitle         = "PR_TITLE"
+	SquashMergeCommitTitleCommitOrPRTitle = "COMMIT_OR_PR_TITLE"
+)
+
+// Allowed values for RepositorySettings.SquashMergeCommitMessage.
+const (
+	SquashMergeCommitMessagePRBody         = "PR_BODY"
+	SquashMergeCommitMessageCommitMessages = "COMMIT_MESSAGES"
+	SquashMergeCommitMessageBlank          = "BLANK"
+)
+
+// GetSettings returns the repository's current merge-related settings.
+func (r *Repository) GetSettings() interfaces.IRepositorySettings {
+	return &RepositorySettings{
+		DeleteBranchOnMerge:      Bool(r.DeleteBranchOnMerge),
+		AllowSquashMerge:         r.AllowSquashMerge,
+		AllowMergeCommit:         r.AllowMergeCommit,
+		AllowRebaseMerge:         r.AllowRebaseMerge,
+		AllowAutoMerge:           r.AllowAutoMerge,
+		AllowUpdateBranch:        r.AllowUpdateBranch,
+		SquashMergeCommitTitle:   r.SquashMergeCommitTitle,
+		SquashMergeCommitMessage: r.SquashMergeCommitMessage,
+	}
+}
+
 // RepositorySettings represents the settings that can be applied to a repository.
 // It implements the IRepositorySettings interface.
+//
+// Pointer fields are optional: nil leaves the setting unchanged on update.
+// Use Bool and String to set them inline.
 type RepositorySettings struct {
 	// DeleteBranchOnMerge indicates whether automatic branch deletion should be enabled.
-	DeleteBranchOnMerge bool
+	DeleteBranchOnMerge *bool
+
+	// AllowSquashMerge indicates whether squash merging should be allowed.
+	AllowSquashMerge *bool
+
+	// AllowMergeCommit indicates whether merge commits should be allowed.
+	AllowMergeCommit *bool
+
+	// AllowRebaseMerge indicates whether rebase merging should be allowed.
+	AllowRebaseMerge *bool
+
+	// AllowAutoMerge indicates whether auto-merge should be allowed.
+	AllowAutoMerge *bool
+
+	// AllowUpdateBranch indicates whether pull request branches may be updated from the base branch.
+	AllowUpdateBranch *bool
+
+	// SquashMergeCommitTitle is the default squash commit title source (see SquashMergeCommitTitle* constants).
+	SquashMergeCommitTitle *string
+
+	// SquashMergeCommitMessage is the default squash commit message source (see SquashMergeCommitMessage* constants).
+	SquashMergeCommitMessage *string
 }
 
 // GetDeleteBranchOnMerge returns whether delete-branch-on-merge should be enabled.
+// False when unset; see HasDeleteBranchOnMerge.
 func (s *RepositorySettings) GetDeleteBranchOnMerge() bool {
-	return s.DeleteBranchOnMerge
+	return s.DeleteBranchOnMerge != nil && *s.DeleteBranchOnMerge
+}
+
+// HasDeleteBranchOnMerge returns whether delete-branch-on-merge is part of the update.
+func (s *RepositorySettings) HasDeleteBranchOnMerge() bool {
+	return s.DeleteBranchOnMerge != nil
+}
+
+// GetAllowSquashMerge returns whether squash merging should be allowed, or nil if unset.
+func (s *RepositorySettings) GetAllowSquashMerge() *bool {
+	return s.AllowSquashMerge
+}
+
+// GetAllowMergeCommit returns whether merge commits should be allowed, or nil if unset.
+func (s *RepositorySettings) GetAllowMergeCommit() *bool {
+	return s.AllowMergeCommit
+}
+
+// GetAllowRebaseMerge returns whether rebase merging should be allowed, or nil if unset.
+func (s *RepositorySettings) GetAllowRebaseMerge() *bool {
+	return s.AllowRebaseMerge
+}
+
+// GetAllowAutoMerge returns whether auto-merge should be allowed, or nil if unset.
+func (s *RepositorySettings) GetAllowAutoMerge() *bool {
+	return s.AllowAutoMerge
+}
+
+// GetAllowUpdateBranch returns whether branch updates should be allowed, or nil if unset.
+func (s *RepositorySettings) GetAllowUpdateBranch() *bool {
+	return s.AllowUpdateBranch
+}
+
+// GetSquashMergeCommitTitle returns the default squash commit title source, or nil if unset.
+func (s *RepositorySettings) GetSquashMergeCommitTitle() *string {
+	return s.SquashMergeCommitTitle
+}
+
+// GetSquashMergeCommitMessage returns the default squash commit message source, or nil if unset.
+func (s *RepositorySettings) GetSquashMergeCommitMessage() *string {
+	return s.SquashMergeCommitMessage
+}
+
+// Bool returns a pointer to v, for setting optional RepositorySettings fields.
+func Bool(v bool) *bool {
+	return &v
+}
+
+// String returns a pointer to v, for setting optional RepositorySettings fields.
+func String(v string) *string {
+	return &v
 }
 
 // NewRepositorySettings creates a new RepositorySettings instance.
@@ -82,6 +283,6 @@ func (s *RepositorySettings) GetDeleteBranchOnMerge() bool {
 //   - deleteBranchOnMerge: whether delete-branch-on-merge should be enabled
 func NewRepositorySettings(deleteBranchOnMerge bool) *RepositorySettings {
 	return &RepositorySettings{
-		DeleteBranchOnMerge: deleteBranchOnMerge,
+		DeleteBranchOnMerge: Bool(deleteBranchOnMerge),
 	}
 }
@@ -8,6 +8,7 @@
 package github_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/josejulio/ghautodelete/internal/github"
@@ -355,7 +356,7 @@ func TestRepositorySettingsGetDeleteBranchOnMerge(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Arrange
 			settings := github.RepositorySettings{
-				DeleteBranchOnMerge: tt.deleteBranchOnMerge,
+				DeleteBranchOnMerge: github.Bool(tt.deleteBranchOnMerge),
 			}
 
 			// Act
@@ -375,7 +376,7 @@ func TestRepositorySettingsGetDeleteBranchOnMerge(t *testing.T) {
 func TestRepositorySettingsAsIRepositorySettings(t *testing.T) {
 	// Arrange
 	settings := &github.RepositorySettings{
-		DeleteBranchOnMerge: true,
+		DeleteBranchOnMerge: github.Bool(true),
 	}
 
 	// Act - function that accepts IRepositorySettings
@@ -462,3 +463,94 @@ func TestNewRepositorySettings(t *testing.T) {
 		})
 	}
 }
+
+// TestRepositoryListingFlags verifies IsArchived, IsFork, IsTemplate and IsPrivate.
+func TestRepositoryListingFlags(t *testing.T) {
+	// Arrange
+	repo := &github.Repository{Archived: true, Fork: false, IsTemplateRepository: true, Private: true}
+
+	// Act & Assert
+	if !repo.IsArchived() {
+		t.Error("IsArchived() = false, expected true")
+	}
+	if repo.IsFork() {
+		t.Error("IsFork() = true, expected false")
+	}
+	if !repo.IsTemplate() {
+		t.Error("IsTemplate() = false, expected true")
+	}
+	if !repo.IsPrivate() {
+		t.Error("IsPrivate() = false, expected true")
+	}
+}
+
+// TestRepositorySettingsOptionalFields verifies unset merge settings are nil and set ones are returned.
+func TestRepositorySettingsOptionalFields(t *testing.T) {
+	// Arrange
+	unset := github.NewRepositorySettings(true)
+	set := &github.RepositorySettings{
+		AllowSquashMerge:         github.Bool(true),
+		AllowMergeCommit:         github.Bool(false),
+		AllowRebaseMerge:         github.Bool(true),
+		AllowAutoMerge:           github.Bool(false),
+		AllowUpdateBranch:        github.Bool(true),
+		SquashMergeCommitTitle:   github.String(github.SquashMergeCommitTitleCommitOrPRTitle),
+		SquashMergeCommitMessage: github.String(github.SquashMergeCommitMessageBlank),
+	}
+
+	// Assert - defaults
+	if !unset.HasDeleteBranchOnMerge() {
+		t.Error("HasDeleteBranchOnMerge() = false, expected true by default")
+	}
+	if unset.GetAllowSquashMerge() != nil || unset.GetAllowMergeCommit() != nil || unset.GetAllowRebaseMerge() != nil ||
+		unset.GetAllowAutoMerge() != nil || unset.GetAllowUpdateBranch() != nil ||
+		unset.GetSquashMergeCommitTitle() != nil || unset.GetSquashMergeCommitMessage() != nil {
+		t.Error("optional settings should be nil when not set")
+	}
+
+	// Assert - explicit values
+	if set.HasDeleteBranchOnMerge() {
+		t.Error("HasDeleteBranchOnMerge() = true, expected false when omitted")
+	}
+	if !*set.GetAllowSquashMerge() || *set.GetAllowMergeCommit() || !*set.GetAllowRebaseMerge() ||
+		*set.GetAllowAutoMerge() || !*set.GetAllowUpdateBranch() {
+		t.Error("boolean settings should return the values that were set")
+	}
+	if *set.GetSquashMergeCommitTitle() != "COMMIT_OR_PR_TITLE" {
+		t.Errorf("GetSquashMergeCommitTitle() = %q, expected %q", *set.GetSquashMergeCommitTitle(), "COMMIT_OR_PR_TITLE")
+	}
+	if *set.GetSquashMergeCommitMessage() != "BLANK" {
+		t.Errorf("GetSquashMergeCommitMessage() = %q, expected %q", *set.GetSquashMergeCommitMessage(), "BLANK")
+	}
+}
+
+// TestRepositoryGetSettings verifies merge settings are decoded from the API response.
+func TestRepositoryGetSettings(t *testing.T) {
+	// Arrange
+	data := `{"delete_branch_on_merge":true,"allow_squash_merge":true,"allow_merge_commit":false,"squash_merge_commit_title":"PR_TITLE"}`
+	var repo github.Repository
+
+	// Act
+	err := json.Unmarshal([]byte(data), &repo)
+	settings := repo.GetSettings()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !settings.HasDeleteBranchOnMerge() || !settings.GetDeleteBranchOnMerge() {
+		t.Error("settings should report delete_branch_on_merge=true")
+	}
+	if settings.GetAllowSquashMerge() == nil || !*settings.GetAllowSquashMerge() {
+		t.Error("settings should report allow_squash_merge=true")
+	}
+	if settings.GetAllowMergeCommit() == nil || *settings.GetAllowMergeCommit() {
+		t.Error("settings should report allow_merge_commit=false")
+	}
+	if settings.GetAllowRebaseMerge() != nil {
+		t.Error("settings missing from the response should be nil")
+	}
+	if settings.GetSquashMergeCommitTitle() == nil || *settings.GetSquashMergeCommitTitle() != "PR_TITLE" {
+		t.Error("settings should report squash_merge_commit_title=PR_TITLE")
+	}
+}
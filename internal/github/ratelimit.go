@@ -0,0 +1,396 @@
+// Package github provides a rate-limit governor for the GitHub API client.
+//
+// RateLimiter implements the IRateLimiter interface. The client reports the
+// X-RateLimit-* headers of every response to it, and it paces the requests that
+// follow: once less than a tenth of a resource's budget is left, requests are
+// spread evenly over the time remaining until the reset, so a long run slows
+// down instead of running out. An exhausted budget either fails fast with
+// ErrAPIRateLimited or, with waitOnLimit, waits for the reset.
+package github
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+const (
+	// pacingFraction is the share of the budget below which requests are paced.
+	pacingFraction = 0.1
+
+	// resetGrace is added to the reset time when waiting for an exhausted budget,
+	// to allow for clock skew between this machine and GitHub.
+	resetGrace = time.Second
+
+	// maxSecondaryRetries is how often a request hitting a secondary rate limit is retried.
+	maxSecondaryRetries = 3
+
+	// secondaryBaseDelay is the first pause after a secondary rate limit without
+	// Retry-After; GitHub asks to wait at least a minute. It doubles on each retry.
+	secondaryBaseDelay = time.Minute
+)
+
+// Rate-limit resources, as reported by the X-RateLimit-Resource header.
+const (
+	resourceCore    = "core"
+	resourceSearch  = "search"
+	resourceGraphQL = "graphql"
+)
+
+// RateLimiter tracks the rate-limit budget of each resource and paces requests.
+//
+// It is safe for concurrent use; concurrent requests share one budget and are
+// given successive time slots when paced.
+type RateLimiter struct {
+	waitOnLimit bool
+	notify      func(string)
+	now         func() time.Time
+	sleep       func(ctx context.Context, d time.Duration) error
+
+	mu          sync.Mutex
+	budgets     map[string]interfaces.RateLimitBudget
+	reported    map[string]int
+	nextSlot    map[string]time.Time
+	pacing      map[string]bool
+	waitingFor  map[string]time.Time
+	pausedUntil time.Time
+	pauseNoted  time.Time
+}
+
+// RateLimiterOption configures optional RateLimiter behavior.
+type RateLimiterOption func(*RateLimiter)
+
+// WithRateLimitNotify sets a function receiving a message when the limiter
+// starts pacing requests or waits for a reset, e.g. the info output.
+func WithRateLimitNotify(notify func(string)) RateLimiterOption {
+	return func(r *RateLimiter) {
+		r.notify = notify
+	}
+}
+
+// WithRateLimitClock replaces the clock and sleep function, for testing.
+func WithRateLimitClock(now func() time.Time, sleep func(ctx context.Context, d time.Duration) error) RateLimiterOption {
+	return func(r *RateLimiter) {
+		r.now = now
+		r.sleep = sleep
+	}
+}
+
+// NewRateLimiter creates a new RateLimiter.
+//
+// Parameters:
+//   - waitOnLimit: if true, an exhausted budget waits for the reset instead of failing
+//   - opts: optional behavior, e.g. WithRateLimitNotify
+//
+// Returns a RateLimiter that implements the IRateLimiter interface.
+func NewRateLimiter(waitOnLimit bool, opts ...RateLimiterOption) *RateLimiter {
+	r := &RateLimiter{
+		waitOnLimit: waitOnLimit,
+		now:         time.Now,
+		sleep:       sleepContext,
+		budgets:     make(map[string]interfaces.RateLimitBudget),
+		reported:    make(map[string]int),
+		nextSlot:    make(map[string]time.Time),
+		pacing:      make(map[string]bool),
+		waitingFor:  make(map[string]time.Time),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Wait blocks until a request against resource may be sent.
+//
+// A pause set by Pause is waited out first. Then, without a known budget, or
+// once its window has reset, it returns at once.
+//
+// Returns:
+//   - nil when the request may be sent
+//   - An *AppError with code ErrAPIRateLimited if the budget is exhausted and waitOnLimit is off
+//   - The context's error if ctx is cancelled while waiting
+func (r *RateLimiter) Wait(ctx context.Context, resource string) error {
+	if err := r.waitForPause(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	now := r.now()
+	budget, ok := r.budgets[resource]
+	if !ok || !now.Before(budget.Reset) {
+		r.mu.Unlock()
+		return nil
+	}
+
+	if budget.Remaining <= 0 {
+		if !r.waitOnLimit {
+			r.mu.Unlock()
+			return apperrors.NewRateLimitError(budget.Reset)
+		}
+		if !r.waitingFor[resource].Equal(budget.Reset) {
+			r.waitingFor[resource] = budget.Reset
+			r.notifyf("GitHub API rate limit exhausted; waiting until %s", budget.Reset.Format(time.RFC3339))
+		}
+		r.mu.Unlock()
+		return r.sleep(ctx, budget.Reset.Add(resetGrace).Sub(now))
+	}
+
+	// Reserve one request so concurrent callers see the shrinking budget
+	budget.Remaining--
+	r.budgets[resource] = budget
+
+	if float64(budget.Remaining+1) >= pacingFraction*float64(budget.Limit) {
+		r.pacing[resource] = false
+		r.mu.Unlock()
+		return nil
+	}
+
+	interval := budget.Reset.Sub(now) / time.Duration(budget.Remaining+1)
+	slot := r.nextSlot[resource]
+	if slot.Before(now) {
+		slot = now
+	}
+	r.nextSlot[resource] = slot.Add(interval)
+
+	if !r.pacing[resource] {
+		r.pacing[resource] = true
+		r.notifyf("GitHub API rate limit low (%d of %d remaining); pacing requests every %s until %s",
+			budget.Remaining+1, budget.Limit, interval.Round(time.Millisecond), budget.Reset.Format(time.RFC3339))
+	}
+	r.mu.Unlock()
+
+	return r.sleep(ctx, slot.Sub(now))
+}
+
+// Pause holds back every request, for all resources, for d. A later pause
+// only extends an earlier one.
+func (r *RateLimiter) Pause(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if until := r.now().Add(d); until.After(r.pausedUntil) {
+		r.pausedUntil = until
+	}
+}
+
+// waitForPause sleeps until the current pause, if any, is over.
+func (r *RateLimiter) waitForPause(ctx context.Context) error {
+	r.mu.Lock()
+	now := r.now()
+	until := r.pausedUntil
+	if !now.Before(until) {
+		r.mu.Unlock()
+		return nil
+	}
+	if !r.pauseNoted.Equal(until) {
+		r.pauseNoted = until
+		r.notifyf("GitHub API secondary rate limit hit; pausing requests until %s", until.Format(time.RFC3339))
+	}
+	r.mu.Unlock()
+
+	return r.sleep(ctx, until.Sub(now))
+}
+
+// Update records the budget reported by a response.
+//
+// Within one window the lower remaining count wins, so a response that was in
+// flight before other requests were reserved does not inflate the budget.
+func (r *RateLimiter) Update(budget interfaces.RateLimitBudget) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reported[budget.Resource] = budget.Remaining
+	current, ok := r.budgets[budget.Resource]
+	if ok && current.Reset.Equal(budget.Reset) && current.Remaining < budget.Remaining {
+		budget.Remaining = current.Remaining
+	}
+	r.budgets[budget.Resource] = budget
+}
+
+// Release gives back the request Wait reserved against resource, for a
+// response that did not count against the budget, such as 304 Not Modified.
+// The budget never rises above the remaining count GitHub last reported.
+func (r *RateLimiter) Release(resource string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	budget, ok := r.budgets[resource]
+	if !ok || budget.Remaining >= r.reported[resource] {
+		return
+	}
+	budget.Remaining++
+	r.budgets[resource] = budget
+}
+
+// Budget returns the most recently reported budget for resource and whether
+// one has been reported.
+func (r *RateLimiter) Budget(resource string) (interfaces.RateLimitBudget, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	budget, ok := r.budgets[resource]
+	return budget, ok
+}
+
+// notifyf sends a formatted message to the notify function, if one is set.
+func (r *RateLimiter) notifyf(format string, args ...interface{}) {
+	if r.notify != nil {
+		r.notify(fmt.Sprintf(format, args...))
+	}
+}
+
+// sleepContext sleeps for d or until ctx is cancelled.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimitResource returns the rate-limit resource a request URL counts against.
+func rateLimitResource(url string) string {
+	path := strings.SplitN(url, "?", 2)[0]
+	switch {
+	case strings.Contains(path, "/search/"):
+		return resourceSearch
+	case strings.HasSuffix(path, "/graphql"):
+		return resourceGraphQL
+	}
+	return resourceCore
+}
+
+// parseRateLimit extracts the budget from a response's X-RateLimit-* headers.
+// Returns false if the remaining count or reset time is missing or invalid.
+func parseRateLimit(resp *http.Response, resource string) (interfaces.RateLimitBudget, bool) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return interfaces.RateLimitBudget{}, false
+	}
+
+	reset, ok := parseResetHeader(resp.Header.Get("X-RateLimit-Reset"))
+	if !ok {
+		return interfaces.RateLimitBudget{}, false
+	}
+
+	if reported := resp.Header.Get("X-RateLimit-Resource"); reported != "" {
+		resource = reported
+	}
+
+	// Limit and Used are informational; zero if missing
+	limit, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	used, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Used"))
+
+	return interfaces.RateLimitBudget{
+		Resource:  resource,
+		Limit:     limit,
+		Remaining: remaining,
+		Used:      used,
+		Reset:     reset,
+	}, true
+}
+
+// parseResetHeader parses an X-RateLimit-Reset value, a Unix timestamp or an
+// RFC 3339 time.
+func parseResetHeader(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	if resetUnix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(resetUnix, 0), true
+	}
+
+	if resetTime, err := time.Parse(time.RFC3339, value); err == nil {
+		return resetTime, true
+	}
+
+	return time.Time{}, false
+}
+
+// secondaryRateLimitError is a secondary rate-limit error carrying the delay
+// GitHub asked for in its Retry-After header, if any.
+type secondaryRateLimitError struct {
+	*apperrors.AppError
+	retryAfter    time.Duration
+	hasRetryAfter bool
+}
+
+// Unwrap returns the underlying AppError, so the error maps to ErrAPIRateLimited.
+func (e *secondaryRateLimitError) Unwrap() error {
+	return e.AppError
+}
+
+// newSecondaryRateLimitError creates the error for a secondary rate-limit response.
+func newSecondaryRateLimitError(resp *http.Response) *secondaryRateLimitError {
+	retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	return &secondaryRateLimitError{
+		AppError:      apperrors.NewSecondaryRateLimitError(retryAfter),
+		retryAfter:    retryAfter,
+		hasRetryAfter: ok,
+	}
+}
+
+// isSecondaryRateLimit reports whether a 403 response is a secondary rate limit,
+// identified by a Retry-After header or GitHub's documented message.
+func isSecondaryRateLimit(resp *http.Response, body []byte) bool {
+	if resp.Header.Get("Retry-After") != "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+}
+
+// secondaryRetryDelay returns how long to pause before retry number retry
+// (counting from 0): Retry-After if given, otherwise an exponential backoff
+// from secondaryBaseDelay. Up to a quarter is added as jitter so concurrent
+// workers do not all resume at once.
+func secondaryRetryDelay(err *secondaryRateLimitError, retry int) time.Duration {
+	delay := err.retryAfter
+	if !err.hasRetryAfter {
+		delay = secondaryBaseDelay << retry
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay/4)+1))
+}
+
+// parseRetryAfter parses a Retry-After value, in seconds or as an HTTP date.
+// Returns false if the value is missing or invalid.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// Ensure RateLimiter implements IRateLimiter interface
+var _ interfaces.IRateLimiter = (*RateLimiter)(nil)
@@ -0,0 +1,243 @@
+// Package github_test provides tests for the RateLimiter implementation.
+//
+// These tests verify that RateLimiter paces requests once the budget runs low,
+// fails fast or waits for the reset when it is exhausted, and that the client
+// records the budget from every response.
+package github_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/github"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// fakeClock records requested sleeps and advances only when asked to.
+type fakeClock struct {
+	current time.Time
+	advance bool
+	sleeps  []time.Duration
+}
+
+func (c *fakeClock) now() time.Time { return c.current }
+
+func (c *fakeClock) sleep(ctx context.Context, d time.Duration) error {
+	c.sleeps = append(c.sleeps, d)
+	if c.advance {
+		c.current = c.current.Add(d)
+	}
+	return nil
+}
+
+// newTestRateLimiter returns a limiter using clock.
+func newTestRateLimiter(waitOnLimit bool, clock *fakeClock, notify func(string)) *github.RateLimiter {
+	return github.NewRateLimiter(waitOnLimit,
+		github.WithRateLimitClock(clock.now, clock.sleep),
+		github.WithRateLimitNotify(notify))
+}
+
+// TestRateLimiterPacesLowBudget verifies requests are spread over the time left
+// once less than a tenth of the budget remains, and not before.
+func TestRateLimiterPacesLowBudget(t *testing.T) {
+	// Arrange
+	now := time.Unix(1700000000, 0)
+	clock := &fakeClock{current: now}
+	var notices []string
+	limiter := newTestRateLimiter(false, clock, func(msg string) { notices = append(notices, msg) })
+	ctx := context.Background()
+
+	// Act
+	limiter.Update(interfaces.RateLimitBudget{Resource: "core", Limit: 5000, Remaining: 4000, Reset: now.Add(time.Hour)})
+	errHigh := limiter.Wait(ctx, "core")
+	limiter.Update(interfaces.RateLimitBudget{Resource: "core", Limit: 5000, Remaining: 100, Reset: now.Add(100 * time.Second)})
+	err1 := limiter.Wait(ctx, "core")
+	err2 := limiter.Wait(ctx, "core")
+
+	// Assert
+	if errHigh != nil || err1 != nil || err2 != nil {
+		t.Fatalf("Wait() errors = %v, %v, %v", errHigh, err1, err2)
+	}
+	// The high budget is not paced; then each request gets the next 1s slot
+	expected := []time.Duration{0, time.Second}
+	if len(clock.sleeps) != 2 || clock.sleeps[0] != expected[0] || clock.sleeps[1] != expected[1] {
+		t.Errorf("sleeps = %v, expected %v", clock.sleeps, expected)
+	}
+	if len(notices) != 1 {
+		t.Errorf("expected one pacing notice, got %q", notices)
+	}
+	budget, ok := limiter.Budget("core")
+	if !ok || budget.Remaining != 98 {
+		t.Errorf("Budget() = %+v, %v, expected 98 remaining after two reservations", budget, ok)
+	}
+}
+
+// TestRateLimiterExhaustedBudget verifies an exhausted budget fails fast, or
+// waits for the reset with waitOnLimit.
+func TestRateLimiterExhaustedBudget(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	reset := now.Add(10 * time.Minute)
+
+	tests := []struct {
+		name        string
+		waitOnLimit bool
+		expectCode  int
+		expectSleep []time.Duration
+	}{
+		{"fails fast", false, 6, nil},
+		{"waits for reset", true, 0, []time.Duration{10*time.Minute + time.Second}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			clock := &fakeClock{current: now}
+			limiter := newTestRateLimiter(tt.waitOnLimit, clock, nil)
+			limiter.Update(interfaces.RateLimitBudget{Resource: "core", Limit: 5000, Remaining: 0, Reset: reset})
+
+			// Act
+			err := limiter.Wait(context.Background(), "core")
+
+			// Assert
+			if code := apperrors.GetExitCode(err); code != tt.expectCode {
+				t.Errorf("exit code = %d, expected %d (err: %v)", code, tt.expectCode, err)
+			}
+			if fmt.Sprint(clock.sleeps) != fmt.Sprint(tt.expectSleep) {
+				t.Errorf("sleeps = %v, expected %v", clock.sleeps, tt.expectSleep)
+			}
+		})
+	}
+}
+
+// TestClientWaitsOnRateLimitAndRecordsBudget verifies the client retries an
+// exhausted rate limit after waiting, and exposes the last reported budget.
+func TestClientWaitsOnRateLimitAndRecordsBudget(t *testing.T) {
+	// Arrange
+	start := time.Unix(1700000000, 0)
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Resource", "core")
+		if n == 1 {
+			w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", start.Add(time.Minute).Unix()))
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Used", "5000")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", start.Add(time.Hour).Unix()))
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.Header().Set("X-RateLimit-Used", "1")
+		_, _ = w.Write([]byte(`{"name":"hello-world","full_name":"octocat/hello-world","owner":{"login":"octocat"}}`))
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{current: start, advance: true}
+	limiter := newTestRateLimiter(true, clock, nil)
+	client := github.NewGitHubClient(server.Client(), server.URL, "test-token", github.WithRateLimiter(limiter))
+
+	// Act
+	_, err := client.GetRepository(context.Background(), "octocat", "hello-world")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("GetRepository() error = %v, expected nil after waiting", err)
+	}
+	if len(clock.sleeps) != 1 {
+		t.Errorf("expected one wait for the reset, got %v", clock.sleeps)
+	}
+	budget, ok := client.RateLimit()
+	if !ok || budget.Remaining != 4999 || budget.Used != 1 || budget.Limit != 5000 {
+		t.Errorf("RateLimit() = %+v, %v, expected 4999 of 5000 remaining", budget, ok)
+	}
+}
+
+// TestClientRetriesSecondaryRateLimit verifies 429 responses and 403 secondary
+// rate limits pause all requests and are retried, honoring Retry-After.
+func TestClientRetriesSecondaryRateLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		header   string
+		body     string
+		minPause time.Duration
+	}{
+		{"429 with Retry-After", http.StatusTooManyRequests, "30", `{"message":"Too many requests"}`, 30 * time.Second},
+		{"403 secondary message", http.StatusForbidden, "",
+			`{"message":"You have exceeded a secondary rate limit. Please wait a few minutes before you try again."}`, time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&requests, 1) == 1 {
+					if tt.header != "" {
+						w.Header().Set("Retry-After", tt.header)
+					}
+					w.WriteHeader(tt.status)
+					_, _ = w.Write([]byte(tt.body))
+					return
+				}
+				_, _ = w.Write([]byte(`{"name":"hello-world","full_name":"octocat/hello-world","owner":{"login":"octocat"}}`))
+			}))
+			defer server.Close()
+
+			clock := &fakeClock{current: time.Unix(1700000000, 0), advance: true}
+			client := github.NewGitHubClient(server.Client(), server.URL, "test-token",
+				github.WithRateLimiter(newTestRateLimiter(false, clock, nil)))
+
+			// Act
+			_, err := client.GetRepository(context.Background(), "octocat", "hello-world")
+
+			// Assert
+			if err != nil {
+				t.Fatalf("GetRepository() error = %v, expected nil after retrying", err)
+			}
+			if len(clock.sleeps) != 1 || clock.sleeps[0] < tt.minPause || clock.sleeps[0] > tt.minPause*5/4 {
+				t.Errorf("pauses = %v, expected one pause of %s plus up to 25%% jitter", clock.sleeps, tt.minPause)
+			}
+		})
+	}
+}
+
+// TestClientSecondaryRateLimitGivesUp verifies a persistent secondary rate limit
+// fails with exit code 6 and a distinct message, not as a permissions error.
+func TestClientSecondaryRateLimitGivesUp(t *testing.T) {
+	// Arrange
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message":"You have exceeded a secondary rate limit."}`))
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{current: time.Unix(1700000000, 0), advance: true}
+	client := github.NewGitHubClient(server.Client(), server.URL, "test-token",
+		github.WithRateLimiter(newTestRateLimiter(false, clock, nil)))
+
+	// Act
+	_, err := client.GetRepository(context.Background(), "octocat", "hello-world")
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 6 {
+		t.Errorf("exit code = %d, expected 6 (err: %v)", code, err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "secondary rate limit") {
+		t.Errorf("error should name the secondary rate limit, got %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 4 {
+		t.Errorf("expected 4 requests (1 + 3 retries), got %d", n)
+	}
+}
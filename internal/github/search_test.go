@@ -0,0 +1,90 @@
+// Package github_test provides tests for repository search.
+//
+// These tests verify that SearchRepositories escapes the query, follows
+// pagination, keeps each repository once and reports the total match count,
+// and that a rejected query is a validation error that is not retried.
+package github_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/github"
+)
+
+// TestSearchRepositoriesFollowsPagination verifies every page is read and total is reported.
+func TestSearchRepositoriesFollowsPagination(t *testing.T) {
+	// Arrange
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search/repositories" {
+			t.Errorf("Expected path /search/repositories, got %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			_, _ = w.Write([]byte(`{"total_count":1500,"items":[{"owner":{"login":"acme"},"name":"web"},{"owner":{"login":"acme"},"name":"worker"}]}`))
+			return
+		}
+		if q := r.URL.Query().Get("q"); q != "org:acme topic:backend archived:false" {
+			t.Errorf("q = %q, expected the query unchanged", q)
+		}
+		w.Header().Set("Link", `<`+server.URL+`/search/repositories?q=org%3Aacme&page=2>; rel="next"`)
+		_, _ = w.Write([]byte(`{"total_count":1500,"items":[{"owner":{"login":"acme"},"name":"api","topics":["backend"]},{"owner":{"login":"acme"},"name":"web"}]}`))
+	}))
+	defer server.Close()
+	client := github.NewGitHubClient(server.Client(), server.URL, "test-token")
+
+	// Act
+	repos, total, err := client.SearchRepositories(context.Background(), "org:acme topic:backend archived:false")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("SearchRepositories() error = %v, expected nil", err)
+	}
+	if total != 1500 {
+		t.Errorf("total = %d, expected 1500", total)
+	}
+	var names []string
+	for _, repo := range repos {
+		names = append(names, repo.GetFullName())
+	}
+	if strings.Join(names, ",") != "acme/api,acme/web,acme/worker" {
+		t.Errorf("repositories = %v, expected each of acme/api, acme/web and acme/worker once", names)
+	}
+	if len(repos[0].GetTopics()) != 1 {
+		t.Errorf("topics = %v, expected the search item metadata", repos[0].GetTopics())
+	}
+}
+
+// TestSearchRepositoriesInvalidQuery verifies a 422 is reported once as a validation error.
+func TestSearchRepositoriesInvalidQuery(t *testing.T) {
+	// Arrange
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"message":"Validation Failed","errors":[{"message":"The listed users cannot be searched","code":"invalid"}]}`))
+	}))
+	defer server.Close()
+	client := github.NewGitHubClient(server.Client(), server.URL, "test-token")
+
+	// Act
+	_, _, err := client.SearchRepositories(context.Background(), "user:ghost")
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 2 {
+		t.Errorf("exit code = %d, expected 2 (err: %v)", code, err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "The listed users cannot be searched") {
+		t.Errorf("error = %v, expected GitHub's reason", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+}
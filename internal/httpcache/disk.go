@@ -0,0 +1,129 @@
+// Package httpcache provides an on-disk cache of GitHub API responses.
+//
+// DiskCache implements the IResponseCache interface. Each response is stored as
+// one JSON file named after the SHA-256 hash of its key, so keys may contain
+// anything (including a hash of the token) without leaking into file names.
+// Files are written to a temporary name and renamed into place, so concurrent
+// workers and interrupted runs never leave a partial entry behind.
+//
+// Get marks an entry as used by updating its modification time, and Prune
+// removes the entries that have not been used for a while, so the cache does
+// not grow without bound.
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// DefaultMaxAge is how long an entry is kept without being used.
+const DefaultMaxAge = 30 * 24 * time.Hour
+
+// DiskCache stores cached responses as files in a directory.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache creates a new DiskCache.
+//
+// Parameters:
+//   - dir: the cache directory; it is created on the first Set
+//
+// Returns a DiskCache that implements the IResponseCache interface.
+func NewDiskCache(dir string) *DiskCache {
+	return &DiskCache{dir: dir}
+}
+
+// Get returns the response stored under key and marks the entry as used.
+// A missing, unreadable or corrupt entry is reported as a miss.
+func (c *DiskCache) Get(key string) (interfaces.CachedResponse, bool) {
+	path := c.path(key)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return interfaces.CachedResponse{}, false
+	}
+
+	var response interfaces.CachedResponse
+	if err := json.Unmarshal(content, &response); err != nil {
+		return interfaces.CachedResponse{}, false
+	}
+
+	// Best effort: an entry that cannot be marked is only pruned sooner
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return response, true
+}
+
+// Set stores response under key, replacing any previous response.
+func (c *DiskCache) Set(key string, response interfaces.CachedResponse) error {
+	content, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(c.dir, ".entry-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// Prune removes the entries that have not been stored or used within maxAge,
+// along with temporary files left behind by interrupted writes.
+// Returns the number of files removed. A missing directory has nothing to prune.
+func (c *DiskCache) Prune(maxAge time.Duration) (int, error) {
+	entries, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	removed := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || (!strings.HasSuffix(name, ".json") && !strings.HasPrefix(name, ".entry-")) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.dir, name)); err == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// path returns the file holding the entry for key.
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Ensure DiskCache implements IResponseCache interface
+var _ interfaces.IResponseCache = (*DiskCache)(nil)
@@ -0,0 +1,119 @@
+// Package httpcache_test provides tests for the DiskCache implementation.
+//
+// These tests verify that DiskCache round-trips responses, reports missing and
+// corrupt entries as misses, never uses the key as a file name and prunes the
+// entries that have not been used.
+package httpcache_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/josejulio/ghautodelete/internal/httpcache"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// TestDiskCacheRoundTrip verifies a stored response is returned by Get.
+func TestDiskCacheRoundTrip(t *testing.T) {
+	// Arrange
+	dir := filepath.Join(t.TempDir(), "http")
+	cache := httpcache.NewDiskCache(dir)
+	response := interfaces.CachedResponse{
+		URL:    "https://api.github.com/repos/octocat/hello-world",
+		ETag:   `"abc"`,
+		Header: map[string][]string{"Link": {`<https://api.github.com/next>; rel="next"`}},
+		Body:   []byte(`{"name":"hello-world"}`),
+	}
+
+	// Act
+	err := cache.Set("secret-token-hash https://api.github.com/repos/octocat/hello-world", response)
+	got, ok := cache.Get("secret-token-hash https://api.github.com/repos/octocat/hello-world")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if !ok || got.ETag != response.ETag || string(got.Body) != string(response.Body) || got.Header["Link"][0] != response.Header["Link"][0] {
+		t.Errorf("Get() = %+v, %v, expected %+v", got, ok, response)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || strings.Contains(entries[0].Name(), "secret") {
+		t.Errorf("expected one hashed entry file, got %v", entries)
+	}
+}
+
+// TestDiskCacheMisses verifies missing and corrupt entries are misses.
+func TestDiskCacheMisses(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	cache := httpcache.NewDiskCache(dir)
+	if err := cache.Set("corrupt", interfaces.CachedResponse{ETag: `"x"`}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if err := os.WriteFile(filepath.Join(dir, entries[0].Name()), []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	_, missingOK := cache.Get("missing")
+	_, corruptOK := cache.Get("corrupt")
+
+	// Assert
+	if missingOK || corruptOK {
+		t.Errorf("Get() hits = %v, %v, expected misses", missingOK, corruptOK)
+	}
+}
+
+// TestDiskCachePrune verifies entries unused for longer than the maximum age
+// and leftover temporary files are removed, while recently used entries stay.
+func TestDiskCachePrune(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	cache := httpcache.NewDiskCache(dir)
+	_ = cache.Set("old", interfaces.CachedResponse{ETag: `"old"`})
+	_ = cache.Set("used", interfaces.CachedResponse{ETag: `"used"`})
+	_ = os.WriteFile(filepath.Join(dir, ".entry-123"), []byte("partial"), 0o600)
+	_ = os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not an entry"), 0o600)
+
+	longAgo := time.Now().Add(-60 * 24 * time.Hour)
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		_ = os.Chtimes(filepath.Join(dir, entry.Name()), longAgo, longAgo)
+	}
+	cache.Get("used")
+
+	// Act
+	removed, err := cache.Prune(httpcache.DefaultMaxAge)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Prune() removed %d files, expected the old entry and the temporary file", removed)
+	}
+	if _, ok := cache.Get("old"); ok {
+		t.Error("the unused entry should have been pruned")
+	}
+	if _, ok := cache.Get("used"); !ok {
+		t.Error("the recently used entry should be kept")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Error("files that are not cache entries should be left alone")
+	}
+}
+
+// TestDiskCachePruneMissingDirectory verifies a cache that was never written has nothing to prune.
+func TestDiskCachePruneMissingDirectory(t *testing.T) {
+	// Act
+	removed, err := httpcache.NewDiskCache(filepath.Join(t.TempDir(), "http")).Prune(httpcache.DefaultMaxAge)
+
+	// Assert
+	if removed != 0 || err != nil {
+		t.Errorf("Prune() = %d, %v, expected 0, nil", removed, err)
+	}
+}
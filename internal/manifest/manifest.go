@@ -0,0 +1,212 @@
+// Package manifest provides the declarative desired-state configuration file.
+//
+// A manifest (ghautodelete.yaml by default) declares which repositories and
+// organizations to manage and the settings they should have:
+//
+//	settings:                    # applied to every target
+//	  delete_branch_on_merge: true
+//	repositories:
+//	  - name: acme/api
+//	    settings:                # overrides for this repository
+//	      allow_auto_merge: true
+//	organizations:
+//	  - name: acme
+//	    skip_forks: true         # skip_archived defaults to true
+//	    settings:
+//	      allow_rebase_merge: false
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/github"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is the manifest file name used when none is given.
+const DefaultFile = "ghautodelete.yaml"
+
+// Settings declares the desired repository settings. Nil fields are not managed.
+type Settings struct {
+	DeleteBranchOnMerge      *bool   `yaml:"delete_branch_on_merge,omitempty"`
+	AllowSquashMerge         *bool   `yaml:"allow_squash_merge,omitempty"`
+	AllowMergeCommit         *bool   `yaml:"allow_merge_commit,omitempty"`
+	AllowRebaseMerge         *bool   `yaml:"allow_rebase_merge,omitempty"`
+	AllowAutoMerge           *bool   `yaml:"allow_auto_merge,omitempty"`
+	AllowUpdateBranch        *bool   `yaml:"allow_update_branch,omitempty"`
+	SquashMergeCommitTitle   *string `yaml:"squash_merge_commit_title,omitempty"`
+	SquashMergeCommitMessage *string `yaml:"squash_merge_commit_message,omitempty"`
+}
+
+// Repository declares a single managed repository.
+type Repository struct {
+	// Name is the repository identifier in any format accepted by the repository parser.
+	Name string `yaml:"name"`
+
+	// Settings overrides the manifest-wide settings for this repository.
+	Settings Settings `yaml:"settings"`
+}
+
+// Organization declares an organization whose repositories are all managed.
+type Organization struct {
+	// Name is the organization login.
+	Name string `yaml:"name"`
+
+	// SkipArchived excludes archived repositories; nil means true.
+	SkipArchived *bool `yaml:"skip_archived"`
+
+	// SkipForks excludes forked repositories.
+	SkipForks bool `yaml:"skip_forks"`
+
+	// SkipTemplates excludes template repositories.
+	SkipTemplates bool `yaml:"skip_templates"`
+
+	// Settings overrides the manifest-wide settings for this organization's repositories.
+	Settings Settings `yaml:"settings"`
+}
+
+// Manifest is the parsed desired-state configuration file.
+type Manifest struct {
+	// Settings applies to every repository and organization unless overridden.
+	Settings Settings `yaml:"settings"`
+
+	// Repositories lists individually managed repositories.
+	Repositories []Repository `yaml:"repositories"`
+
+	// Organizations lists organizations whose repositories are all managed.
+	Organizations []Organization `yaml:"organizations"`
+}
+
+// Parse decodes and validates a manifest.
+// Unknown keys, missing names, targets without any settings and invalid squash
+// commit values are reported as validation errors.
+func Parse(content []byte) (*Manifest, error) {
+	var m Manifest
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&m); err != nil && err != io.EOF {
+		return nil, errors.NewValidationError(fmt.Sprintf("Invalid manifest: %v", err))
+	}
+
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// RepositorySettings returns the effective settings for a declared repository.
+func (m *Manifest) RepositorySettings(repo Repository) *github.RepositorySettings {
+	return m.Settings.Override(repo.Settings).RepositorySettings()
+}
+
+// OrganizationSettings returns the effective settings for a declared organization's repositories.
+func (m *Manifest) OrganizationSettings(org Organization) *github.RepositorySettings {
+	return m.Settings.Override(org.Settings).RepositorySettings()
+}
+
+// ShouldSkipArchived returns whether archived repositories are excluded (the default).
+func (o Organization) ShouldSkipArchived() bool {
+	return o.SkipArchived == nil || *o.SkipArchived
+}
+
+// Override returns s with every field that is set in other replaced by other's value.
+func (s Settings) Override(other Settings) Settings {
+	result := s
+	overrideBool(&result.DeleteBranchOnMerge, other.DeleteBranchOnMerge)
+	overrideBool(&result.AllowSquashMerge, other.AllowSquashMerge)
+	overrideBool(&result.AllowMergeCommit, other.AllowMergeCommit)
+	overrideBool(&result.AllowRebaseMerge, other.AllowRebaseMerge)
+	overrideBool(&result.AllowAutoMerge, other.AllowAutoMerge)
+	overrideBool(&result.AllowUpdateBranch, other.AllowUpdateBranch)
+	if other.SquashMergeCommitTitle != nil {
+		result.SquashMergeCommitTitle = other.SquashMergeCommitTitle
+	}
+	if other.SquashMergeCommitMessage != nil {
+		result.SquashMergeCommitMessage = other.SquashMergeCommitMessage
+	}
+	return result
+}
+
+// IsEmpty returns whether no setting is declared.
+func (s Settings) IsEmpty() bool {
+	return s == Settings{}
+}
+
+// RepositorySettings converts s to the settings model used by the GitHub client.
+// An undeclared delete_branch_on_merge is left out of updates.
+func (s Settings) RepositorySettings() *github.RepositorySettings {
+	return &github.RepositorySettings{
+		DeleteBranchOnMerge:      s.DeleteBranchOnMerge,
+		AllowSquashMerge:         s.AllowSquashMerge,
+		AllowMergeCommit:         s.AllowMergeCommit,
+		AllowRebaseMerge:         s.AllowRebaseMerge,
+		AllowAutoMerge:           s.AllowAutoMerge,
+		AllowUpdateBranch:        s.AllowUpdateBranch,
+		SquashMergeCommitTitle:   s.SquashMergeCommitTitle,
+		SquashMergeCommitMessage: s.SquashMergeCommitMessage,
+	}
+}
+
+// validate checks the manifest for targets and settings that cannot be applied.
+func (m *Manifest) validate() error {
+	if len(m.Repositories) == 0 && len(m.Organizations) == 0 {
+		return errors.NewValidationError("Invalid manifest: no repositories or organizations declared")
+	}
+
+	for i, repo := range m.Repositories {
+		if repo.Name == "" {
+			return errors.NewValidationError(fmt.Sprintf("Invalid manifest: repositories[%d] has no name", i))
+		}
+		if err := validateSettings(repo.Name, m.Settings.Override(repo.Settings)); err != nil {
+			return err
+		}
+	}
+
+	for i, org := range m.Organizations {
+		if org.Name == "" {
+			return errors.NewValidationError(fmt.Sprintf("Invalid manifest: organizations[%d] has no name", i))
+		}
+		if err := validateSettings(org.Name, m.Settings.Override(org.Settings)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateSettings checks the effective settings of one target.
+func validateSettings(target string, s Settings) error {
+	if s.IsEmpty() {
+		return errors.NewValidationError(fmt.Sprintf("Invalid manifest: no settings declared for %s", target))
+	}
+
+	if s.SquashMergeCommitTitle != nil {
+		switch *s.SquashMergeCommitTitle {
+		case github.SquashMergeCommitTitlePRTitle, github.SquashMergeCommitTitleCommitOrPRTitle:
+		default:
+			return errors.NewValidationError(fmt.Sprintf(
+				"Invalid manifest: squash_merge_commit_title for %s must be PR_TITLE or COMMIT_OR_PR_TITLE", target))
+		}
+	}
+
+	if s.SquashMergeCommitMessage != nil {
+		switch *s.SquashMergeCommitMessage {
+		case github.SquashMergeCommitMessagePRBody, github.SquashMergeCommitMessageCommitMessages, github.SquashMergeCommitMessageBlank:
+		default:
+			return errors.NewValidationError(fmt.Sprintf(
+				"Invalid manifest: squash_merge_commit_message for %s must be PR_BODY, COMMIT_MESSAGES or BLANK", target))
+		}
+	}
+
+	return nil
+}
+
+// overrideBool replaces *dst with value if value is set.
+func overrideBool(dst **bool, value *bool) {
+	if value != nil {
+		*dst = value
+	}
+}
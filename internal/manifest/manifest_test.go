@@ -0,0 +1,150 @@
+// Package manifest_test provides tests for the desired-state manifest.
+//
+// These tests verify that Parse decodes repositories, organizations and settings,
+// applies manifest-wide settings with per-target overrides, and rejects invalid
+// manifests with validation errors.
+package manifest_test
+
+import (
+	"strings"
+	"testing"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/manifest"
+)
+
+// =============================================================================
+// Parse Tests
+// =============================================================================
+
+// TestParseManifest verifies targets and effective settings are decoded.
+func TestParseManifest(t *testing.T) {
+	// Arrange
+	content := `
+settings:
+  delete_branch_on_merge: true
+  allow_rebase_merge: false
+repositories:
+  - name: acme/api
+    settings:
+      allow_rebase_merge: true
+      squash_merge_commit_title: PR_TITLE
+organizations:
+  - name: acme
+    skip_forks: true
+`
+
+	// Act
+	m, err := manifest.Parse([]byte(content))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Parse() error = %v, expected nil", err)
+	}
+	if len(m.Repositories) != 1 || m.Repositories[0].Name != "acme/api" {
+		t.Fatalf("Repositories = %+v, expected acme/api", m.Repositories)
+	}
+	if len(m.Organizations) != 1 || m.Organizations[0].Name != "acme" {
+		t.Fatalf("Organizations = %+v, expected acme", m.Organizations)
+	}
+
+	repoSettings := m.RepositorySettings(m.Repositories[0])
+	if !repoSettings.HasDeleteBranchOnMerge() || !repoSettings.GetDeleteBranchOnMerge() {
+		t.Error("repository should inherit delete_branch_on_merge=true")
+	}
+	if repoSettings.GetAllowRebaseMerge() == nil || !*repoSettings.GetAllowRebaseMerge() {
+		t.Error("repository override allow_rebase_merge=true should win")
+	}
+	if repoSettings.GetSquashMergeCommitTitle() == nil || *repoSettings.GetSquashMergeCommitTitle() != "PR_TITLE" {
+		t.Error("repository should set squash_merge_commit_title=PR_TITLE")
+	}
+
+	org := m.Organizations[0]
+	orgSettings := m.OrganizationSettings(org)
+	if orgSettings.GetAllowRebaseMerge() == nil || *orgSettings.GetAllowRebaseMerge() {
+		t.Error("organization should inherit allow_rebase_merge=false")
+	}
+	if !org.ShouldSkipArchived() || !org.SkipForks || org.SkipTemplates {
+		t.Errorf("organization filters = archived:%v forks:%v templates:%v, expected true/true/false",
+			org.ShouldSkipArchived(), org.SkipForks, org.SkipTemplates)
+	}
+}
+
+// TestParseManifestOmitsUndeclaredDeleteBranchOnMerge verifies unmanaged settings stay out of updates.
+func TestParseManifestOmitsUndeclaredDeleteBranchOnMerge(t *testing.T) {
+	// Arrange
+	content := "repositories:\n  - name: acme/api\n    settings:\n      allow_auto_merge: true\n"
+
+	// Act
+	m, err := manifest.Parse([]byte(content))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Parse() error = %v, expected nil", err)
+	}
+	settings := m.RepositorySettings(m.Repositories[0])
+	if settings.HasDeleteBranchOnMerge() {
+		t.Error("HasDeleteBranchOnMerge() = true, expected false when not declared")
+	}
+	if settings.GetAllowSquashMerge() != nil {
+		t.Error("undeclared allow_squash_merge should be nil")
+	}
+}
+
+// TestParseManifestErrors verifies invalid manifests are validation errors.
+func TestParseManifestErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{
+			name:          "empty manifest",
+			content:       "",
+			expectedError: "no repositories or organizations declared",
+		},
+		{
+			name:          "unknown key",
+			content:       "repositories:\n  - name: acme/api\n    settings:\n      delete_branch: true\n",
+			expectedError: "field delete_branch not found",
+		},
+		{
+			name:          "repository without name",
+			content:       "settings:\n  delete_branch_on_merge: true\nrepositories:\n  - settings: {}\n",
+			expectedError: "repositories[0] has no name",
+		},
+		{
+			name:          "organization without settings",
+			content:       "organizations:\n  - name: acme\n",
+			expectedError: "no settings declared for acme",
+		},
+		{
+			name:          "invalid squash title",
+			content:       "settings:\n  squash_merge_commit_title: TITLE\nrepositories:\n  - name: acme/api\n",
+			expectedError: "squash_merge_commit_title for acme/api must be PR_TITLE or COMMIT_OR_PR_TITLE",
+		},
+		{
+			name:          "invalid squash message",
+			content:       "settings:\n  squash_merge_commit_message: BODY\nrepositories:\n  - name: acme/api\n",
+			expectedError: "squash_merge_commit_message for acme/api must be PR_BODY, COMMIT_MESSAGES or BLANK",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := manifest.Parse([]byte(tt.content))
+
+			// Assert
+			if err == nil {
+				t.Fatal("Parse() error = nil, expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("Parse() error = %v, expected to contain %q", err, tt.expectedError)
+			}
+			if code := apperrors.GetExitCode(err); code != 2 {
+				t.Errorf("exit code = %d, expected 2", code)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Package output provides a JSON implementation of the result reporter.
+//
+// JSONReporter writes one JSON object per line (JSON Lines) for every processed
+// repository, so CI tooling can parse results without scraping text output.
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// Output formats accepted by the --output flag.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// JSONReporter implements the IResultReporter interface by writing JSON Lines.
+// It is safe for concurrent use; records are written in the order reported.
+type JSONReporter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewJSONReporter creates a new JSONReporter instance.
+//
+// Parameters:
+//   - out: the writer that receives one JSON object per line
+//
+// Returns:
+//   - *JSONReporter: a new JSONReporter instance
+func NewJSONReporter(out io.Writer) *JSONReporter {
+	return &JSONReporter{encoder: json.NewEncoder(out)}
+}
+
+// Report writes the report as a single line of JSON.
+//
+// Parameters:
+//   - report: the outcome of processing one repository
+func (r *JSONReporter) Report(report interfaces.RepositoryReport) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Write errors are ignored, as they are for the text OutputWriter
+	_ = r.encoder.Encode(report)
+}
+
+// Compile-time interface satisfaction check
+var _ interfaces.IResultReporter = (*JSONReporter)(nil)
@@ -0,0 +1,78 @@
+// Package output_test provides tests for the JSONReporter implementation.
+//
+// These tests verify that JSONReporter writes one JSON object per report with
+// the documented field names, omitting empty optional fields.
+package output_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/josejulio/ghautodelete/internal/output"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+// TestJSONReporterWritesOneLinePerReport verifies JSON Lines output and field names.
+func TestJSONReporterWritesOneLinePerReport(t *testing.T) {
+	// Arrange
+	var out bytes.Buffer
+	reporter := output.NewJSONReporter(&out)
+
+	// Act
+	reporter.Report(interfaces.RepositoryReport{
+		Repository:    "octocat/hello-world",
+		DefaultBranch: "main",
+		Mode:          "normal",
+		Operation:     "enable",
+		PreviousState: "disabled",
+		NewState:      "enabled",
+		Status:        "enabled",
+	})
+	reporter.Report(interfaces.RepositoryReport{
+		Repository: "octocat/missing",
+		Mode:       "normal",
+		Operation:  "enable",
+		Status:     "failed",
+		Error:      "Repository not found",
+		ErrorCode:  5,
+	})
+
+	// Assert
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d:\n%s", len(lines), out.String())
+	}
+
+	var first map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("line 1 is not valid JSON: %v", err)
+	}
+	expected := map[string]interface{}{
+		"repository":     "octocat/hello-world",
+		"default_branch": "main",
+		"mode":           "normal",
+		"operation":      "enable",
+		"previous_state": "disabled",
+		"new_state":      "enabled",
+		"status":         "enabled",
+		"error_code":     float64(0),
+	}
+	for key, want := range expected {
+		if first[key] != want {
+			t.Errorf("line 1 %s = %v, expected %v", key, first[key], want)
+		}
+	}
+	if _, ok := first["error"]; ok {
+		t.Error("line 1 should omit error on success")
+	}
+
+	var second interfaces.RepositoryReport
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatalf("line 2 is not valid JSON: %v", err)
+	}
+	if second.ErrorCode != 5 || second.Error != "Repository not found" {
+		t.Errorf("line 2 = %+v, expected error code 5 with message", second)
+	}
+}
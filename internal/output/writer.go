@@ -7,6 +7,7 @@ package output
 import (
 	"fmt"
 	"io"
+	"sync"
 
 	"github.com/josejulio/ghautodelete/pkg/interfaces"
 )
@@ -14,11 +15,14 @@ import (
 // OutputWriter implements the IOutputWriter interface for writing formatted output.
 //
 // It routes messages to appropriate output streams (stdout/stderr) and respects
-// the verbose flag for debug output.
+// the verbose flag for debug output. It is safe for concurrent use; each
+// message is written whole.
 type OutputWriter struct {
 	verbose bool
 	out     io.Writer
 	errOut  io.Writer
+
+	mu sync.Mutex
 }
 
 // NewOutputWriter creates a new OutputWriter instance.
@@ -45,6 +49,8 @@ func NewOutputWriter(verbose bool, out io.Writer, errOut io.Writer) *OutputWrite
 // Parameters:
 //   - message: the success message to display
 func (w *OutputWriter) Success(message string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
 	fmt.Fprintf(w.out, "\u2713 %s\n", message)
 }
 
@@ -55,6 +61,8 @@ func (w *OutputWriter) Success(message string) {
 // Parameters:
 //   - message: the error message to display
 func (w *OutputWriter) Error(message string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
 	fmt.Fprintf(w.errOut, "Error: %s\n", message)
 }
 
@@ -65,6 +73,8 @@ func (w *OutputWriter) Error(message string) {
 // Parameters:
 //   - message: the informational message to display
 func (w *OutputWriter) Info(message string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
 	fmt.Fprintf(w.out, "%s\n", message)
 }
 
@@ -78,6 +88,8 @@ func (w *OutputWriter) Info(message string) {
 //   - message: the verbose message to display
 func (w *OutputWriter) Verbose(message string) {
 	if w.verbose {
+		w.mu.Lock()
+		defer w.mu.Unlock()
 		fmt.Fprintf(w.out, "[verbose] %s\n", message)
 	}
 }
@@ -0,0 +1,46 @@
+// Package parser provides functionality for parsing repository list files.
+//
+// A repository list contains one repository identifier per line, in any format
+// accepted by RepoParser.Parse. Blank lines are ignored and "#" starts a comment
+// that runs to the end of the line.
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+// ParseRepositoryList extracts repository identifiers from list file content.
+//
+// Example:
+//
+//	# backend services
+//	acme/api
+//	acme/worker   # owned by platform team
+//	https://github.com/acme/web
+//
+// Returns the identifiers in file order, trimmed of whitespace and comments.
+// The identifiers are not validated; pass each one to RepoParser.Parse.
+func ParseRepositoryList(content []byte) []string {
+	var identifiers []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// Strip comments (GitHub names and URLs never contain '#')
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		identifiers = append(identifiers, line)
+	}
+
+	return identifiers
+}
@@ -0,0 +1,64 @@
+// Package parser_test provides tests for the repository list parser.
+//
+// These tests verify that ParseRepositoryList extracts one identifier per line,
+// skipping blank lines and "#" comments.
+package parser_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/josejulio/ghautodelete/internal/parser"
+)
+
+// TestParseRepositoryList verifies identifiers, comments and blank lines are handled.
+func TestParseRepositoryList(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{
+			name:     "one identifier per line",
+			content:  "acme/api\nacme/worker\n",
+			expected: []string{"acme/api", "acme/worker"},
+		},
+		{
+			name:     "full line comments and blank lines are skipped",
+			content:  "# backend\n\nacme/api\n   \n# frontend\nacme/web",
+			expected: []string{"acme/api", "acme/web"},
+		},
+		{
+			name:     "trailing comments are stripped",
+			content:  "acme/api   # owned by platform\n",
+			expected: []string{"acme/api"},
+		},
+		{
+			name:     "surrounding whitespace and CRLF are trimmed",
+			content:  "  acme/api  \r\n\tacme/web\r\n",
+			expected: []string{"acme/api", "acme/web"},
+		},
+		{
+			name:     "URL formats are passed through unchanged",
+			content:  "https://github.com/acme/api\ngit@github.com:acme/web.git\n",
+			expected: []string{"https://github.com/acme/api", "git@github.com:acme/web.git"},
+		},
+		{
+			name:     "empty content returns no identifiers",
+			content:  "",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result := parser.ParseRepositoryList([]byte(tt.content))
+
+			// Assert
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ParseRepositoryList() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
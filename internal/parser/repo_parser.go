@@ -4,6 +4,9 @@
 // - Simple format: "owner/repo"
 // - HTTPS GitHub URL: "https://github.com/owner/repo[.git]"
 // - SSH GitHub URL: "git@github.com:owner/repo[.git]"
+//
+// URLs must point at the parser's hostname, which is github.com unless a
+// GitHub Enterprise Server hostname is configured.
 package parser
 
 import (
@@ -18,13 +21,45 @@ import (
 //
 // It handles multiple repository identifier formats and validates owner/repo names
 // according to GitHub's naming rules.
-type RepoParser struct{}
+type RepoParser struct {
+	// hostname is the lowercase host that HTTPS and SSH URLs must point at.
+	hostname string
+}
 
-// NewRepoParser creates a new RepoParser instance.
+// defaultHostname is the host accepted by NewRepoParser.
+const defaultHostname = "github.com"
+
+// NewRepoParser creates a new RepoParser instance that accepts github.com URLs.
 //
 // Returns a RepoParser that implements the IRepoParser interface.
 func NewRepoParser() interfaces.IRepoParser {
-	return &RepoParser{}
+	return NewRepoParserForHostname(defaultHostname)
+}
+
+// NewRepoParserForHostname creates a new RepoParser instance that accepts URLs
+// for the given hostname (e.g., a GitHub Enterprise Server host).
+//
+// Returns a RepoParser that implements the IRepoParser interface.
+func NewRepoParserForHostname(hostname string) interfaces.IRepoParser {
+	return &RepoParser{hostname: strings.ToLower(hostname)}
+}
+
+// DetectHostname returns the host of an HTTPS or SSH repository URL
+// in lowercase, or "" if the identifier is not a URL (e.g., "owner/repo").
+//
+// The identifier is not validated; Parse reports malformed URLs.
+func DetectHostname(input string) string {
+	input = strings.TrimSpace(input)
+	lowerInput := strings.ToLower(input)
+
+	var host string
+	switch {
+	case strings.HasPrefix(lowerInput, "https://"):
+		host = strings.SplitN(lowerInput[len("https://"):], "/", 2)[0]
+	case strings.HasPrefix(input, "git@"):
+		host = strings.SplitN(lowerInput[len("git@"):], ":", 2)[0]
+	}
+	return host
 }
 
 // validNamePattern is a regex pattern for valid GitHub owner and repository names.
@@ -33,7 +68,7 @@ var validNamePattern = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
 
 // Parse extracts owner and repository name from a repository identifier.
 //
-// Supported formats:
+// Supported formats (github.com stands for the parser's hostname):
 //   - Simple: "owner/repo"
 //   - HTTPS: "https://github.com/owner/repo[.git]"
 //   - SSH: "git@github.com:owner/repo[.git]"
@@ -115,7 +150,7 @@ func (p *RepoParser) parseSimpleFormat(input string) (owner string, repo string,
 	return owner, repo, nil
 }
 
-// parseHTTPSURL parses HTTPS GitHub URLs.
+// parseHTTPSURL parses HTTPS GitHub URLs for the parser's hostname.
 //
 // Supported formats:
 //   - https://github.com/owner/repo
@@ -133,14 +168,15 @@ func (p *RepoParser) parseHTTPSURL(input string) (owner string, repo string, err
 	// Extract the part after the scheme
 	afterScheme := input[8:] // len("https://") = 8
 
-	// Check for github.com domain (case-insensitive)
+	// Check for the configured domain (case-insensitive)
+	hostPrefix := p.hostname + "/"
 	lowerAfterScheme := strings.ToLower(afterScheme)
-	if !strings.HasPrefix(lowerAfterScheme, "github.com/") {
+	if !strings.HasPrefix(lowerAfterScheme, hostPrefix) {
 		return "", "", errors.NewValidationError("Expected format: owner/repo")
 	}
 
-	// Extract the path after github.com/
-	path := afterScheme[11:] // len("github.com/") = 11
+	// Extract the path after the domain
+	path := afterScheme[len(hostPrefix):]
 
 	// Remove trailing slash if present
 	path = strings.TrimSuffix(path, "/")
@@ -170,19 +206,20 @@ func (p *RepoParser) parseHTTPSURL(input string) (owner string, repo string, err
 	return owner, repo, nil
 }
 
-// parseSSHURL parses SSH GitHub URLs.
+// parseSSHURL parses SSH GitHub URLs for the parser's hostname.
 //
 // Supported formats:
 //   - git@github.com:owner/repo
 //   - git@github.com:owner/repo.git
 func (p *RepoParser) parseSSHURL(input string) (owner string, repo string, err error) {
-	// Check for correct SSH format
-	if !strings.HasPrefix(input, "git@github.com:") {
+	// Check for correct SSH format; host names are case-insensitive
+	sshPrefix := "git@" + p.hostname + ":"
+	if len(input) < len(sshPrefix) || !strings.EqualFold(input[:len(sshPrefix)], sshPrefix) {
 		return "", "", errors.NewValidationError("Expected format: owner/repo")
 	}
 
-	// Extract the path after git@github.com:
-	path := input[15:] // len("git@github.com:") = 15
+	// Extract the path after git@host:
+	path := input[len(sshPrefix):]
 
 	// Remove .git suffix if present
 	path = strings.TrimSuffix(path, ".git")
@@ -848,3 +848,64 @@ func TestParseMultipleCallsReturnConsistentResults(t *testing.T) {
 		}
 	}
 }
+
+// =============================================================================
+// GitHub Enterprise Server Tests
+// =============================================================================
+
+// TestParseEnterpriseHostname verifies URLs are accepted only for the configured host.
+func TestParseEnterpriseHostname(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expectedOwner string
+		expectError   bool
+	}{
+		{name: "HTTPS URL on host", input: "https://git.corp.example/platform/api.git", expectedOwner: "platform"},
+		{name: "HTTPS URL with uppercase host", input: "https://Git.Corp.Example/platform/api", expectedOwner: "platform"},
+		{name: "SSH URL on host", input: "git@git.corp.example:platform/api.git", expectedOwner: "platform"},
+		{name: "SSH URL with uppercase host", input: "git@Git.Corp.Example:platform/api.git", expectedOwner: "platform"},
+		{name: "simple format", input: "platform/api", expectedOwner: "platform"},
+		{name: "github.com URL rejected", input: "https://github.com/platform/api", expectError: true},
+		{name: "github.com SSH URL rejected", input: "git@github.com:platform/api.git", expectError: true},
+	}
+
+	p := parser.NewRepoParserForHostname("git.corp.example")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			owner, repo, err := p.Parse(tt.input)
+
+			// Assert
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Parse(%q) expected error, got %s/%s", tt.input, owner, repo)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.input, err)
+			}
+			if owner != tt.expectedOwner || repo != "api" {
+				t.Errorf("Parse(%q) = %s/%s, expected %s/api", tt.input, owner, repo, tt.expectedOwner)
+			}
+		})
+	}
+}
+
+// TestDetectHostname verifies the host is extracted from HTTPS and SSH URLs only.
+func TestDetectHostname(t *testing.T) {
+	tests := map[string]string{
+		"https://git.corp.example/platform/api": "git.corp.example",
+		"HTTPS://GitHub.com/octocat/hello":      "github.com",
+		"git@git.corp.example:platform/api.git": "git.corp.example",
+		"  git@github.com:octocat/hello  ":      "github.com",
+		"octocat/hello-world":                   "",
+	}
+
+	for input, expected := range tests {
+		if got := parser.DetectHostname(input); got != expected {
+			t.Errorf("DetectHostname(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Package snapshot provides point-in-time captures of repository settings.
+//
+// A snapshot file records the settings of a set of repositories as they were
+// when it was taken, in the same repository/settings layout as a manifest:
+//
+//	version: 1
+//	created_at: 2024-03-01T12:00:00Z
+//	hostname: github.com
+//	repositories:
+//	  - name: acme/api
+//	    settings:
+//	      delete_branch_on_merge: false
+//	      allow_squash_merge: true
+//
+// Restoring a snapshot reconciles every repository back to the recorded values,
+// exactly as applying a manifest would.
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/manifest"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the snapshot file format version written by this release.
+const CurrentVersion = 1
+
+// DefaultFile is the snapshot file name used when none is given.
+const DefaultFile = "ghautodelete-snapshot.yaml"
+
+// Snapshot is the settings of a set of repositories at one point in time.
+type Snapshot struct {
+	// Version is the file format version (see CurrentVersion).
+	Version int `yaml:"version"`
+
+	// CreatedAt is when the snapshot was taken.
+	CreatedAt time.Time `yaml:"created_at"`
+
+	// Hostname is the GitHub host the repositories are on.
+	Hostname string `yaml:"hostname"`
+
+	// Repositories lists each captured repository with its settings.
+	Repositories []manifest.Repository `yaml:"repositories"`
+}
+
+// New creates an empty snapshot of repositories on hostname taken at createdAt.
+func New(hostname string, createdAt time.Time) *Snapshot {
+	return &Snapshot{
+		Version:   CurrentVersion,
+		CreatedAt: createdAt.UTC(),
+		Hostname:  hostname,
+	}
+}
+
+// Capture returns the snapshot entry for repo.
+// Settings the API did not report (e.g., merge settings without admin access)
+// are left out, so restoring never touches them.
+func Capture(repo interfaces.IRepository) manifest.Repository {
+	settings := repo.GetSettings()
+	deleteBranchOnMerge := repo.GetDeleteBranchOnMerge()
+
+	return manifest.Repository{
+		Name: repo.GetFullName(),
+		Settings: manifest.Settings{
+			DeleteBranchOnMerge:      &deleteBranchOnMerge,
+			AllowSquashMerge:         settings.GetAllowSquashMerge(),
+			AllowMergeCommit:         settings.GetAllowMergeCommit(),
+			AllowRebaseMerge:         settings.GetAllowRebaseMerge(),
+			AllowAutoMerge:           settings.GetAllowAutoMerge(),
+			AllowUpdateBranch:        settings.GetAllowUpdateBranch(),
+			SquashMergeCommitTitle:   settings.GetSquashMergeCommitTitle(),
+			SquashMergeCommitMessage: settings.GetSquashMergeCommitMessage(),
+		},
+	}
+}
+
+// Marshal encodes the snapshot as YAML.
+func (s *Snapshot) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(s); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Parse decodes and validates a snapshot file.
+// Unknown keys, unsupported versions and repositories without a name or
+// settings are reported as validation errors.
+func Parse(content []byte) (*Snapshot, error) {
+	var s Snapshot
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&s); err != nil && err != io.EOF {
+		return nil, errors.NewValidationError(fmt.Sprintf("Invalid snapshot: %v", err))
+	}
+
+	switch {
+	case s.Version == 0:
+		return nil, errors.NewValidationError("Invalid snapshot: no version")
+	case s.Version > CurrentVersion:
+		return nil, errors.NewValidationError(fmt.Sprintf(
+			"Snapshot version %d is not supported by this release (up to %d). Upgrade ghautodelete", s.Version, CurrentVersion))
+	case len(s.Repositories) == 0:
+		return nil, errors.NewValidationError("Invalid snapshot: no repositories")
+	}
+
+	for i, repo := range s.Repositories {
+		if repo.Name == "" {
+			return nil, errors.NewValidationError(fmt.Sprintf("Invalid snapshot: repositories[%d] has no name", i))
+		}
+		if repo.Settings.IsEmpty() {
+			return nil, errors.NewValidationError(fmt.Sprintf("Invalid snapshot: no settings recorded for %s", repo.Name))
+		}
+	}
+	return &s, nil
+}
+
+// Manifest returns a manifest whose desired state is the recorded settings,
+// so a snapshot is restored by applying it.
+func (s *Snapshot) Manifest() *manifest.Manifest {
+	return &manifest.Manifest{Repositories: s.Repositories}
+}
@@ -0,0 +1,109 @@
+// Package snapshot_test provides tests for repository settings snapshots.
+//
+// These tests verify that captured settings survive a Marshal/Parse round trip,
+// that unreported settings are left out, and that invalid or newer snapshot
+// files are rejected with validation errors.
+package snapshot_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/github"
+	"github.com/josejulio/ghautodelete/internal/snapshot"
+)
+
+// TestSnapshotRoundTrip verifies a captured repository is written and read back unchanged.
+func TestSnapshotRoundTrip(t *testing.T) {
+	// Arrange
+	repo := github.NewRepository("acme", "api", "main", false)
+	repo.AllowSquashMerge = github.Bool(true)
+	repo.SquashMergeCommitTitle = github.String(github.SquashMergeCommitTitlePRTitle)
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	snap := snapshot.New("github.com", createdAt)
+	snap.Repositories = append(snap.Repositories, snapshot.Capture(repo))
+
+	// Act
+	content, marshalErr := snap.Marshal()
+	parsed, parseErr := snapshot.Parse(content)
+
+	// Assert
+	if marshalErr != nil || parseErr != nil {
+		t.Fatalf("Marshal/Parse errors = %v, %v, expected nil", marshalErr, parseErr)
+	}
+	if strings.Contains(string(content), "allow_rebase_merge") {
+		t.Errorf("unreported settings should be left out, got:\n%s", content)
+	}
+	if parsed.Version != snapshot.CurrentVersion || parsed.Hostname != "github.com" || !parsed.CreatedAt.Equal(createdAt) {
+		t.Errorf("header = %d %q %v, expected %d github.com %v",
+			parsed.Version, parsed.Hostname, parsed.CreatedAt, snapshot.CurrentVersion, createdAt)
+	}
+
+	m := parsed.Manifest()
+	if len(m.Repositories) != 1 || m.Repositories[0].Name != "acme/api" {
+		t.Fatalf("Manifest().Repositories = %+v, expected acme/api", m.Repositories)
+	}
+	settings := m.RepositorySettings(m.Repositories[0])
+	if !settings.HasDeleteBranchOnMerge() || settings.GetDeleteBranchOnMerge() {
+		t.Error("delete_branch_on_merge=false should be restored")
+	}
+	if settings.GetAllowSquashMerge() == nil || !*settings.GetAllowSquashMerge() {
+		t.Error("allow_squash_merge=true should be restored")
+	}
+	if settings.GetAllowRebaseMerge() != nil {
+		t.Error("allow_rebase_merge was not captured and should be left unchanged")
+	}
+}
+
+// TestParseRejectsInvalidSnapshots verifies invalid files are validation errors.
+func TestParseRejectsInvalidSnapshots(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "no version",
+			content: "repositories:\n  - name: acme/api\n    settings:\n      delete_branch_on_merge: true\n",
+			want:    "no version",
+		},
+		{
+			name:    "newer version",
+			content: "version: 99\nrepositories:\n  - name: acme/api\n    settings:\n      delete_branch_on_merge: true\n",
+			want:    "version 99 is not supported",
+		},
+		{
+			name:    "no repositories",
+			content: "version: 1\n",
+			want:    "no repositories",
+		},
+		{
+			name:    "no settings",
+			content: "version: 1\nrepositories:\n  - name: acme/api\n",
+			want:    "no settings recorded for acme/api",
+		},
+		{
+			name:    "unknown key",
+			content: "version: 1\nrepos: []\n",
+			want:    "Invalid snapshot",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := snapshot.Parse([]byte(tt.content))
+
+			// Assert
+			if code := apperrors.GetExitCode(err); code != 2 {
+				t.Fatalf("Parse() error = %v (code %d), expected exit code 2", err, code)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, should contain %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
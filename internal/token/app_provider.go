@@ -0,0 +1,220 @@
+// Package token provides GitHub App installation authentication.
+//
+// AppTokenProvider implements the ITokenProvider interface for a GitHub App
+// installation: it signs an RS256 JWT with the App's private key, exchanges it
+// for an installation access token and caches that token until shortly before
+// it expires, so long batch runs keep working past the one-hour token lifetime.
+package token
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+)
+
+const (
+	// jwtLifetime is how long the App JWT is valid (GitHub allows at most 10 minutes).
+	jwtLifetime = 9 * time.Minute
+
+	// jwtClockSkew backdates the JWT issue time to tolerate clock drift.
+	jwtClockSkew = 60 * time.Second
+
+	// refreshMargin is how long before expiry a cached installation token is replaced.
+	refreshMargin = 5 * time.Minute
+)
+
+// AppTokenProvider retrieves installation access tokens for a GitHub App.
+//
+// It is safe for concurrent use; concurrent callers share one cached token.
+type AppTokenProvider struct {
+	httpClient     *http.Client
+	baseURL        string
+	appID          int64
+	installationID int64
+	privateKey     *rsa.PrivateKey
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewAppTokenProvider creates a new AppTokenProvider.
+//
+// Parameters:
+//   - httpClient: the HTTP client used to exchange the JWT
+//   - baseURL: the base URL for the GitHub API (e.g., "https://api.github.com")
+//   - appID: the GitHub App ID
+//   - installationID: the ID of the App's installation on the target account
+//   - privateKeyPEM: the App's private key in PEM format (PKCS#1 or PKCS#8)
+//
+// Returns a validation error if the private key cannot be parsed.
+func NewAppTokenProvider(
+	httpClient *http.Client,
+	baseURL string,
+	appID, installationID int64,
+	privateKeyPEM []byte,
+) (*AppTokenProvider, error) {
+	privateKey, err := parsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, errors.NewValidationError(fmt.Sprintf("Invalid GitHub App private key: %v", err))
+	}
+
+	return &AppTokenProvider{
+		httpClient:     httpClient,
+		baseURL:        baseURL,
+		appID:          appID,
+		installationID: installationID,
+		privateKey:     privateKey,
+	}, nil
+}
+
+// GetToken returns a valid installation access token; see GetTokenContext.
+func (p *AppTokenProvider) GetToken() (string, error) {
+	return p.GetTokenContext(context.Background())
+}
+
+// GetTokenContext returns a valid installation access token.
+//
+// The cached token is returned until it is within refreshMargin of expiring;
+// then a new token is requested with ctx.
+//
+// Returns:
+//   - The installation access token
+//   - An *AppError with code ErrAuthenticationFailed if the exchange is rejected
+func (p *AppTokenProvider) GetTokenContext(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Now().Add(refreshMargin).Before(p.expiresAt) {
+		return p.token, nil
+	}
+
+	token, expiresAt, err := p.exchange(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	p.token = token
+	p.expiresAt = expiresAt
+	return token, nil
+}
+
+// exchange signs a JWT and requests a new installation access token.
+func (p *AppTokenProvider) exchange(ctx context.Context) (string, time.Time, error) {
+	jwt, err := p.signJWT(time.Now())
+	if err != nil {
+		return "", time.Time{}, errors.NewAuthenticationError("Cannot sign GitHub App JWT", err)
+	}
+
+	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", p.baseURL, p.installationID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return "", time.Time{}, errors.NewNetworkError(err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", jwt))
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("User-Agent", "ghautodelete")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, errors.NewNetworkError(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", time.Time{}, errors.NewNetworkError(err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusNotFound:
+		return "", time.Time{}, errors.NewAuthenticationError(fmt.Sprintf(
+			"GitHub App %d cannot access installation %d. Check the App ID, installation ID and private key",
+			p.appID, p.installationID), nil)
+	case resp.StatusCode != http.StatusCreated:
+		return "", time.Time{}, errors.NewAPIError(
+			fmt.Sprintf("GitHub App token exchange failed: %d %s", resp.StatusCode, string(body)), nil)
+	}
+
+	var response struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", time.Time{}, errors.NewAPIError("failed to parse installation token response", err)
+	}
+
+	return response.Token, response.ExpiresAt, nil
+}
+
+// signJWT creates an RS256 JWT identifying the App, issued at now.
+func (p *AppTokenProvider) signJWT(now time.Time) (string, error) {
+	header := map[string]string{"alg": "RS256", "typ": "JWT"}
+	claims := map[string]interface{}{
+		"iat": now.Add(-jwtClockSkew).Unix(),
+		"exp": now.Add(jwtLifetime).Unix(),
+		"iss": fmt.Sprintf("%d", p.appID),
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." +
+		base64.RawURLEncoding.EncodeToString(claimsJSON)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, p.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parsePrivateKey decodes a PEM-encoded RSA private key in PKCS#1 or PKCS#8 form.
+func parsePrivateKey(privateKeyPEM []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// Ensure AppTokenProvider implements ITokenProvider and IContextTokenProvider interfaces
+var (
+	_ interfaces.ITokenProvider        = (*AppTokenProvider)(nil)
+	_ interfaces.IContextTokenProvider = (*AppTokenProvider)(nil)
+)
@@ -0,0 +1,205 @@
+// Package token_test provides tests for the AppTokenProvider implementation.
+//
+// These tests verify that AppTokenProvider signs a valid RS256 JWT, exchanges it
+// for an installation token, caches the token, refreshes it before expiry and
+// makes the exchange with the caller's context.
+package token_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/token"
+)
+
+// newAppKey generates an RSA key and returns it with its PKCS#1 PEM encoding.
+func newAppKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+// newInstallationServer serves installation 67890 and counts token exchanges.
+// Each token expires after lifetime; the JWT is verified against key.
+func newInstallationServer(t *testing.T, key *rsa.PrivateKey, lifetime time.Duration) (*httptest.Server, *int) {
+	t.Helper()
+	exchanges := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/app/installations/67890/access_tokens" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		jwt := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if err := verifyJWT(jwt, &key.PublicKey); err != nil {
+			t.Errorf("invalid JWT: %v", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		exchanges++
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"token":"ghs_token_%d","expires_at":%q}`,
+			exchanges, time.Now().Add(lifetime).UTC().Format(time.RFC3339))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &exchanges
+}
+
+// verifyJWT checks the RS256 signature and the issuer claim of an App JWT.
+func verifyJWT(jwt string, publicKey *rsa.PublicKey) error {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("expected 3 parts, got %d", len(parts))
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature); err != nil {
+		return err
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return err
+	}
+	var claims struct {
+		Issuer    string `json:"iss"`
+		IssuedAt  int64  `json:"iat"`
+		ExpiresAt int64  `json:"exp"`
+	}
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		return err
+	}
+	if claims.Issuer != "12345" {
+		return fmt.Errorf("iss = %q, expected %q", claims.Issuer, "12345")
+	}
+	if claims.ExpiresAt-claims.IssuedAt > int64((10 * time.Minute).Seconds()) {
+		return fmt.Errorf("JWT lifetime exceeds 10 minutes")
+	}
+	return nil
+}
+
+// TestAppTokenProviderCachesInstallationToken verifies one exchange serves repeated calls.
+func TestAppTokenProviderCachesInstallationToken(t *testing.T) {
+	// Arrange
+	key, keyPEM := newAppKey(t)
+	server, exchanges := newInstallationServer(t, key, time.Hour)
+	provider, err := token.NewAppTokenProvider(server.Client(), server.URL, 12345, 67890, keyPEM)
+	if err != nil {
+		t.Fatalf("NewAppTokenProvider() error = %v", err)
+	}
+
+	// Act
+	first, err1 := provider.GetToken()
+	second, err2 := provider.GetToken()
+
+	// Assert
+	if err1 != nil || err2 != nil {
+		t.Fatalf("GetToken() errors = %v, %v", err1, err2)
+	}
+	if first != "ghs_token_1" || second != "ghs_token_1" {
+		t.Errorf("GetToken() = %q, %q, expected the cached ghs_token_1 twice", first, second)
+	}
+	if *exchanges != 1 {
+		t.Errorf("expected 1 token exchange, got %d", *exchanges)
+	}
+}
+
+// TestAppTokenProviderExchangeUsesCallerContext verifies a cancelled caller
+// cancels the token exchange instead of waiting for GitHub.
+func TestAppTokenProviderExchangeUsesCallerContext(t *testing.T) {
+	// Arrange
+	_, keyPEM := newAppKey(t)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	provider, err := token.NewAppTokenProvider(server.Client(), server.URL, 12345, 67890, keyPEM)
+	if err != nil {
+		t.Fatalf("NewAppTokenProvider() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Act
+	_, err = provider.GetTokenContext(ctx)
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "context deadline exceeded") {
+		t.Errorf("GetTokenContext() error = %v, expected the caller's deadline to cancel the exchange", err)
+	}
+}
+
+// TestAppTokenProviderRefreshesExpiringToken verifies a token close to expiry is replaced.
+func TestAppTokenProviderRefreshesExpiringToken(t *testing.T) {
+	// Arrange
+	key, keyPEM := newAppKey(t)
+	server, exchanges := newInstallationServer(t, key, time.Minute)
+	provider, err := token.NewAppTokenProvider(server.Client(), server.URL, 12345, 67890, keyPEM)
+	if err != nil {
+		t.Fatalf("NewAppTokenProvider() error = %v", err)
+	}
+
+	// Act
+	_, _ = provider.GetToken()
+	second, err := provider.GetToken()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+	if second != "ghs_token_2" || *exchanges != 2 {
+		t.Errorf("GetToken() = %q after %d exchanges, expected a refreshed ghs_token_2", second, *exchanges)
+	}
+}
+
+// TestAppTokenProviderErrors verifies key and exchange failures map to exit codes.
+func TestAppTokenProviderErrors(t *testing.T) {
+	// Arrange
+	key, keyPEM := newAppKey(t)
+	server, _ := newInstallationServer(t, key, time.Hour)
+
+	// Act
+	_, keyErr := token.NewAppTokenProvider(server.Client(), server.URL, 12345, 67890, []byte("not a key"))
+	provider, err := token.NewAppTokenProvider(server.Client(), server.URL, 12345, 1, keyPEM)
+	if err != nil {
+		t.Fatalf("NewAppTokenProvider() error = %v", err)
+	}
+	_, exchangeErr := provider.GetToken()
+
+	// Assert
+	if code := apperrors.GetExitCode(keyErr); code != 2 {
+		t.Errorf("invalid key exit code = %d, expected 2 (err: %v)", code, keyErr)
+	}
+	if code := apperrors.GetExitCode(exchangeErr); code != 3 {
+		t.Errorf("unknown installation exit code = %d, expected 3 (err: %v)", code, exchangeErr)
+	}
+}
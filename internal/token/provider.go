@@ -1,39 +1,94 @@
 // Package token provides functionality for retrieving GitHub API tokens.
 //
-// TokenProvider implements the ITokenProvider interface and retrieves tokens
-// with the following precedence:
-// 1. Explicit token (from CLI flag)
-// 2. GITHUB_TOKEN environment variable
-// 3. gh CLI configuration file (~/.config/gh/hosts.yml)
+// TokenProvider implements the ITokenProvider interface. An explicit token (from
+// the --token flag) always wins; otherwise a chain of token sources is tried in
+// a configurable order, by default:
+// 1. Token file (--token-file) and token command (--token-command), if configured
+// 2. GH_TOKEN, then GITHUB_TOKEN (GH_ENTERPRISE_TOKEN, GITHUB_ENTERPRISE_TOKEN on GHES)
+// 3. gh CLI configuration file (~/.config/gh/hosts.yml), for the token's host
+//
+// git credential helpers, via "git credential fill", are only asked when the
+// order lists them.
 package token
 
 import (
-	"path/filepath"
+	"fmt"
 	"strings"
 
 	"github.com/josejulio/ghautodelete/internal/errors"
 	"github.com/josejulio/ghautodelete/pkg/interfaces"
-	"gopkg.in/yaml.v3"
 )
 
 // TokenProvider retrieves GitHub API tokens from various sources.
 //
 // It uses dependency injection for all external dependencies (environment variables,
-// home directory, and file system access) to enable comprehensive testing.
+// home directory, file system access and external commands) to enable comprehensive testing.
 type TokenProvider struct {
+	hostname      string
 	explicitToken string
 	envGetter     func(string) string
 	homeGetter    func() (string, error)
 	fileReader    func(string) ([]byte, error)
+
+	order        []string
+	tokenFile    string
+	tokenCommand string
+	runCommand   CommandRunner
+	trace        func(string)
+}
+
+// ProviderOption configures optional TokenProvider sources and behavior.
+type ProviderOption func(*TokenProvider)
+
+// WithSourceOrder sets the order in which token sources are tried.
+// Use ParseSourceOrder to obtain a valid order.
+func WithSourceOrder(order []string) ProviderOption {
+	return func(p *TokenProvider) {
+		p.order = order
+	}
 }
 
-// NewTokenProvider creates a new TokenProvider with dependency injection.
+// WithTokenFile enables the file source, reading the token from path.
+func WithTokenFile(path string) ProviderOption {
+	return func(p *TokenProvider) {
+		p.tokenFile = path
+	}
+}
+
+// WithTokenCommand enables the command source, using the output of command as the token.
+func WithTokenCommand(command string) ProviderOption {
+	return func(p *TokenProvider) {
+		p.tokenCommand = command
+	}
+}
+
+// WithCommandRunner sets how external commands are run. Without a runner the
+// command and git credential sources are unavailable.
+func WithCommandRunner(run CommandRunner) ProviderOption {
+	return func(p *TokenProvider) {
+		p.runCommand = run
+	}
+}
+
+// WithTrace sets a function receiving one message per source tried, e.g. the
+// verbose output. Messages name the sources but never contain a token.
+func WithTrace(trace func(string)) ProviderOption {
+	return func(p *TokenProvider) {
+		p.trace = trace
+	}
+}
+
+// defaultHostname is the host whose token NewTokenProvider retrieves.
+const defaultHostname = "github.com"
+
+// NewTokenProvider creates a new TokenProvider for github.com with dependency injection.
 //
 // Parameters:
 //   - explicitToken: Token passed via CLI flag (highest priority)
 //   - envGetter: Function to retrieve environment variables
 //   - homeGetter: Function to retrieve the user's home directory
 //   - fileReader: Function to read file contents
+//   - opts: Optional sources and behavior, e.g. WithTokenFile or WithSourceOrder
 //
 // Returns a TokenProvider that implements the ITokenProvider interface.
 func NewTokenProvider(
@@ -41,102 +96,122 @@ func NewTokenProvider(
 	envGetter func(string) string,
 	homeGetter func() (string, error),
 	fileReader func(string) ([]byte, error),
+	opts ...ProviderOption,
 ) *TokenProvider {
-	return &TokenProvider{
+	return NewTokenProviderForHostname(defaultHostname, explicitToken, envGetter, homeGetter, fileReader, opts...)
+}
+
+// NewTokenProviderForHostname creates a new TokenProvider for the given GitHub hostname
+// (e.g., a GitHub Enterprise Server host). Parameters are as for NewTokenProvider.
+func NewTokenProviderForHostname(
+	hostname string,
+	explicitToken string,
+	envGetter func(string) string,
+	homeGetter func() (string, error),
+	fileReader func(string) ([]byte, error),
+	opts ...ProviderOption,
+) *TokenProvider {
+	p := &TokenProvider{
+		hostname:      strings.ToLower(hostname),
 		explicitToken: explicitToken,
 		envGetter:     envGetter,
 		homeGetter:    homeGetter,
 		fileReader:    fileReader,
 	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
-// GetToken retrieves the GitHub API token using the precedence order.
+// GetToken retrieves the GitHub API token.
 //
-// Precedence (highest to lowest):
-// 1. Explicit token from CLI flag (if non-empty after trimming)
-// 2. GITHUB_TOKEN environment variable (if non-empty after trimming)
-// 3. gh CLI configuration file at ~/.config/gh/hosts.yml
+// The explicit token from the CLI flag is returned if non-empty after trimming;
+// otherwise each source is tried in order and the first non-empty token is returned.
 //
 // Returns:
 //   - The token string (trimmed of whitespace)
-//   - An error if no token source is available
+//   - An error if a configured source fails or no source has a token
 //
 // The error returned when no token is found is an *AppError with code
 // ErrAuthenticationFailed and includes actionable guidance.
 func (p *TokenProvider) GetToken() (string, error) {
-	// Priority 1: Explicit token from CLI flag
 	trimmedExplicit := strings.TrimSpace(p.explicitToken)
 	if trimmedExplicit != "" {
+		p.tracef("Using token from --token flag")
 		return trimmedExplicit, nil
 	}
 
-	// Priority 2: GITHUB_TOKEN environment variable
-	envToken := p.envGetter("GITHUB_TOKEN")
-	trimmedEnv := strings.TrimSpace(envToken)
-	if trimmedEnv != "" {
-		return trimmedEnv, nil
-	}
-
-	// Priority 3: gh CLI configuration file
-	ghToken, err := p.getTokenFromGhConfig()
-	if err == nil && ghToken != "" {
-		return ghToken, nil
+	for _, source := range p.sources() {
+		token, err := source.Token()
+		if err != nil {
+			return "", err
+		}
+		if token != "" {
+			p.tracef("Using token from %s", source.Name())
+			return token, nil
+		}
+		p.tracef("No token from %s", source.Name())
 	}
 
 	// No token found from any source
 	return "", errors.NewAuthenticationError(
-		"No GitHub token found. Set GITHUB_TOKEN environment variable or use --token flag",
+		fmt.Sprintf("No GitHub token found for %s. Set %s environment variable or use --token flag",
+			p.hostname, strings.Join(p.envVariables(), " or ")),
 		nil,
 	)
 }
 
-// getTokenFromGhConfig retrieves the token from gh CLI configuration.
-//
-// It reads ~/.config/gh/hosts.yml and extracts the oauth_token for github.com.
-//
-// Returns:
-//   - The token string (trimmed of whitespace)
-//   - An error if the file cannot be read, parsed, or doesn't contain the token
-func (p *TokenProvider) getTokenFromGhConfig() (string, error) {
-	// Get home directory
-	homeDir, err := p.homeGetter()
-	if err != nil {
-		return "", err
-	}
-
-	// Construct path to gh config file
-	ghConfigPath := filepath.Join(homeDir, ".config", "gh", "hosts.yml")
-
-	// Read the file
-	content, err := p.fileReader(ghConfigPath)
-	if err != nil {
-		return "", err
+// sources returns the configured token sources in order. The file, command and
+// git credential sources are left out when they are not configured.
+func (p *TokenProvider) sources() []interfaces.ITokenSource {
+	order := p.order
+	if order == nil {
+		order = DefaultSourceOrder
 	}
 
-	// Parse YAML
-	var hosts map[string]map[string]interface{}
-	if err := yaml.Unmarshal(content, &hosts); err != nil {
-		return "", err
-	}
-
-	// Extract token for github.com
-	githubHost, ok := hosts["github.com"]
-	if !ok {
-		return "", nil // github.com not found, return empty token
+	variables := p.envVariables()
+	var sources []interfaces.ITokenSource
+	for _, name := range order {
+		switch name {
+		case SourceFile:
+			if p.tokenFile != "" {
+				sources = append(sources, &fileSource{path: p.tokenFile, fileReader: p.fileReader})
+			}
+		case SourceCommand:
+			if p.tokenCommand != "" && p.runCommand != nil {
+				sources = append(sources, &commandSource{command: p.tokenCommand, run: p.runCommand})
+			}
+		case SourceGHToken:
+			sources = append(sources, &envSource{variable: variables[0], envGetter: p.envGetter})
+		case SourceGitHubToken:
+			sources = append(sources, &envSource{variable: variables[1], envGetter: p.envGetter})
+		case SourceGhHosts:
+			sources = append(sources, &ghHostsSource{hostname: p.hostname, homeGetter: p.homeGetter, fileReader: p.fileReader})
+		case SourceGitCredential:
+			if p.runCommand != nil {
+				sources = append(sources, &gitCredentialSource{hostname: p.hostname, run: p.runCommand})
+			}
+		}
 	}
+	return sources
+}
 
-	oauthToken, ok := githubHost["oauth_token"]
-	if !ok {
-		return "", nil // oauth_token not found, return empty token
+// envVariables returns the GH_TOKEN-style and GITHUB_TOKEN-style environment
+// variables for the host. Like the gh CLI, GitHub Enterprise Server hosts use
+// their own variables and never GH_TOKEN or GITHUB_TOKEN.
+func (p *TokenProvider) envVariables() []string {
+	if p.hostname == defaultHostname {
+		return []string{"GH_TOKEN", "GITHUB_TOKEN"}
 	}
+	return []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+}
 
-	// Convert to string and trim
-	tokenStr, ok := oauthToken.(string)
-	if !ok {
-		return "", nil // oauth_token is not a string, return empty token
+// tracef sends a formatted message to the trace function, if one is set.
+func (p *TokenProvider) tracef(format string, args ...interface{}) {
+	if p.trace != nil {
+		p.trace(fmt.Sprintf(format, args...))
 	}
-
-	return strings.TrimSpace(tokenStr), nil
 }
 
 // Ensure TokenProvider implements ITokenProvider interface
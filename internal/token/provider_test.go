@@ -872,7 +872,7 @@ func TestNewTokenProviderAcceptsAllDependencies(t *testing.T) {
 // TestNewTokenProviderDependenciesCalledInOrder verifies fallback order.
 //
 // The implementation should:
-// - Call dependencies in order: explicit -> env -> gh config
+// - Call dependencies in order: explicit -> GH_TOKEN -> GITHUB_TOKEN -> gh config
 func TestNewTokenProviderDependenciesCalledInOrder(t *testing.T) {
 	// Arrange
 	callOrder := []string{}
@@ -904,18 +904,10 @@ func TestNewTokenProviderDependenciesCalledInOrder(t *testing.T) {
 	if result != ghConfigToken {
 		t.Errorf("GetToken() = %q, expected %q", result, ghConfigToken)
 	}
-	// Verify call order: first check env, then home, then file
-	if len(callOrder) != 3 {
-		t.Errorf("Expected 3 dependency calls, got %d: %v", len(callOrder), callOrder)
-	}
-	if len(callOrder) >= 1 && callOrder[0] != "envGetter" {
-		t.Errorf("First call should be envGetter, got %v", callOrder)
-	}
-	if len(callOrder) >= 2 && callOrder[1] != "homeGetter" {
-		t.Errorf("Second call should be homeGetter, got %v", callOrder)
-	}
-	if len(callOrder) >= 3 && callOrder[2] != "fileReader" {
-		t.Errorf("Third call should be fileReader, got %v", callOrder)
+	// Verify call order: first check GH_TOKEN and GITHUB_TOKEN, then home, then file
+	expected := []string{"envGetter", "envGetter", "homeGetter", "fileReader"}
+	if strings.Join(callOrder, ",") != strings.Join(expected, ",") {
+		t.Errorf("Dependency calls = %v, expected %v", callOrder, expected)
 	}
 }
 
@@ -1074,3 +1066,47 @@ type mockHomeDirectoryError struct{}
 func (e *mockHomeDirectoryError) Error() string {
 	return "could not determine home directory"
 }
+
+// =============================================================================
+// GitHub Enterprise Server Tests
+// =============================================================================
+
+// TestGetTokenForEnterpriseHostname verifies enterprise hosts use their own
+// environment variables and hosts.yml entry, never the github.com token.
+func TestGetTokenForEnterpriseHostname(t *testing.T) {
+	hostsYAML := `github.com:
+  oauth_token: ghp_github_com_token
+git.corp.example:
+  oauth_token: ghp_enterprise_token`
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected string
+	}{
+		{"GH_ENTERPRISE_TOKEN", map[string]string{"GH_ENTERPRISE_TOKEN": "ghp_gh_env", "GITHUB_ENTERPRISE_TOKEN": "ghp_github_env"}, "ghp_gh_env"},
+		{"GITHUB_ENTERPRISE_TOKEN", map[string]string{"GITHUB_ENTERPRISE_TOKEN": "ghp_github_env"}, "ghp_github_env"},
+		{"GITHUB_TOKEN ignored", map[string]string{"GITHUB_TOKEN": "ghp_public"}, "ghp_enterprise_token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			envGetter := func(key string) string { return tt.env[key] }
+			homeGetter := func() (string, error) { return "/home/testuser", nil }
+			fileReader := func(path string) ([]byte, error) { return []byte(hostsYAML), nil }
+			provider := token.NewTokenProviderForHostname("Git.Corp.Example", "", envGetter, homeGetter, fileReader)
+
+			// Act
+			result, err := provider.GetToken()
+
+			// Assert
+			if err != nil {
+				t.Fatalf("GetToken() unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("GetToken() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
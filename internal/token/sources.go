@@ -0,0 +1,267 @@
+// Package token provides the token sources TokenProvider chains together.
+//
+// Each source implements interfaces.ITokenSource and reports an empty token
+// when it has nothing to offer, so the chain moves on to the next source.
+// Sources the user configured explicitly (a token file or command) report
+// failures as errors instead, since silently falling back would hide them.
+package token
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/pkg/interfaces"
+	"gopkg.in/yaml.v3"
+)
+
+// Token source names, as accepted by ParseSourceOrder.
+const (
+	SourceFile          = "file"
+	SourceCommand       = "command"
+	SourceGHToken       = "gh-token"
+	SourceGitHubToken   = "github-token"
+	SourceGhHosts       = "gh-hosts"
+	SourceGitCredential = "git-credential"
+)
+
+// DefaultSourceOrder is the order in which token sources are tried when no
+// order is configured. Sources the user configured explicitly come first.
+// The git credential source is left out: a helper such as Git Credential
+// Manager may open a sign-in window, so it is only asked when listed.
+var DefaultSourceOrder = []string{
+	SourceFile,
+	SourceCommand,
+	SourceGHToken,
+	SourceGitHubToken,
+	SourceGhHosts,
+}
+
+// knownSources are the source names accepted by ParseSourceOrder.
+var knownSources = append(append([]string(nil), DefaultSourceOrder...), SourceGitCredential)
+
+// commandTimeout bounds how long a credential helper or token command may run.
+const commandTimeout = 30 * time.Second
+
+// CommandRunner runs an external program with the given standard input and
+// returns its standard output.
+type CommandRunner func(stdin string, name string, args ...string) (string, error)
+
+// ExecCommand is the CommandRunner used in production. It never lets git
+// prompt on the terminal or Git Credential Manager open a sign-in window, so a
+// missing credential fails instead of hanging.
+func ExecCommand(stdin string, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GCM_INTERACTIVE=never")
+
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// ParseSourceOrder parses a comma-separated list of token source names.
+//
+// Parameters:
+//   - value: the list, e.g. "gh-hosts,github-token"; empty selects DefaultSourceOrder
+//
+// Returns:
+//   - The source names in order
+//   - A validation error naming the first unknown or repeated source
+func ParseSourceOrder(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return DefaultSourceOrder, nil
+	}
+
+	seen := make(map[string]bool)
+	var order []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if !isKnownSource(name) {
+			return nil, errors.NewValidationError(fmt.Sprintf("Invalid token source %q. Expected one of: %s",
+				name, strings.Join(knownSources, ", ")))
+		}
+		if seen[name] {
+			return nil, errors.NewValidationError(fmt.Sprintf("Token source %q is listed more than once", name))
+		}
+		seen[name] = true
+		order = append(order, name)
+	}
+	return order, nil
+}
+
+// CheckConfiguredSources reports a token file or command that is configured
+// but would never be read because order leaves its source out.
+//
+// Parameters:
+//   - order: the source order, e.g. from ParseSourceOrder
+//   - tokenFile, tokenCommand: the configured file and command; empty when not set
+//
+// Returns a validation error naming the first configured source missing from order.
+func CheckConfiguredSources(order []string, tokenFile, tokenCommand string) error {
+	configured := []struct {
+		source, flag, value string
+	}{
+		{SourceFile, "--token-file", tokenFile},
+		{SourceCommand, "--token-command", tokenCommand},
+	}
+	for _, c := range configured {
+		if c.value == "" || containsSource(order, c.source) {
+			continue
+		}
+		return errors.NewValidationError(fmt.Sprintf(
+			"%s is set but --token-sources does not include %q; add it to the list or drop %s", c.flag, c.source, c.flag))
+	}
+	return nil
+}
+
+// containsSource reports whether order includes source.
+func containsSource(order []string, source string) bool {
+	for _, name := range order {
+		if name == source {
+			return true
+		}
+	}
+	return false
+}
+
+// isKnownSource reports whether name is one of the known sources.
+func isKnownSource(name string) bool {
+	return containsSource(knownSources, name)
+}
+
+// envSource reads a token from an environment variable.
+type envSource struct {
+	variable  string
+	envGetter func(string) string
+}
+
+func (s *envSource) Name() string { return s.variable }
+
+func (s *envSource) Token() (string, error) {
+	return strings.TrimSpace(s.envGetter(s.variable)), nil
+}
+
+// fileSource reads a token from the file given with --token-file.
+type fileSource struct {
+	path       string
+	fileReader func(string) ([]byte, error)
+}
+
+func (s *fileSource) Name() string { return fmt.Sprintf("token file %s", s.path) }
+
+func (s *fileSource) Token() (string, error) {
+	content, err := s.fileReader(s.path)
+	if err != nil {
+		return "", errors.NewAuthenticationError(fmt.Sprintf("Cannot read token file %s", s.path), err)
+	}
+
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", errors.NewAuthenticationError(fmt.Sprintf("Token file %s is empty", s.path), nil)
+	}
+	return token, nil
+}
+
+// commandSource runs the command given with --token-command and uses its output.
+// The command is split on whitespace and run without a shell.
+type commandSource struct {
+	command string
+	run     CommandRunner
+}
+
+func (s *commandSource) Name() string { return fmt.Sprintf("token command %q", s.command) }
+
+func (s *commandSource) Token() (string, error) {
+	fields := strings.Fields(s.command)
+	if len(fields) == 0 {
+		return "", errors.NewValidationError("--token-command is empty")
+	}
+
+	out, err := s.run("", fields[0], fields[1:]...)
+	if err != nil {
+		return "", errors.NewAuthenticationError(fmt.Sprintf("Token command %q failed", s.command), err)
+	}
+
+	token := strings.TrimSpace(out)
+	if token == "" {
+		return "", errors.NewAuthenticationError(fmt.Sprintf("Token command %q printed no token", s.command), nil)
+	}
+	return token, nil
+}
+
+// gitCredentialSource asks git's configured credential helpers for the host's
+// password using the "git credential fill" protocol.
+type gitCredentialSource struct {
+	hostname string
+	run      CommandRunner
+}
+
+func (s *gitCredentialSource) Name() string { return "git credential helper" }
+
+func (s *gitCredentialSource) Token() (string, error) {
+	request := fmt.Sprintf("protocol=https\nhost=%s\n\n", s.hostname)
+	out, err := s.run(request, "git", "credential", "fill")
+	if err != nil {
+		// No git, or no helper holding a credential for the host
+		return "", nil
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		if password, ok := strings.CutPrefix(line, "password="); ok {
+			return strings.TrimSpace(password), nil
+		}
+	}
+	return "", nil
+}
+
+// ghHostsSource reads the host's oauth_token from the gh CLI configuration
+// (~/.config/gh/hosts.yml).
+type ghHostsSource struct {
+	hostname   string
+	homeGetter func() (string, error)
+	fileReader func(string) ([]byte, error)
+}
+
+func (s *ghHostsSource) Name() string { return "gh CLI configuration" }
+
+// Token returns an empty token if the file cannot be read or parsed, or has
+// no string oauth_token for the host.
+func (s *ghHostsSource) Token() (string, error) {
+	homeDir, err := s.homeGetter()
+	if err != nil {
+		return "", nil
+	}
+
+	content, err := s.fileReader(filepath.Join(homeDir, ".config", "gh", "hosts.yml"))
+	if err != nil {
+		return "", nil
+	}
+
+	var hosts map[string]map[string]interface{}
+	if err := yaml.Unmarshal(content, &hosts); err != nil {
+		return "", nil
+	}
+
+	oauthToken, ok := hosts[s.hostname]["oauth_token"].(string)
+	if !ok {
+		return "", nil
+	}
+	return strings.TrimSpace(oauthToken), nil
+}
+
+// Ensure the sources implement ITokenSource interface
+var (
+	_ interfaces.ITokenSource = (*envSource)(nil)
+	_ interfaces.ITokenSource = (*fileSource)(nil)
+	_ interfaces.ITokenSource = (*commandSource)(nil)
+	_ interfaces.ITokenSource = (*gitCredentialSource)(nil)
+	_ interfaces.ITokenSource = (*ghHostsSource)(nil)
+)
@@ -0,0 +1,258 @@
+// Package token_test provides tests for the token source chain.
+//
+// These tests verify the file, command and git credential sources, the
+// configurable source order and that the trace never contains a token.
+package token_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	apperrors "github.com/josejulio/ghautodelete/internal/errors"
+	"github.com/josejulio/ghautodelete/internal/token"
+)
+
+// fakeRunner records the commands it runs and answers with the given outputs,
+// keyed by program name. Programs without an output fail.
+type fakeRunner struct {
+	outputs map[string]string
+	stdins  map[string]string
+}
+
+func (r *fakeRunner) run(stdin string, name string, args ...string) (string, error) {
+	if r.stdins == nil {
+		r.stdins = make(map[string]string)
+	}
+	r.stdins[name] = stdin
+	out, ok := r.outputs[name]
+	if !ok {
+		return "", errors.New("exit status 1")
+	}
+	return out, nil
+}
+
+// newChainProvider creates a provider for github.com whose environment, hosts.yml
+// and files are served from the given maps.
+func newChainProvider(env, files map[string]string, opts ...token.ProviderOption) *token.TokenProvider {
+	envGetter := func(key string) string { return env[key] }
+	homeGetter := func() (string, error) { return "/home/test", nil }
+	fileReader := func(path string) ([]byte, error) {
+		content, ok := files[path]
+		if !ok {
+			return nil, errors.New("no such file or directory")
+		}
+		return []byte(content), nil
+	}
+	return token.NewTokenProvider("", envGetter, homeGetter, fileReader, opts...)
+}
+
+// TestGetTokenFromSourceChain verifies each source and the configured order.
+func TestGetTokenFromSourceChain(t *testing.T) {
+	hosts := map[string]string{"/home/test/.config/gh/hosts.yml": "github.com:\n  oauth_token: ghp_hosts\n"}
+	credential := &fakeRunner{outputs: map[string]string{
+		"git":  "protocol=https\nhost=github.com\nusername=x-access-token\npassword=ghp_credential\n",
+		"pass": "ghp_command\n",
+	}}
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		files    map[string]string
+		opts     []token.ProviderOption
+		expected string
+	}{
+		{"GH_TOKEN before GITHUB_TOKEN", map[string]string{"GH_TOKEN": "ghp_gh", "GITHUB_TOKEN": "ghp_github"}, nil, nil, "ghp_gh"},
+		{"token file before environment", map[string]string{"GH_TOKEN": "ghp_gh"},
+			map[string]string{"token.txt": " ghp_file\n"}, []token.ProviderOption{token.WithTokenFile("token.txt")}, "ghp_file"},
+		{"token command", nil, nil,
+			[]token.ProviderOption{token.WithTokenCommand("pass show github"), token.WithCommandRunner(credential.run)}, "ghp_command"},
+		{"git credential helper", nil, nil,
+			[]token.ProviderOption{token.WithSourceOrder([]string{token.SourceGitCredential}), token.WithCommandRunner(credential.run)}, "ghp_credential"},
+		{"configured order", map[string]string{"GITHUB_TOKEN": "ghp_github"}, hosts,
+			[]token.ProviderOption{token.WithSourceOrder([]string{token.SourceGhHosts, token.SourceGitHubToken})}, "ghp_hosts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			provider := newChainProvider(tt.env, tt.files, tt.opts...)
+
+			// Act
+			result, err := provider.GetToken()
+
+			// Assert
+			if err != nil {
+				t.Fatalf("GetToken() unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("GetToken() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+
+	if stdin := credential.stdins["git"]; stdin != "protocol=https\nhost=github.com\n\n" {
+		t.Errorf("git credential fill stdin = %q", stdin)
+	}
+}
+
+// TestGetTokenDefaultOrderNeverRunsGit verifies the default chain does not ask
+// git credential helpers, which may open a sign-in window.
+func TestGetTokenDefaultOrderNeverRunsGit(t *testing.T) {
+	// Arrange
+	runner := &fakeRunner{outputs: map[string]string{"git": "password=ghp_credential\n"}}
+	provider := newChainProvider(nil, nil, token.WithCommandRunner(runner.run))
+
+	// Act
+	_, err := provider.GetToken()
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 3 {
+		t.Errorf("exit code = %d, expected 3 (err: %v)", code, err)
+	}
+	if _, ran := runner.stdins["git"]; ran {
+		t.Error("the default source order should not run git credential fill")
+	}
+}
+
+// TestGetTokenSourceOrderExcludesUnlistedSources verifies sources left out of the order are not used.
+func TestGetTokenSourceOrderExcludesUnlistedSources(t *testing.T) {
+	// Arrange
+	provider := newChainProvider(map[string]string{"GITHUB_TOKEN": "ghp_github"}, nil,
+		token.WithSourceOrder([]string{token.SourceGHToken}))
+
+	// Act
+	_, err := provider.GetToken()
+
+	// Assert
+	if code := apperrors.GetExitCode(err); code != 3 {
+		t.Errorf("exit code = %d, expected 3 (err: %v)", code, err)
+	}
+}
+
+// TestGetTokenConfiguredSourceFailures verifies an unusable token file or command
+// fails instead of falling back to another source.
+func TestGetTokenConfiguredSourceFailures(t *testing.T) {
+	runner := &fakeRunner{outputs: map[string]string{"empty": "\n"}}
+
+	tests := []struct {
+		name string
+		opt  token.ProviderOption
+	}{
+		{"missing token file", token.WithTokenFile("missing.txt")},
+		{"failing command", token.WithTokenCommand("pass show github")},
+		{"command without output", token.WithTokenCommand("empty")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			provider := newChainProvider(map[string]string{"GITHUB_TOKEN": "ghp_github"}, nil,
+				tt.opt, token.WithCommandRunner(runner.run))
+
+			// Act
+			result, err := provider.GetToken()
+
+			// Assert
+			if result != "" {
+				t.Errorf("GetToken() = %q, expected no token", result)
+			}
+			if code := apperrors.GetExitCode(err); code != 3 {
+				t.Errorf("exit code = %d, expected 3 (err: %v)", code, err)
+			}
+		})
+	}
+}
+
+// TestGetTokenTraceNamesSourcesOnly verifies the trace reports which source
+// supplied the token without revealing it.
+func TestGetTokenTraceNamesSourcesOnly(t *testing.T) {
+	// Arrange
+	var trace []string
+	provider := newChainProvider(map[string]string{"GITHUB_TOKEN": "ghp_secret"}, nil,
+		token.WithTrace(func(message string) { trace = append(trace, message) }))
+
+	// Act
+	_, err := provider.GetToken()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("GetToken() unexpected error: %v", err)
+	}
+	expected := []string{"No token from GH_TOKEN", "Using token from GITHUB_TOKEN"}
+	if strings.Join(trace, "|") != strings.Join(expected, "|") {
+		t.Errorf("trace = %q, expected %q", trace, expected)
+	}
+}
+
+// TestParseSourceOrder verifies source lists are parsed and validated.
+func TestParseSourceOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+		wantErr  bool
+	}{
+		{"default", "", strings.Join(token.DefaultSourceOrder, ","), false},
+		{"custom", "gh-hosts, github-token", "gh-hosts,github-token", false},
+		{"git credential", "gh-hosts,git-credential", "gh-hosts,git-credential", false},
+		{"unknown source", "gh-hosts,keychain", "", true},
+		{"repeated source", "file,file", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			order, err := token.ParseSourceOrder(tt.value)
+
+			// Assert
+			if tt.wantErr {
+				if code := apperrors.GetExitCode(err); code != 2 {
+					t.Errorf("exit code = %d, expected 2 (err: %v)", code, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSourceOrder() unexpected error: %v", err)
+			}
+			if strings.Join(order, ",") != tt.expected {
+				t.Errorf("ParseSourceOrder() = %v, expected %s", order, tt.expected)
+			}
+		})
+	}
+}
+
+// TestCheckConfiguredSources verifies a token file or command left out of the
+// source order is a validation error instead of being ignored.
+func TestCheckConfiguredSources(t *testing.T) {
+	tests := []struct {
+		name         string
+		order        []string
+		tokenFile    string
+		tokenCommand string
+		wantErr      string
+	}{
+		{name: "default order", order: token.DefaultSourceOrder, tokenFile: "token.txt", tokenCommand: "pass show github"},
+		{name: "nothing configured", order: []string{token.SourceGhHosts}},
+		{name: "file left out", order: []string{token.SourceGhHosts}, tokenFile: "token.txt", wantErr: "--token-file"},
+		{name: "command left out", order: []string{token.SourceFile}, tokenCommand: "pass show github", wantErr: "--token-command"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := token.CheckConfiguredSources(tt.order, tt.tokenFile, tt.tokenCommand)
+
+			// Assert
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("CheckConfiguredSources() unexpected error: %v", err)
+				}
+				return
+			}
+			if code := apperrors.GetExitCode(err); code != 2 || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CheckConfiguredSources() error = %v (code %d), expected a validation error naming %s", err, code, tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,7 +4,10 @@
 // and testability throughout the application.
 package interfaces
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // IGitHubClient provides methods for interacting with the GitHub API.
 // It abstracts repository operations and token validation.
@@ -20,6 +23,35 @@ type IGitHubClient interface {
 	// ValidateToken validates the GitHub API token and returns token information.
 	// Returns ITokenInfo containing scopes and user details.
 	ValidateToken(ctx context.Context) (ITokenInfo, error)
+
+	// ListOrganizationRepositories lists every repository of an organization.
+	// Follows pagination until all pages have been retrieved.
+	ListOrganizationRepositories(ctx context.Context, org string) ([]IRepository, error)
+
+	// ListUserRepositories lists the repositories of the authenticated user.
+	// affiliation is a comma-separated subset of "owner", "collaborator" and
+	// "organization_member". Follows pagination until all pages have been retrieved.
+	ListUserRepositories(ctx context.Context, affiliation string) ([]IRepository, error)
+
+	// SearchRepositories lists the repositories matching a GitHub search query
+	// (e.g., "org:acme topic:backend archived:false"). Follows pagination until
+	// all pages have been retrieved. GitHub returns at most 1,000 results per
+	// query; total is the number of matches it reported, which may be larger.
+	SearchRepositories(ctx context.Context, query string) (repos []IRepository, total int, err error)
+
+	// ListEnterpriseOrganizations lists the logins of an enterprise's member
+	// organizations, ordered by login. Follows pagination until all pages have
+	// been retrieved.
+	ListEnterpriseOrganizations(ctx context.Context, slug string) ([]string, error)
+}
+
+// IRepositoryPrefetcher is implemented by GitHub clients that can read many
+// repositories at once. Multi-repository runs call it before processing, so the
+// per-repository reads that follow are served without further requests.
+type IRepositoryPrefetcher interface {
+	// PrefetchRepositories reads the given "owner/name" repositories in bulk.
+	// Repositories that cannot be read are skipped; reading them again reports the error.
+	PrefetchRepositories(ctx context.Context, fullNames []string) error
 }
 
 // IRepoParser provides methods for parsing repository identifiers.
@@ -38,6 +70,78 @@ type ITokenProvider interface {
 	GetToken() (string, error)
 }
 
+// IContextTokenProvider is implemented by token providers that make a request
+// to obtain a token, such as a GitHub App exchanging its JWT. The GitHub client
+// passes the context of the API call, so cancelling the run cancels the request.
+type IContextTokenProvider interface {
+	// GetTokenContext retrieves the GitHub API token, making any request with ctx.
+	GetTokenContext(ctx context.Context) (string, error)
+}
+
+// ITokenSource is a single place a GitHub API token can be read from, such as
+// an environment variable or a credential helper. Token providers try a chain
+// of sources in order.
+type ITokenSource interface {
+	// Name describes the source in verbose output. It never contains the token.
+	Name() string
+
+	// Token returns the token, or an empty string if the source has none.
+	// An error means the source is configured but unusable.
+	Token() (string, error)
+}
+
+// IRateLimiter paces GitHub API requests to stay within the rate-limit budget.
+// Budgets are tracked per rate-limit resource (e.g., "core", "search", "graphql").
+type IRateLimiter interface {
+	// Wait blocks until a request against resource may be sent. It slows down
+	// as the budget shrinks; once the budget is exhausted it either waits for the
+	// reset or returns an error with code ErrAPIRateLimited.
+	Wait(ctx context.Context, resource string) error
+
+	// Update records the budget reported by a response.
+	Update(budget RateLimitBudget)
+
+	// Release gives back the request reserved by Wait when the response did
+	// not count against the budget, e.g. 304 Not Modified.
+	Release(resource string)
+
+	// Pause holds back every request for d, e.g. after a secondary rate limit.
+	Pause(d time.Duration)
+
+	// Budget returns the most recently reported budget for resource and whether
+	// one has been reported.
+	Budget(resource string) (RateLimitBudget, bool)
+}
+
+// IResponseCache stores GitHub API responses for conditional requests.
+// A cached response is revalidated with its ETag or Last-Modified time before
+// it is used, so the cache never serves stale data.
+type IResponseCache interface {
+	// Get returns the response stored under key and whether there is one.
+	Get(key string) (CachedResponse, bool)
+
+	// Set stores response under key, replacing any previous response.
+	Set(key string, response CachedResponse) error
+}
+
+// IChangeLog records repository setting changes as audit evidence.
+type IChangeLog interface {
+	// Record appends one applied change. The log stamps the time, tool version
+	// and run ID; the caller supplies the rest.
+	Record(change ChangeRecord) error
+}
+
+// ICheckpoint records the outcome of each repository of a batch run as it
+// completes, so an interrupted run can be resumed.
+type ICheckpoint interface {
+	// Status returns the status the repository completed with in the run being
+	// resumed, or an empty string if it failed or was not processed.
+	Status(repository string) string
+
+	// Record stores the outcome of one repository. The checkpoint stamps the time.
+	Record(outcome CheckpointRecord) error
+}
+
 // IOutputWriter provides methods for writing output messages.
 // It abstracts output operations for different verbosity levels.
 type IOutputWriter interface {
@@ -54,6 +158,13 @@ type IOutputWriter interface {
 	Verbose(message string)
 }
 
+// IResultReporter provides methods for reporting per-repository outcomes
+// in a machine-readable form.
+type IResultReporter interface {
+	// Report records the outcome of processing one repository.
+	Report(report RepositoryReport)
+}
+
 // IConfigService provides methods for configuring repository settings.
 // It orchestrates the process of checking and updating repository configuration.
 type IConfigService interface {
@@ -64,6 +175,20 @@ type IConfigService interface {
 	// CheckStatus checks the current delete-branch-on-merge setting status.
 	// Returns IConfigResult containing the current configuration state.
 	CheckStatus(ctx context.Context, owner, name string) (IConfigResult, error)
+
+	// Disable turns the delete-branch-on-merge setting off for a repository.
+	// Returns IConfigResult where WasAlreadyEnabled reports the state before the call.
+	Disable(ctx context.Context, owner, name string, dryRun bool) (IConfigResult, error)
+
+	// ValidateToken validates the API token before any repository is processed.
+	// With write, it also confirms the token's scopes allow changing repository settings.
+	// Returns ITokenInfo containing scopes and user details.
+	ValidateToken(ctx context.Context, write bool) (ITokenInfo, error)
+
+	// Reconcile brings a repository's settings in line with the desired settings.
+	// Only settings that differ are updated; with dryRun nothing is updated.
+	// Returns the settings that differed before the call.
+	Reconcile(ctx context.Context, owner, name string, desired IRepositorySettings, dryRun bool) ([]SettingChange, error)
 }
 
 // IRepository provides methods for accessing repository information.
@@ -83,13 +208,69 @@ type IRepository interface {
 
 	// GetFullName returns the full repository name in "owner/name" format.
 	GetFullName() string
+
+	// IsArchived returns whether the repository is archived (read-only).
+	IsArchived() bool
+
+	// IsFork returns whether the repository is a fork of another repository.
+	IsFork() bool
+
+	// IsTemplate returns whether the repository is a template repository.
+	IsTemplate() bool
+
+	// IsPrivate returns whether the repository is private (or internal).
+	IsPrivate() bool
+
+	// GetVisibility returns the repository visibility: "public", "private" or "internal".
+	GetVisibility() string
+
+	// GetTopics returns the repository topics.
+	GetTopics() []string
+
+	// GetLanguage returns the repository's primary language, or "" if none was detected.
+	GetLanguage() string
+
+	// GetPushedAt returns when the repository was last pushed to (zero if never).
+	GetPushedAt() time.Time
+
+	// GetSettings returns the repository's current merge-related settings.
+	// Settings the API did not report (e.g., without admin access) are nil.
+	GetSettings() IRepositorySettings
 }
 
 // IRepositorySettings provides methods for accessing repository settings.
 // It represents the settings that can be applied to a repository.
+//
+// Apart from delete-branch-on-merge, every setting is optional: a nil getter result
+// means the setting is left unchanged by an update.
 type IRepositorySettings interface {
 	// GetDeleteBranchOnMerge returns whether delete-branch-on-merge should be enabled.
 	GetDeleteBranchOnMerge() bool
+
+	// HasDeleteBranchOnMerge returns whether delete-branch-on-merge is part of the update.
+	HasDeleteBranchOnMerge() bool
+
+	// GetAllowSquashMerge returns whether squash merging should be allowed, or nil to leave it unchanged.
+	GetAllowSquashMerge() *bool
+
+	// GetAllowMergeCommit returns whether merge commits should be allowed, or nil to leave it unchanged.
+	GetAllowMergeCommit() *bool
+
+	// GetAllowRebaseMerge returns whether rebase merging should be allowed, or nil to leave it unchanged.
+	GetAllowRebaseMerge() *bool
+
+	// GetAllowAutoMerge returns whether auto-merge should be allowed, or nil to leave it unchanged.
+	GetAllowAutoMerge() *bool
+
+	// GetAllowUpdateBranch returns whether pull request branches may be updated from the base branch,
+	// or nil to leave it unchanged.
+	GetAllowUpdateBranch() *bool
+
+	// GetSquashMergeCommitTitle returns the default squash commit title source, or nil to leave it unchanged.
+	GetSquashMergeCommitTitle() *string
+
+	// GetSquashMergeCommitMessage returns the default squash commit message source, or nil to leave it unchanged.
+	GetSquashMergeCommitMessage() *string
 }
 
 // ITokenInfo provides methods for accessing GitHub token information.
@@ -121,6 +302,129 @@ type IConfigResult interface {
 	GetRepositoryFullName() string
 }
 
+// SettingChange describes one repository setting whose current value differs
+// from the desired value. Values are formatted for display; an unknown current
+// value is reported as "unknown".
+type SettingChange struct {
+	// Setting is the API name of the setting (e.g., "allow_squash_merge").
+	Setting string `json:"setting"`
+
+	// Current is the value the repository has now.
+	Current string `json:"current"`
+
+	// Desired is the value the repository should have.
+	Desired string `json:"desired"`
+}
+
+// RateLimitBudget is the rate-limit state reported by the X-RateLimit-* response headers.
+type RateLimitBudget struct {
+	// Resource is the rate-limit resource the budget applies to (e.g., "core").
+	Resource string
+
+	// Limit is the number of requests allowed per window.
+	Limit int
+
+	// Remaining is the number of requests left in the current window.
+	Remaining int
+
+	// Used is the number of requests made in the current window.
+	Used int
+
+	// Reset is when the current window ends and the budget is replenished.
+	Reset time.Time
+}
+
+// CachedResponse is a successful GET response kept for conditional requests.
+type CachedResponse struct {
+	// URL is the request URL the response belongs to.
+	URL string `json:"url"`
+
+	// ETag is the response's ETag header, sent back as If-None-Match.
+	ETag string `json:"etag,omitempty"`
+
+	// LastModified is the response's Last-Modified header, sent back as If-Modified-Since.
+	LastModified string `json:"last_modified,omitempty"`
+
+	// Header holds the response headers, restored when the server answers 304 Not Modified.
+	Header map[string][]string `json:"header,omitempty"`
+
+	// Body is the response body.
+	Body []byte `json:"body"`
+}
+
+// ChangeRecord is one repository setting change in the audit log.
+type ChangeRecord struct {
+	// Timestamp is when the change was applied.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Actor is the login the token authenticated as.
+	Actor string `json:"actor"`
+
+	// Repository is the full repository name in "owner/name" format.
+	Repository string `json:"repository"`
+
+	// Setting is the API name of the setting (e.g., "delete_branch_on_merge").
+	Setting string `json:"setting"`
+
+	// Before and After are the formatted values before and after the change.
+	Before string `json:"before"`
+	After  string `json:"after"`
+
+	// Version is the ghautodelete version that made the change.
+	Version string `json:"version"`
+
+	// RunID identifies the ghautodelete run that made the change.
+	RunID string `json:"run_id"`
+}
+
+// CheckpointRecord is the outcome of one repository in a checkpoint file.
+type CheckpointRecord struct {
+	// Timestamp is when the repository completed.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Repository is the full repository name in "owner/name" format.
+	Repository string `json:"repository"`
+
+	// Status is the batch status of the repository (e.g., "enabled", "failed").
+	Status string `json:"status"`
+
+	// Error is the failure message; empty when the repository succeeded.
+	Error string `json:"error,omitempty"`
+}
+
+// RepositoryReport is the machine-readable outcome of processing one repository.
+type RepositoryReport struct {
+	// Repository is the full repository name, or the identifier as given if it could not be resolved.
+	Repository string `json:"repository"`
+
+	// DefaultBranch is the repository's default branch, if known.
+	DefaultBranch string `json:"default_branch,omitempty"`
+
+	// Mode is the operational mode: "audit", "check", "dry-run" or "normal".
+	Mode string `json:"mode"`
+
+	// Operation is what was done: "enable", "disable", "reconcile" or "snapshot".
+	Operation string `json:"operation"`
+
+	// PreviousState is the delete-branch-on-merge state before the operation ("enabled" or "disabled").
+	PreviousState string `json:"previous_state,omitempty"`
+
+	// NewState is the delete-branch-on-merge state after the operation ("enabled" or "disabled").
+	NewState string `json:"new_state,omitempty"`
+
+	// Status is a short description of the outcome (e.g., "enabled", "would enable", "failed").
+	Status string `json:"status"`
+
+	// Changes lists the settings that differed, for the reconcile operation.
+	Changes []SettingChange `json:"changes,omitempty"`
+
+	// Error is the error message if processing failed.
+	Error string `json:"error,omitempty"`
+
+	// ErrorCode is the exit code associated with Error, or 0 on success.
+	ErrorCode int `json:"error_code"`
+}
+
 // CLIOptions represents the command-line options for the application.
 // It contains all user-configurable parameters passed via CLI flags.
 type CLIOptions struct {
@@ -130,6 +434,20 @@ type CLIOptions struct {
 	// Token is the GitHub API token for authentication.
 	Token string
 
+	// TokenFile is a file holding the token; TokenCommand is a command printing it.
+	TokenFile    string
+	TokenCommand string
+
+	// TokenSources is a comma-separated list setting the order in which token
+	// sources are tried, e.g. "gh-hosts,github-token". Empty uses the default order.
+	TokenSources string
+
+	// AppID, AppInstallationID and AppPrivateKeyFile authenticate as a GitHub App
+	// installation instead of with Token. All three must be set together.
+	AppID             int64
+	AppInstallationID int64
+	AppPrivateKeyFile string
+
 	// Verbose enables verbose/debug output.
 	Verbose bool
 
@@ -138,4 +456,99 @@ type CLIOptions struct {
 
 	// CheckOnly enables check-only mode (only check status, don't update).
 	CheckOnly bool
+
+	// Disable turns delete-branch-on-merge off instead of on.
+	Disable bool
+
+	// Audit checks status like CheckOnly and fails with a drift error
+	// if any repository does not have delete-branch-on-merge enabled.
+	Audit bool
+
+	// Hostname is the GitHub host, e.g. a GitHub Enterprise Server host.
+	// Empty means github.com.
+	Hostname string
+
+	// Output selects the output format: "text" (default) or "json".
+	Output string
+
+	// Organization selects every repository of this organization instead of Repository.
+	Organization string
+
+	// User selects every repository of the authenticated user instead of Repository.
+	User bool
+
+	// Search selects every repository matching this GitHub search query instead of Repository.
+	Search string
+
+	// Enterprise selects every repository of every organization of this
+	// enterprise (by slug) instead of Repository.
+	Enterprise string
+
+	// Affiliation restricts User mode to repositories with these affiliations
+	// (comma-separated "owner", "collaborator", "organization_member").
+	Affiliation string
+
+	// SkipArchived excludes archived repositories when listing repositories.
+	SkipArchived bool
+
+	// SkipForks excludes forked repositories when listing repositories.
+	SkipForks bool
+
+	// SkipTemplates excludes template repositories when listing repositories.
+	SkipTemplates bool
+
+	// Topics keeps only listed repositories that have every one of these topics.
+	Topics []string
+
+	// Visibility keeps only listed repositories with this visibility
+	// ("public", "private" or "internal"); empty keeps all.
+	Visibility string
+
+	// Language keeps only listed repositories with this primary language,
+	// compared case-insensitively.
+	Language string
+
+	// Include keeps only listed repositories whose name or full name matches
+	// one of these patterns: globs, or regular expressions written as /regex/.
+	Include []string
+
+	// Exclude drops listed repositories whose name or full name matches one
+	// of these patterns (see Include).
+	Exclude []string
+
+	// PushedSince keeps only listed repositories pushed to on or after this
+	// date (YYYY-MM-DD or an RFC 3339 timestamp).
+	PushedSince string
+
+	// WaitOnRateLimit waits for the rate limit to reset when it is exhausted
+	// instead of failing with ErrAPIRateLimited.
+	WaitOnRateLimit bool
+
+	// Concurrency is the number of repositories processed at once in
+	// multi-repository runs. Values below 2 process them one at a time.
+	Concurrency int
+
+	// NoCache disables the on-disk cache of API responses.
+	NoCache bool
+
+	// CacheDir overrides the directory of the on-disk response cache.
+	CacheDir string
+
+	// API selects the backend for repository reads: "rest" (default) or
+	// "graphql", which reads repositories in bulk.
+	API string
+
+	// AuditLog overrides the file every setting change is recorded in;
+	// NoAuditLog disables recording.
+	AuditLog   string
+	NoAuditLog bool
+
+	// Checkpoint overrides the file multi-repository runs record their
+	// progress in; NoCheckpoint disables recording.
+	Checkpoint   string
+	NoCheckpoint bool
+
+	// Resume continues the run recorded in the checkpoint: repositories that
+	// succeeded are skipped and failed ones are retried.
+	Resume bool
 }
@@ -7,7 +7,9 @@ package interfaces_test
 
 import (
 	"context"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/josejulio/ghautodelete/pkg/interfaces"
 )
@@ -25,6 +27,10 @@ import (
 // - GetRepository(ctx context.Context, owner, name string) (IRepository, error)
 // - UpdateRepository(ctx context.Context, owner, name string, settings IRepositorySettings) error
 // - ValidateToken(ctx context.Context) (ITokenInfo, error)
+// - ListOrganizationRepositories(ctx context.Context, org string) ([]IRepository, error)
+// - ListUserRepositories(ctx context.Context, affiliation string) ([]IRepository, error)
+// - SearchRepositories(ctx context.Context, query string) ([]IRepository, int, error)
+// - ListEnterpriseOrganizations(ctx context.Context, slug string) ([]string, error)
 func TestIGitHubClientInterfaceExists(t *testing.T) {
 	// Arrange
 	var client interfaces.IGitHubClient
@@ -85,6 +91,9 @@ func TestIOutputWriterInterfaceExists(t *testing.T) {
 // The implementation should define IConfigService with:
 // - Configure(ctx context.Context, owner, name string, dryRun bool) (IConfigResult, error)
 // - CheckStatus(ctx context.Context, owner, name string) (IConfigResult, error)
+// - Disable(ctx context.Context, owner, name string, dryRun bool) (IConfigResult, error)
+// - Reconcile(ctx context.Context, owner, name string, desired IRepositorySettings, dryRun bool) ([]SettingChange, error)
+// - ValidateToken(ctx context.Context, write bool) (ITokenInfo, error)
 func TestIConfigServiceInterfaceExists(t *testing.T) {
 	// Arrange
 	var service interfaces.IConfigService
@@ -103,6 +112,10 @@ func TestIConfigServiceInterfaceExists(t *testing.T) {
 // - GetDefaultBranch() string
 // - GetDeleteBranchOnMerge() bool
 // - GetFullName() string
+// - IsArchived() bool
+// - IsFork() bool
+// - IsTemplate() bool
+// - IsPrivate() bool
 func TestIRepositoryInterfaceExists(t *testing.T) {
 	// Arrange
 	var repo interfaces.IRepository
@@ -117,6 +130,10 @@ func TestIRepositoryInterfaceExists(t *testing.T) {
 //
 // The implementation should define IRepositorySettings with:
 // - GetDeleteBranchOnMerge() bool
+// - HasDeleteBranchOnMerge() bool
+// - GetAllowSquashMerge(), GetAllowMergeCommit(), GetAllowRebaseMerge() *bool
+// - GetAllowAutoMerge(), GetAllowUpdateBranch() *bool
+// - GetSquashMergeCommitTitle(), GetSquashMergeCommitMessage() *string
 func TestIRepositorySettingsInterfaceExists(t *testing.T) {
 	// Arrange
 	var settings interfaces.IRepositorySettings
@@ -217,6 +234,22 @@ func (m *mockGitHubClient) ValidateToken(ctx context.Context) (interfaces.IToken
 	return nil, nil
 }
 
+func (m *mockGitHubClient) ListOrganizationRepositories(ctx context.Context, org string) ([]interfaces.IRepository, error) {
+	return nil, nil
+}
+
+func (m *mockGitHubClient) ListUserRepositories(ctx context.Context, affiliation string) ([]interfaces.IRepository, error) {
+	return nil, nil
+}
+
+func (m *mockGitHubClient) SearchRepositories(ctx context.Context, query string) ([]interfaces.IRepository, int, error) {
+	return nil, 0, nil
+}
+
+func (m *mockGitHubClient) ListEnterpriseOrganizations(ctx context.Context, slug string) ([]string, error) {
+	return nil, nil
+}
+
 // mockRepoParser implements IRepoParser for compile-time verification.
 type mockRepoParser struct{}
 
@@ -250,6 +283,18 @@ func (m *mockConfigService) CheckStatus(ctx context.Context, owner, name string)
 	return nil, nil
 }
 
+func (m *mockConfigService) Disable(ctx context.Context, owner, name string, dryRun bool) (interfaces.IConfigResult, error) {
+	return nil, nil
+}
+
+func (m *mockConfigService) Reconcile(ctx context.Context, owner, name string, desired interfaces.IRepositorySettings, dryRun bool) ([]interfaces.SettingChange, error) {
+	return nil, nil
+}
+
+func (m *mockConfigService) ValidateToken(ctx context.Context, write bool) (interfaces.ITokenInfo, error) {
+	return nil, nil
+}
+
 // mockRepository implements IRepository for compile-time verification.
 type mockRepository struct{}
 
@@ -258,11 +303,30 @@ func (m *mockRepository) GetName() string              { return "" }
 func (m *mockRepository) GetDefaultBranch() string     { return "" }
 func (m *mockRepository) GetDeleteBranchOnMerge() bool { return false }
 func (m *mockRepository) GetFullName() string          { return "" }
+func (m *mockRepository) IsArchived() bool             { return false }
+func (m *mockRepository) IsFork() bool                 { return false }
+func (m *mockRepository) IsTemplate() bool             { return false }
+func (m *mockRepository) IsPrivate() bool              { return false }
+func (m *mockRepository) GetVisibility() string        { return "" }
+func (m *mockRepository) GetTopics() []string          { return nil }
+func (m *mockRepository) GetLanguage() string          { return "" }
+func (m *mockRepository) GetPushedAt() time.Time       { return time.Time{} }
+func (m *mockRepository) GetSettings() interfaces.IRepositorySettings {
+	return &mockRepositorySettings{}
+}
 
 // mockRepositorySettings implements IRepositorySettings for compile-time verification.
 type mockRepositorySettings struct{}
 
-func (m *mockRepositorySettings) GetDeleteBranchOnMerge() bool { return false }
+func (m *mockRepositorySettings) GetDeleteBranchOnMerge() bool         { return false }
+func (m *mockRepositorySettings) HasDeleteBranchOnMerge() bool         { return true }
+func (m *mockRepositorySettings) GetAllowSquashMerge() *bool           { return nil }
+func (m *mockRepositorySettings) GetAllowMergeCommit() *bool           { return nil }
+func (m *mockRepositorySettings) GetAllowRebaseMerge() *bool           { return nil }
+func (m *mockRepositorySettings) GetAllowAutoMerge() *bool             { return nil }
+func (m *mockRepositorySettings) GetAllowUpdateBranch() *bool          { return nil }
+func (m *mockRepositorySettings) GetSquashMergeCommitTitle() *string   { return nil }
+func (m *mockRepositorySettings) GetSquashMergeCommitMessage() *string { return nil }
 
 // mockTokenInfo implements ITokenInfo for compile-time verification.
 type mockTokenInfo struct{}
@@ -649,7 +713,7 @@ func TestCLIOptionsZeroValue(t *testing.T) {
 		CheckOnly:  false,
 	}
 
-	if opts != expected {
+	if !reflect.DeepEqual(opts, expected) {
 		t.Errorf("CLIOptions zero value mismatch: expected %+v, got %+v", expected, opts)
 	}
 }